@@ -0,0 +1,167 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package analysis
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Integrates time ordered samples over time with the trapezoidal
+rule, in pressure unit·s, between start and end. Pressures at
+the bounds are interpolated linearly, and the integral is only
+taken where samples exist
+*/
+func Dose(samples []Sample, start time.Time, end time.Time) float64 {
+	var dose float64
+	for idx := 1; idx < len(samples); idx++ {
+		a, b := samples[idx-1], samples[idx]
+		from, to := maxTime(a.Time, start), minTime(b.Time, end)
+		if !from.Before(to) {
+			continue
+		}
+		span := b.Time.Sub(a.Time).Seconds()
+		at := func(t time.Time) float64 {
+			return a.Pressure + (b.Pressure-a.Pressure)*t.Sub(a.Time).Seconds()/span
+		}
+		dose += (at(from) + at(to)) / 2 * to.Sub(from).Seconds()
+	}
+	return dose
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+type ChannelDose struct {
+	Channel int
+	Label   string
+	Dose    float64       // Integral of the pressure, in unit·s
+	Peak    float64       // Highest pressure
+	Covered time.Duration // Time with valid readings
+	Samples int
+}
+
+type DoseSegment struct {
+	Marker   string // Marker that opened the segment
+	Start    time.Time
+	End      time.Time
+	Channels []ChannelDose // By channel
+}
+
+/*
+Integrates the pressure of every channel over time between
+user defined markers, e.g. to estimate the contamination
+exposure during a vent or for process QA records. Readings are
+integrated with the trapezoidal rule, and intervals where a
+channel has no valid reading are left out.
+
+It is safe for concurrent use
+*/
+type DoseIntegrator struct {
+	marker   string
+	start    time.Time
+	last     map[int]Sample // Last valid sample of each channel
+	channels map[int]*ChannelDose
+	mutex    sync.Mutex
+}
+
+/*
+Closes the current segment, if any, and opens a new one at the
+given time. Returns the closed segment
+*/
+func (d *DoseIntegrator) Mark(marker string, at time.Time) (DoseSegment, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	segment, ok := d.segment(at)
+	d.marker = marker
+	d.start = at
+	d.last = make(map[int]Sample)
+	d.channels = make(map[int]*ChannelDose)
+	return segment, ok
+}
+
+/*
+Closes the current segment at the given time. Readings are
+ignored until the next marker
+*/
+func (d *DoseIntegrator) Stop(at time.Time) (DoseSegment, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	segment, ok := d.segment(at)
+	d.channels = nil
+	return segment, ok
+}
+
+/*
+Adds readings taken at a time. Readings that are not OK break
+the integration of their channel until the next valid one
+*/
+func (d *DoseIntegrator) Add(at time.Time, readings []protocol.PressureReading) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.channels == nil {
+		return
+	}
+	for idx, reading := range readings {
+		channel := reading.Channel
+		if channel == 0 {
+			channel = idx + 1
+		}
+		entry, ok := d.channels[channel]
+		if !ok {
+			entry = &ChannelDose{Channel: channel}
+			d.channels[channel] = entry
+		}
+		entry.Label = reading.Label
+		if reading.Status != "OK" {
+			delete(d.last, channel)
+			continue
+		}
+
+		sample := Sample{Time: at, Pressure: reading.Value}
+		if previous, ok := d.last[channel]; ok && at.After(previous.Time) {
+			entry.Dose += Dose([]Sample{previous, sample}, previous.Time, at)
+			entry.Covered += at.Sub(previous.Time)
+		}
+		entry.Peak = max(entry.Peak, reading.Value)
+		entry.Samples++
+		d.last[channel] = sample
+	}
+}
+
+/*
+Returns the current segment. The caller must hold the mutex
+*/
+func (d *DoseIntegrator) segment(at time.Time) (DoseSegment, bool) {
+	if d.channels == nil {
+		return DoseSegment{}, false
+	}
+	segment := DoseSegment{Marker: d.marker, Start: d.start, End: at}
+	for _, channel := range slices.Sorted(maps.Keys(d.channels)) {
+		segment.Channels = append(segment.Channels, *d.channels[channel])
+	}
+	return segment, true
+}
@@ -0,0 +1,52 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/analysis"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestDose(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	samples := []analysis.Sample{
+		{Time: start, Pressure: 0},
+		{Time: start.Add(10 * time.Second), Pressure: 10},
+	}
+	if dose := analysis.Dose(samples, start, start.Add(10*time.Second)); math.Abs(dose-50) > 1e-9 {
+		t.Errorf("expected 50, got %v", dose)
+	}
+	// Second half, interpolated from 5 to 10
+	if dose := analysis.Dose(samples, start.Add(5*time.Second), start.Add(time.Hour)); math.Abs(dose-37.5) > 1e-9 {
+		t.Errorf("expected 37.5, got %v", dose)
+	}
+}
+
+func TestDoseIntegrator(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	ok := func(value float64) []protocol.PressureReading {
+		return []protocol.PressureReading{{Channel: 1, Value: value, Status: "OK"}}
+	}
+
+	var integrator analysis.DoseIntegrator
+	integrator.Add(start, ok(1))
+	if _, open := integrator.Mark("vent", start); open {
+		t.Error("expected no segment before the first marker")
+	}
+	integrator.Add(start, ok(1))
+	integrator.Add(start.Add(10*time.Second), ok(3))
+	integrator.Add(start.Add(20*time.Second), []protocol.PressureReading{{Channel: 1, Status: "OFF"}})
+	integrator.Add(start.Add(30*time.Second), ok(3))
+	integrator.Add(start.Add(40*time.Second), ok(3))
+
+	segment, _ := integrator.Stop(start.Add(40 * time.Second))
+	if segment.Marker != "vent" || len(segment.Channels) != 1 {
+		t.Fatalf("unexpected segment %+v", segment)
+	}
+	dose := segment.Channels[0]
+	if math.Abs(dose.Dose-50) > 1e-9 || dose.Covered != 20*time.Second || dose.Peak != 3 {
+		t.Errorf("unexpected dose %+v", dose)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package analysis
+
+import (
+	"math"
+	"time"
+)
+
+type Envelope struct {
+	Start time.Time // Start of the bucket
+	End   time.Time // End of the bucket
+	Min   float64
+	Max   float64
+	Mean  float64
+	Count int
+}
+
+/*
+Reduces time ordered samples to at most threshold points with
+the Largest-Triangle-Three-Buckets algorithm, which keeps the
+visual shape of the trend. The first and last samples are
+always kept.
+
+Areas are computed on the logarithm of the pressure, since
+vacuum trends are plotted on a log axis, unless a sample is
+not positive
+*/
+func LTTB(samples []Sample, threshold int) []Sample {
+	if threshold >= len(samples) || threshold < 3 {
+		return samples
+	}
+
+	logScale := true
+	for _, sample := range samples {
+		if sample.Pressure <= 0 {
+			logScale = false
+			break
+		}
+	}
+	x := func(idx int) float64 {
+		return samples[idx].Time.Sub(samples[0].Time).Seconds()
+	}
+	y := func(idx int) float64 {
+		if logScale {
+			return math.Log10(samples[idx].Pressure)
+		}
+		return samples[idx].Pressure
+	}
+
+	result := make([]Sample, 0, threshold)
+	result = append(result, samples[0])
+	size := float64(len(samples)-2) / float64(threshold-2)
+	selected := 0
+
+	for bucket := range threshold - 2 {
+		start := int(float64(bucket)*size) + 1
+		end := int(float64(bucket+1)*size) + 1
+
+		// Average of the next bucket, the last sample for the last bucket
+		nextStart, nextEnd := end, min(int(float64(bucket+2)*size)+1, len(samples))
+		if bucket == threshold-3 {
+			nextStart, nextEnd = len(samples)-1, len(samples)
+		}
+		var avgX, avgY float64
+		for idx := nextStart; idx < nextEnd; idx++ {
+			avgX += x(idx)
+			avgY += y(idx)
+		}
+		avgX /= float64(nextEnd - nextStart)
+		avgY /= float64(nextEnd - nextStart)
+
+		maxArea, maxIdx := -1.0, start
+		for idx := start; idx < end; idx++ {
+			area := math.Abs((x(selected)-avgX)*(y(idx)-y(selected)) - (x(selected)-x(idx))*(avgY-y(selected)))
+			if area > maxArea {
+				maxArea, maxIdx = area, idx
+			}
+		}
+		result = append(result, samples[maxIdx])
+		selected = maxIdx
+	}
+	return append(result, samples[len(samples)-1])
+}
+
+/*
+Splits time ordered samples in buckets of equal duration and
+returns the min, max and mean pressure of each non empty
+bucket, so spikes are never hidden by downsampling
+*/
+func MinMax(samples []Sample, buckets int) []Envelope {
+	if len(samples) == 0 || buckets < 1 {
+		return nil
+	}
+	first, last := samples[0].Time, samples[len(samples)-1].Time
+	width := last.Sub(first) / time.Duration(buckets)
+	if width <= 0 {
+		width, buckets = time.Nanosecond, 1
+	}
+
+	envelopes := make([]Envelope, 0, buckets)
+	var current *Envelope
+	for _, sample := range samples {
+		bucket := min(int(sample.Time.Sub(first)/width), buckets-1)
+		start := first.Add(time.Duration(bucket) * width)
+		if current == nil || !current.Start.Equal(start) {
+			envelopes = append(envelopes, Envelope{
+				Start: start,
+				End:   start.Add(width),
+				Min:   sample.Pressure,
+				Max:   sample.Pressure,
+			})
+			current = &envelopes[len(envelopes)-1]
+		}
+		current.Min = min(current.Min, sample.Pressure)
+		current.Max = max(current.Max, sample.Pressure)
+		current.Mean += sample.Pressure
+		current.Count++
+	}
+	for idx := range envelopes {
+		envelopes[idx].Mean /= float64(envelopes[idx].Count)
+	}
+	return envelopes
+}
@@ -0,0 +1,53 @@
+package analysis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/analysis"
+)
+
+func TestDownsample(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	samples := make([]analysis.Sample, 0, 1000)
+	for i := range 1000 {
+		pressure := 1e-7
+		if i == 500 {
+			pressure = 1e-4
+		}
+		samples = append(samples, analysis.Sample{
+			Time:     start.Add(time.Duration(i) * time.Second),
+			Pressure: pressure,
+		})
+	}
+
+	points := analysis.LTTB(samples, 50)
+	if len(points) != 50 {
+		t.Fatalf("expected 50 points, got %d", len(points))
+	}
+	if points[0] != samples[0] || points[49] != samples[999] {
+		t.Error("expected the first and last samples to be kept")
+	}
+	spike := false
+	for _, point := range points {
+		spike = spike || point.Pressure == 1e-4
+	}
+	if !spike {
+		t.Error("expected the pressure spike to be kept")
+	}
+
+	envelopes := analysis.MinMax(samples, 10)
+	if len(envelopes) != 10 {
+		t.Fatalf("expected 10 buckets, got %d", len(envelopes))
+	}
+	count := 0
+	for _, envelope := range envelopes {
+		count += envelope.Count
+	}
+	if count != 1000 {
+		t.Errorf("expected 1000 samples in the buckets, got %d", count)
+	}
+	if envelopes[5].Max != 1e-4 || envelopes[5].Min != 1e-7 {
+		t.Errorf("unexpected envelope %+v", envelopes[5])
+	}
+}
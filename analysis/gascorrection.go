@@ -0,0 +1,81 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package analysis
+
+import (
+	"errors"
+	"math"
+)
+
+// Temperature in Kelvin at which Pirani sensors are calibrated,
+// used when PiraniCompensation.CalibrationTemperature is zero
+const DefaultCalibrationTemperature = 296.15
+
+var ErrInvalidTemperature = errors.New("temperatures must be positive and below the wire temperature")
+
+/*
+Correction of Pirani and Convection Pirani readings for a gas
+type and a gas temperature other than the calibration one, e.g.
+for processes running hot chambers.
+
+The model assumes the molecular regime, where the heat carried
+away from the wire is proportional to p·ΔT/√T, ΔT being the
+difference between the wire and the gas temperatures and T the
+gas temperature, with a constant accommodation coefficient. It
+holds below about 1 Torr; at higher pressures the conduction no
+longer depends on the pressure and the correction is only an
+estimate. The gas factor must be the one of the gas at the
+calibration temperature, from the sensor documentation
+*/
+type PiraniCompensation struct {
+	GasFactor              float64 // True over indicated pressure, 1 for nitrogen and air
+	GasTemperature         float64 // Kelvin, the calibration temperature when zero
+	CalibrationTemperature float64 // Kelvin, DefaultCalibrationTemperature when zero
+	WireTemperature        float64 // Kelvin, zero when the wire is kept at a constant excess over the gas temperature
+}
+
+/*
+Returns the effective correction factor, the ratio of the true
+pressure over the indicated one
+*/
+func (c PiraniCompensation) Factor() (float64, error) {
+	gasFactor := c.GasFactor
+	if gasFactor == 0 {
+		gasFactor = 1
+	}
+	calibration := c.CalibrationTemperature
+	if calibration == 0 {
+		calibration = DefaultCalibrationTemperature
+	}
+	gas := c.GasTemperature
+	if gas == 0 {
+		gas = calibration
+	}
+	if gasFactor < 0 || gas < 0 || calibration < 0 {
+		return 0, ErrInvalidTemperature
+	}
+
+	factor := gasFactor * math.Sqrt(gas/calibration)
+	if c.WireTemperature != 0 {
+		if c.WireTemperature <= max(gas, calibration) {
+			return 0, ErrInvalidTemperature
+		}
+		factor *= (c.WireTemperature - calibration) / (c.WireTemperature - gas)
+	}
+	return factor, nil
+}
+
+/*
+Returns the true pressure of an indicated pressure
+*/
+func (c PiraniCompensation) Correct(pressure float64) (float64, error) {
+	factor, err := c.Factor()
+	if err != nil {
+		return 0, err
+	}
+	return pressure * factor, nil
+}
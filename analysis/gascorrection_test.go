@@ -0,0 +1,33 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/analysis"
+)
+
+func TestPiraniCompensation(t *testing.T) {
+	factor, err := analysis.PiraniCompensation{}.Factor()
+	if err != nil || factor != 1 {
+		t.Errorf("expected no correction, got %v (%v)", factor, err)
+	}
+
+	// Gas four times hotter than the calibration, wire at constant excess
+	compensation := analysis.PiraniCompensation{GasFactor: 1.5, GasTemperature: 400, CalibrationTemperature: 100}
+	if factor, _ := compensation.Factor(); math.Abs(factor-3) > 1e-12 {
+		t.Errorf("expected 3, got %v", factor)
+	}
+
+	// Fixed wire temperature, the excess halves
+	compensation = analysis.PiraniCompensation{GasTemperature: 400, CalibrationTemperature: 300, WireTemperature: 500}
+	want := math.Sqrt(400.0/300) * 2
+	if pressure, _ := compensation.Correct(1e-2); math.Abs(pressure-want*1e-2) > 1e-12 {
+		t.Errorf("expected %v, got %v", want*1e-2, pressure)
+	}
+
+	compensation.WireTemperature = 350
+	if _, err := compensation.Factor(); err == nil {
+		t.Error("expected an invalid temperature error")
+	}
+}
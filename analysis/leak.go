@@ -0,0 +1,120 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+var ErrNotEnoughSamples = errors.New("at least two samples with distinct times are required")
+
+type Sample struct {
+	Time     time.Time
+	Pressure float64
+}
+
+type LeakRate struct {
+	RiseRate      float64 // Pressure rise rate in unit per second
+	RiseRateError float64 // Standard error of the rise rate, 0 with two samples
+	LeakRate      float64 // Rise rate times volume, in unit·L/s
+	LeakRateError float64 // Standard error of the leak rate
+	Unit          string  // Pressure unit of the samples
+	Intercept     float64 // Fitted pressure at the first sample
+	R2            float64 // Coefficient of determination of the fit
+	Samples       int
+	Duration      time.Duration
+}
+
+func (l LeakRate) String() string {
+	return fmt.Sprintf(
+		"dP/dt = %.3E ± %.1E %s/s, leak rate = %.3E ± %.1E %s·L/s (R² = %.3f, %d samples over %s)",
+		l.RiseRate, l.RiseRateError, l.Unit, l.LeakRate, l.LeakRateError, l.Unit, l.R2, l.Samples, l.Duration,
+	)
+}
+
+/*
+Computes the leak rate of an isolated volume (in liters) from
+pressure samples taken during a rate-of-rise test. The rise
+rate is the slope of a least squares linear fit of pressure
+over time, with its standard error
+*/
+func RateOfRise(samples []Sample, volume float64, unit string) (LeakRate, error) {
+	result := LeakRate{Unit: unit, Samples: len(samples)}
+	if len(samples) < 2 {
+		return result, ErrNotEnoughSamples
+	}
+
+	start := samples[0].Time
+	var sumT, sumP, sumTT, sumTP float64
+	for _, sample := range samples {
+		t := sample.Time.Sub(start).Seconds()
+		sumT += t
+		sumP += sample.Pressure
+		sumTT += t * t
+		sumTP += t * sample.Pressure
+	}
+	n := float64(len(samples))
+	denominator := n*sumTT - sumT*sumT
+	if denominator == 0 {
+		return result, ErrNotEnoughSamples
+	}
+	slope := (n*sumTP - sumT*sumP) / denominator
+	intercept := (sumP - slope*sumT) / n
+
+	meanP := sumP / n
+	var residual, total float64
+	for _, sample := range samples {
+		t := sample.Time.Sub(start).Seconds()
+		fitted := intercept + slope*t
+		residual += (sample.Pressure - fitted) * (sample.Pressure - fitted)
+		total += (sample.Pressure - meanP) * (sample.Pressure - meanP)
+	}
+	result.R2 = 1
+	if total > 0 {
+		result.R2 = 1 - residual/total
+	}
+	if n > 2 {
+		result.RiseRateError = math.Sqrt(residual / (n - 2) / (sumTT - sumT*sumT/n))
+	}
+
+	result.RiseRate = slope
+	result.LeakRate = slope * volume
+	result.LeakRateError = result.RiseRateError * volume
+	result.Intercept = intercept
+	result.Duration = samples[len(samples)-1].Time.Sub(start)
+	return result, nil
+}
+
+/*
+Samples the pressure of a channel over a window. The volume
+must already be isolated from the pumps when this is called
+*/
+func RecordRise(device *protocol.MKS937B, channel int, window time.Duration, interval time.Duration) ([]Sample, error) {
+	var samples []Sample
+
+	deadline := time.Now().Add(window)
+	for {
+		reading, err := device.GetPressure(channel)
+		if err != nil {
+			return samples, err
+		}
+		if reading.Status != "OK" {
+			return samples, fmt.Errorf("channel %d reading is unavailable: %s", channel, reading.Status)
+		}
+		samples = append(samples, Sample{Time: time.Now(), Pressure: reading.Value})
+
+		if time.Now().Add(interval).After(deadline) {
+			return samples, nil
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,52 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/analysis"
+)
+
+func TestRateOfRise(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	samples := make([]analysis.Sample, 0, 10)
+	for i := range 10 {
+		samples = append(samples, analysis.Sample{
+			Time:     start.Add(time.Duration(i) * 10 * time.Second),
+			Pressure: 1e-6 + 2e-8*float64(i*10),
+		})
+	}
+
+	result, err := analysis.RateOfRise(samples, 50, "Torr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.RiseRate-2e-8) > 1e-12 {
+		t.Errorf("expected rise rate 2E-08, got %E", result.RiseRate)
+	}
+	if math.Abs(result.LeakRate-1e-6) > 1e-10 {
+		t.Errorf("expected leak rate 1E-06, got %E", result.LeakRate)
+	}
+	if result.R2 < 0.999 {
+		t.Errorf("expected a perfect fit, got R2 %f", result.R2)
+	}
+	if result.RiseRateError > 1e-12 || result.LeakRateError > 1e-10 {
+		t.Errorf("expected no uncertainty on a perfect fit, got %E and %E", result.RiseRateError, result.LeakRateError)
+	}
+
+	noisy := append([]analysis.Sample(nil), samples...)
+	noisy[3].Pressure += 5e-8
+	noisy[6].Pressure -= 5e-8
+	result, err = analysis.RateOfRise(noisy, 50, "Torr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RiseRateError <= 0 || math.Abs(result.LeakRateError-50*result.RiseRateError) > 1e-15 {
+		t.Errorf("unexpected uncertainties %E and %E", result.RiseRateError, result.LeakRateError)
+	}
+
+	if _, err := analysis.RateOfRise(samples[:1], 50, "Torr"); err == nil {
+		t.Error("expected an error with a single sample")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+/*
+Converts a binary frame log to text, one frame per line with
+its timestamp, direction and quoted content:
+
+	framelog [-json] bus.flog
+*/
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+)
+
+type jsonFrame struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Data      string    `json:"data"`
+}
+
+func main() {
+	asJSON := flag.Bool("json", false, "writes one JSON object per line")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: framelog [-json] <file>")
+		os.Exit(2)
+	}
+	if err := convert(flag.Arg(0), *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func convert(path string, asJSON bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := framelog.NewReader(file)
+	if err != nil {
+		return err
+	}
+	output := bufio.NewWriter(os.Stdout)
+	defer output.Flush()
+	encoder := json.NewEncoder(output)
+
+	for {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			err = encoder.Encode(jsonFrame{
+				Time:      frame.Time.UTC(),
+				Direction: frame.Direction.String(),
+				Data:      string(frame.Data),
+			})
+		} else {
+			_, err = fmt.Fprintf(output, "%s %s %q\n",
+				frame.Time.UTC().Format(time.RFC3339Nano), frame.Direction, frame.Data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
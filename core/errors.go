@@ -0,0 +1,45 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package core
+
+import "fmt"
+
+type ErrUnexpectedReply struct {
+	Sent string
+	Got  string
+}
+
+func NewErrUnexpectedReply(sent string, got string) *ErrUnexpectedReply {
+	return &ErrUnexpectedReply{
+		Sent: sent,
+		Got:  got,
+	}
+}
+func (e *ErrUnexpectedReply) Error() string {
+	return fmt.Sprintf(
+		"not expected response, sent %s got %s",
+		e.Sent, e.Got,
+	)
+}
+
+type ErrUnexpectedAddress struct {
+	Expected string
+	Got      string
+}
+
+func NewErrUnexpectedAddress(expected string, got string) *ErrUnexpectedAddress {
+	return &ErrUnexpectedAddress{
+		Expected: expected,
+		Got:      got,
+	}
+}
+func (e *ErrUnexpectedAddress) Error() string {
+	return fmt.Sprintf(
+		"invalid received address, expected %s got %s",
+		e.Expected, e.Got,
+	)
+}
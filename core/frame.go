@@ -0,0 +1,85 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+// End of every request and reply frame
+const Terminator = ";FF"
+
+var replyPattern = regexp.MustCompile(`@([0-9]+)(ACK|NAK)(.*?);FF`)
+
+/*
+Reply of a controller to a request frame
+*/
+type Reply struct {
+	Address string // Address of the answering controller, e.g. 001
+	Payload string // Parameter or value of the reply
+	Nak     bool
+}
+
+/*
+Builds the frame of a query, e.g. @001PR1?;FF
+*/
+func QueryFrame(address int, command string) string {
+	return fmt.Sprintf("@%03d%s?%s", address, command, Terminator)
+}
+
+/*
+Builds the frame of a setting, e.g. @001CP1!ON;FF
+*/
+func SetFrame(address int, command string, parameter string) string {
+	return fmt.Sprintf("@%03d%s!%s%s", address, command, parameter, Terminator)
+}
+
+/*
+Splits a command into its mnemonic and the trailing channel
+or relay number, which is 0 when the command has none
+*/
+func SplitCommand(command string) (string, int) {
+	mnemonic := strings.TrimRight(command, "0123456789")
+	channel, _ := strconv.Atoi(command[len(mnemonic):])
+	return mnemonic, channel
+}
+
+/*
+Writes a frame and reads the reply up to the terminator. The
+raw reply is returned with the read error, if any
+*/
+func Exchange(communication unicomm.Unicomm, message string) (string, error) {
+	if err := communication.Write([]byte(message)); err != nil {
+		return "", err
+	}
+	response, err := communication.ReadUntil(Terminator)
+	return string(response), err
+}
+
+/*
+Parses the reply to a frame sent to an address
+*/
+func ParseReply(sent string, address int, response string) (Reply, error) {
+	var reply Reply
+
+	matches := replyPattern.FindStringSubmatch(response)
+	if len(matches) < 4 {
+		return reply, NewErrUnexpectedReply(sent, response)
+	}
+	reply.Address = matches[1]
+	if expected := fmt.Sprintf("%03d", address); reply.Address != expected {
+		return reply, NewErrUnexpectedAddress(expected, reply.Address)
+	}
+	reply.Payload = matches[3]
+	reply.Nak = matches[2] == "NAK"
+	return reply, nil
+}
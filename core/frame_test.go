@@ -0,0 +1,34 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/core"
+)
+
+func TestFrames(t *testing.T) {
+	if frame := core.QueryFrame(1, "PR1"); frame != "@001PR1?;FF" {
+		t.Errorf("QueryFrame = %s", frame)
+	}
+	if frame := core.SetFrame(253, "CP1", "ON"); frame != "@253CP1!ON;FF" {
+		t.Errorf("SetFrame = %s", frame)
+	}
+
+	reply, err := core.ParseReply("@001PR1?;FF", 1, "@001ACK4.20E-08;FF")
+	if err != nil || reply.Payload != "4.20E-08" || reply.Nak {
+		t.Errorf("ParseReply = %+v, %v", reply, err)
+	}
+	reply, err = core.ParseReply("@001CP1!ON;FF", 1, "@001NAK160;FF")
+	if err != nil || reply.Payload != "160" || !reply.Nak {
+		t.Errorf("ParseReply = %+v, %v", reply, err)
+	}
+	var unexpectedAddress *core.ErrUnexpectedAddress
+	if _, err := core.ParseReply("@001PR1?;FF", 1, "@002ACK1.00E-03;FF"); !errors.As(err, &unexpectedAddress) {
+		t.Errorf("ParseReply from another address = %v", err)
+	}
+	var unexpectedReply *core.ErrUnexpectedReply
+	if _, err := core.ParseReply("@001PR1?;FF", 1, "garbage"); !errors.As(err, &unexpectedReply) {
+		t.Errorf("ParseReply of garbage = %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package datalogger
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+type Options struct {
+	Directory string          // Folder where the files are created
+	Prefix    string          // File name prefix, default is "mks937b"
+	Columns   []export.Column // Columns written on each row, default is all
+	Unit      string          // Value of the unit column
+	MaxSize   int64           // Rotates after this many uncompressed bytes, 0 disables
+	MaxAge    time.Duration   // Rotates after this period, 0 disables
+	Compress  bool            // Writes gzip compressed files
+}
+
+type DataLogger struct {
+	Options Options
+	OnError func(err error) // Errors reading the device in Run, which keeps polling
+
+	pollErrors atomic.Uint64
+	file       *os.File
+	gzip       *gzip.Writer
+	writer     *export.ReadingsCSVWriter
+	counter    *countingWriter
+	opened     time.Time
+	mutex      sync.Mutex
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.writer.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+/*
+Creates a new data logger that writes pressure readings to
+CSV files rotated by size and/or age.
+
+Parquet output is not supported since it would require a
+third party encoder
+*/
+func New(options Options) *DataLogger {
+	if options.Prefix == "" {
+		options.Prefix = "mks937b"
+	}
+	return &DataLogger{Options: options}
+}
+
+/*
+Writes one row per channel for a set of readings taken
+at the same timestamp. Channels are numbered from 1
+*/
+func (d *DataLogger) Write(timestamp time.Time, readings []protocol.PressureReading) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.shouldRotate(timestamp) {
+		if err := d.close(); err != nil {
+			return err
+		}
+	}
+	if d.file == nil {
+		if err := d.open(timestamp); err != nil {
+			return err
+		}
+	}
+	return d.writer.Write(timestamp, readings)
+}
+
+/*
+Polls all channel pressures at a fixed interval and writes
+them until the context is cancelled. Failed readings are
+skipped, counted by PollErrors and passed to OnError
+*/
+func (d *DataLogger) Run(ctx context.Context, device *protocol.MKS937B, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return d.Close()
+		case now := <-ticker.C:
+			readings, err := device.GetPressures()
+			if err != nil {
+				d.pollErrors.Add(1)
+				if d.OnError != nil {
+					d.OnError(err)
+				}
+				continue
+			}
+			if err := d.Write(now, readings); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+/*
+Returns the number of readings of Run that failed
+*/
+func (d *DataLogger) PollErrors() uint64 {
+	return d.pollErrors.Load()
+}
+
+/*
+Flushes and closes the current file
+*/
+func (d *DataLogger) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.close()
+}
+
+/*
+Returns true if the current file exceeded its size or age
+*/
+func (d *DataLogger) shouldRotate(timestamp time.Time) bool {
+	if d.file == nil {
+		return false
+	}
+	if d.Options.MaxSize > 0 && d.counter.count >= d.Options.MaxSize {
+		return true
+	}
+	if d.Options.MaxAge > 0 && timestamp.Sub(d.opened) >= d.Options.MaxAge {
+		return true
+	}
+	return false
+}
+
+/*
+Creates a new file named after the timestamp and writes
+the header row. An existing file is never overwritten: a
+sequence number is appended to the name instead
+*/
+func (d *DataLogger) open(timestamp time.Time) error {
+	if err := os.MkdirAll(d.Options.Directory, 0o755); err != nil {
+		return err
+	}
+	base := fmt.Sprintf("%s_%s", d.Options.Prefix, timestamp.UTC().Format("20060102T150405.000Z"))
+	extension := ".csv"
+	if d.Options.Compress {
+		extension += ".gz"
+	}
+	var file *os.File
+	for sequence := 0; file == nil; sequence++ {
+		name := base + extension
+		if sequence > 0 {
+			name = fmt.Sprintf("%s_%d%s", base, sequence, extension)
+		}
+		var err error
+		file, err = os.OpenFile(filepath.Join(d.Options.Directory, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+
+	d.file = file
+	d.opened = timestamp
+	if d.Options.Compress {
+		d.gzip = gzip.NewWriter(file)
+		d.counter = &countingWriter{writer: d.gzip}
+	} else {
+		d.counter = &countingWriter{writer: file}
+	}
+	d.writer = export.NewReadingsCSVWriter(d.counter, d.Options.Columns...)
+	d.writer.Unit = d.Options.Unit
+	return d.writer.WriteHeader()
+}
+
+/*
+Flushes all writers and closes the file. The file is released
+even if a flush fails, so the next write opens a new one, and
+the first error is returned
+*/
+func (d *DataLogger) close() error {
+	if d.file == nil {
+		return nil
+	}
+	file, compressor, writer := d.file, d.gzip, d.writer
+	d.file, d.gzip, d.writer, d.counter = nil, nil, nil, nil
+
+	err := writer.Flush()
+	if compressor != nil {
+		if closeErr := compressor.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,140 @@
+package datalogger_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/datalogger"
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestRotation(t *testing.T) {
+	directory := t.TempDir()
+	logger := datalogger.New(datalogger.Options{
+		Directory: directory,
+		MaxAge:    time.Minute,
+	})
+
+	readings := []protocol.PressureReading{
+		{Value: 7.6e2, Status: "OK"},
+		{Value: 0, Status: "Pressure lower than minimum"},
+	}
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if err := logger.Write(start.Add(time.Duration(i)*40*time.Second), readings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expected 2 files, got %d", len(files))
+	}
+}
+
+func TestRotationSameTimestamp(t *testing.T) {
+	directory := t.TempDir()
+	logger := datalogger.New(datalogger.Options{Directory: directory, MaxSize: 1})
+
+	timestamp := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	existing := filepath.Join(directory, "mks937b_20261017T120000.000Z.csv")
+	if err := os.WriteFile(existing, []byte("kept"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	readings := []protocol.PressureReading{{Value: 7.6e2, Status: "OK"}}
+	for range 3 {
+		if err := logger.Write(timestamp, readings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 {
+		t.Errorf("expected 4 files, got %d", len(files))
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "kept" {
+		t.Errorf("expected the existing file to be kept, got %q, %v", data, err)
+	}
+}
+
+func TestRunPollErrors(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := framelog.NewWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// The replay has no frames, so every reading fails
+	replay, err := framelog.NewReplay(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := &protocol.MKS937B{Communication: replay, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := datalogger.New(datalogger.Options{Directory: t.TempDir()})
+	var reported int
+	logger.OnError = func(err error) { reported++ }
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := logger.Run(ctx, device, 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if logger.PollErrors() == 0 || uint64(reported) != logger.PollErrors() {
+		t.Errorf("expected the failed readings to be counted and reported, got %d and %d", logger.PollErrors(), reported)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	history := datalogger.NewHistory(3, 0)
+	start := time.Date(2026, time.October, 17, 8, 0, 0, 0, time.UTC)
+	for idx := range 5 {
+		history.Write(start.Add(time.Duration(idx)*time.Minute), []protocol.PressureReading{
+			{Channel: 1, Value: float64(idx), Status: "OK"},
+			{Channel: 3, Value: float64(10 * idx), Status: "OK"},
+		})
+	}
+
+	entries := history.Query(1, start.Add(3*time.Minute), time.Time{})
+	if len(entries) != 2 || entries[0].Reading.Value != 3 || entries[1].Reading.Value != 4 {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+	if entries := history.Query(1, time.Time{}, time.Time{}); len(entries) != 3 || entries[0].Reading.Value != 2 {
+		t.Errorf("expected the last 3 readings, got %+v", entries)
+	}
+	if latest, ok := history.Latest(3); !ok || latest.Reading.Value != 40 {
+		t.Errorf("unexpected latest %+v", latest)
+	}
+	if latest := history.LatestAll(); len(latest) != 2 || latest[0].Reading.Channel != 1 {
+		t.Errorf("unexpected latest readings %+v", latest)
+	}
+
+	aged := datalogger.NewHistory(10, 90*time.Second)
+	for idx := range 5 {
+		aged.Write(start.Add(time.Duration(idx)*time.Minute), []protocol.PressureReading{{Channel: 1, Status: "OK"}})
+	}
+	if entries := aged.Query(1, time.Time{}, time.Time{}); len(entries) != 2 {
+		t.Errorf("expected readings of the last 90 s, got %d", len(entries))
+	}
+}
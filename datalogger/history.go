@@ -0,0 +1,188 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package datalogger
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+type Entry struct {
+	Time    time.Time                `json:"time"`
+	Reading protocol.PressureReading `json:"reading"`
+}
+
+/*
+Ring of the entries of a channel, oldest first from start
+*/
+type ring struct {
+	entries []Entry
+	start   int
+	count   int
+}
+
+func (r *ring) at(idx int) Entry {
+	return r.entries[(r.start+idx)%len(r.entries)]
+}
+
+func (r *ring) push(entry Entry) {
+	if r.count < len(r.entries) {
+		r.entries[(r.start+r.count)%len(r.entries)] = entry
+		r.count++
+		return
+	}
+	r.entries[r.start] = entry
+	r.start = (r.start + 1) % len(r.entries)
+}
+
+/*
+Drops the entries older than a time
+*/
+func (r *ring) trim(before time.Time) {
+	for r.count > 0 && r.at(0).Time.Before(before) {
+		r.start = (r.start + 1) % len(r.entries)
+		r.count--
+	}
+}
+
+/*
+In-memory history of the recent readings of each channel, so
+short-term trends can be served without an external database.
+Each channel keeps its last Capacity readings, no older than
+MaxAge from its latest one when MaxAge is set. Readings must be
+written in time order, older ones are dropped.
+
+It is safe for concurrent use
+*/
+type History struct {
+	Capacity int           // Readings kept per channel
+	MaxAge   time.Duration // Age of the oldest reading kept, 0 disables
+
+	channels map[int]*ring
+	mutex    sync.RWMutex
+}
+
+/*
+Creates a new history keeping the last readings of each
+channel
+*/
+func NewHistory(capacity int, maxAge time.Duration) *History {
+	return &History{
+		Capacity: max(capacity, 1),
+		MaxAge:   maxAge,
+		channels: make(map[int]*ring),
+	}
+}
+
+/*
+Adds a set of readings taken at the same timestamp, as
+DataLogger.Write. Channels are numbered from 1 when the
+readings have none
+*/
+func (h *History) Write(timestamp time.Time, readings []protocol.PressureReading) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.channels == nil {
+		h.channels = make(map[int]*ring)
+	}
+	for idx, reading := range readings {
+		channel := reading.Channel
+		if channel == 0 {
+			channel = idx + 1
+		}
+		r, ok := h.channels[channel]
+		if !ok {
+			r = &ring{entries: make([]Entry, max(h.Capacity, 1))}
+			h.channels[channel] = r
+		}
+		if r.count > 0 && timestamp.Before(r.at(r.count-1).Time) {
+			continue
+		}
+		r.push(Entry{Time: timestamp, Reading: reading})
+		if h.MaxAge > 0 {
+			r.trim(timestamp.Add(-h.MaxAge))
+		}
+	}
+	return nil
+}
+
+/*
+Returns the readings of a channel taken from one time to
+another, both included, oldest first. A zero time leaves the
+range open on its side
+*/
+func (h *History) Query(channel int, from time.Time, to time.Time) []Entry {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	r, ok := h.channels[channel]
+	if !ok {
+		return nil
+	}
+	first := 0
+	if !from.IsZero() {
+		first = sort.Search(r.count, func(idx int) bool { return !r.at(idx).Time.Before(from) })
+	}
+	last := r.count
+	if !to.IsZero() {
+		last = sort.Search(r.count, func(idx int) bool { return r.at(idx).Time.After(to) })
+	}
+	var entries []Entry
+	for idx := first; idx < last; idx++ {
+		entries = append(entries, r.at(idx))
+	}
+	return entries
+}
+
+/*
+Returns the latest reading of a channel
+*/
+func (h *History) Latest(channel int) (Entry, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	r, ok := h.channels[channel]
+	if !ok || r.count == 0 {
+		return Entry{}, false
+	}
+	return r.at(r.count - 1), true
+}
+
+/*
+Returns the latest reading of every channel, by channel
+*/
+func (h *History) LatestAll() []Entry {
+	var entries []Entry
+	for _, channel := range h.Channels() {
+		if entry, ok := h.Latest(channel); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+/*
+Returns the channels with readings, in ascending order
+*/
+func (h *History) Channels() []int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	channels := make([]int, 0, len(h.channels))
+	for channel, r := range h.channels {
+		if r.count > 0 {
+			channels = append(channels, channel)
+		}
+	}
+	slices.Sort(channels)
+	return channels
+}
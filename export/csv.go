@@ -0,0 +1,128 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+type Column string
+
+const (
+	ColumnTimestamp Column = "timestamp"
+	ColumnChannel   Column = "channel"
+	ColumnLabel     Column = "label"
+	ColumnValue     Column = "value"
+	ColumnUnit      Column = "unit"
+	ColumnStatus    Column = "status"
+	ColumnGauge     Column = "gauge"     // Facility gauge name, see protocol.GaugeMap
+	ColumnEquipment Column = "equipment" // Equipment ID of the gauge
+)
+
+var DefaultColumns = []Column{
+	ColumnTimestamp, ColumnChannel, ColumnLabel, ColumnValue, ColumnUnit, ColumnStatus,
+}
+
+/*
+Streams pressure readings as CSV rows, one row per channel.
+
+Numbers are always written with a dot as decimal separator and
+in scientific notation, regardless of the system locale
+*/
+type ReadingsCSVWriter struct {
+	Columns   []Column
+	Unit      string // Value of the unit column
+	Precision int    // Digits after the decimal point of values
+
+	writer *csv.Writer
+	header bool
+}
+
+/*
+Creates a new CSV writer with the selected columns, or with
+the default columns if none is given
+*/
+func NewReadingsCSVWriter(writer io.Writer, columns ...Column) *ReadingsCSVWriter {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	return &ReadingsCSVWriter{
+		Columns:   columns,
+		Precision: 3,
+		writer:    csv.NewWriter(writer),
+	}
+}
+
+/*
+Writes the header row. It is written automatically before
+the first readings if not called
+*/
+func (c *ReadingsCSVWriter) WriteHeader() error {
+	header := make([]string, len(c.Columns))
+	for idx, column := range c.Columns {
+		header[idx] = string(column)
+	}
+	c.header = true
+	return c.writer.Write(header)
+}
+
+/*
+Writes one row per reading with the given timestamp and flushes
+them to the underlying writer. Readings without channel are
+numbered by their position, starting at 1
+*/
+func (c *ReadingsCSVWriter) Write(timestamp time.Time, readings []protocol.PressureReading) error {
+	if !c.header {
+		if err := c.WriteHeader(); err != nil {
+			return err
+		}
+	}
+	for idx, reading := range readings {
+		row := make([]string, len(c.Columns))
+		for col, column := range c.Columns {
+			switch column {
+			case ColumnTimestamp:
+				row[col] = timestamp.Format(time.RFC3339Nano)
+			case ColumnChannel:
+				channel := reading.Channel
+				if channel == 0 {
+					channel = idx + 1
+				}
+				row[col] = strconv.Itoa(channel)
+			case ColumnLabel:
+				row[col] = reading.Label
+			case ColumnValue:
+				row[col] = strconv.FormatFloat(reading.Value, 'E', c.Precision, 64)
+			case ColumnUnit:
+				row[col] = c.Unit
+			case ColumnStatus:
+				row[col] = reading.Status
+			case ColumnGauge:
+				row[col] = reading.Gauge
+			case ColumnEquipment:
+				row[col] = reading.Equipment
+			}
+		}
+		if err := c.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return c.Flush()
+}
+
+/*
+Flushes buffered rows to the underlying writer
+*/
+func (c *ReadingsCSVWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
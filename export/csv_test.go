@@ -0,0 +1,53 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestReadingsCSVWriter(t *testing.T) {
+	var output strings.Builder
+	writer := export.NewReadingsCSVWriter(&output)
+	writer.Unit = "Torr"
+	readings := []protocol.PressureReading{
+		{Channel: 1, Label: "Chamber, main", Value: 1.234e-7, Status: "OK"},
+		{Label: "Foreline", Value: 0, Status: "Sensor misconnected"},
+	}
+	timestamp := time.Date(2026, 10, 17, 12, 0, 0, 500000000, time.UTC)
+	if err := writer.Write(timestamp, readings); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(timestamp.Add(time.Second), readings[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The header is written once, labels are quoted and channels
+	// default to the position of the reading
+	expected := "timestamp,channel,label,value,unit,status\n" +
+		"2026-10-17T12:00:00.5Z,1,\"Chamber, main\",1.234E-07,Torr,OK\n" +
+		"2026-10-17T12:00:00.5Z,2,Foreline,0.000E+00,Torr,Sensor misconnected\n" +
+		"2026-10-17T12:00:01.5Z,1,\"Chamber, main\",1.234E-07,Torr,OK\n"
+	if output.String() != expected {
+		t.Errorf("expected %q, got %q", expected, output.String())
+	}
+}
+
+func TestReadingsCSVWriterColumns(t *testing.T) {
+	var output strings.Builder
+	writer := export.NewReadingsCSVWriter(&output, export.ColumnGauge, export.ColumnEquipment, export.ColumnValue)
+	writer.Precision = 1
+	readings := []protocol.PressureReading{
+		{Channel: 3, Gauge: "VGC-BC1-01", Equipment: "EQ-20431", Value: 2.5e-9, Status: "OK"},
+	}
+	if err := writer.Write(time.Now(), readings); err != nil {
+		t.Fatal(err)
+	}
+	expected := "gauge,equipment,value\nVGC-BC1-01,EQ-20431,2.5E-09\n"
+	if output.String() != expected {
+		t.Errorf("expected %q, got %q", expected, output.String())
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+type ndjsonReading struct {
+	Type      string            `json:"type"`
+	Time      time.Time         `json:"time"`
+	Device    string            `json:"device,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Channel   int               `json:"channel"`
+	Label     string            `json:"label,omitempty"`
+	Gauge     string            `json:"gauge,omitempty"`
+	Equipment string            `json:"equipment,omitempty"`
+	Value     *float64          `json:"value"` // Null when the status is not OK
+	Unit      string            `json:"unit,omitempty"`
+	Status    string            `json:"status"`
+
+	Sensor      string  `json:"sensor,omitempty"`
+	Uncertainty float64 `json:"uncertainty,omitempty"`
+	OutOfRange  bool    `json:"out_of_range,omitempty"`
+}
+
+type ndjsonEvent struct {
+	Type     string            `json:"type"`
+	Time     time.Time         `json:"time"`
+	Device   string            `json:"device,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Event    string            `json:"event"`
+	Data     any               `json:"data,omitempty"`
+}
+
+/*
+Streams readings and events as newline delimited JSON, one
+self-describing object per line with a "type" field of
+"reading" or "event", suited to jq, Vector or Loki.
+
+It is safe for concurrent use
+*/
+type NDJSONWriter struct {
+	Device   string            // Identifier of the device added to every line
+	Metadata map[string]string // Facility metadata added to every line, see protocol.Metadata.Labels
+	Unit     string            // Unit added to readings
+
+	encoder *json.Encoder
+	mutex   sync.Mutex
+}
+
+/*
+Creates a new NDJSON writer for a device
+*/
+func NewNDJSONWriter(writer io.Writer, device string) *NDJSONWriter {
+	return &NDJSONWriter{Device: device, encoder: json.NewEncoder(writer)}
+}
+
+/*
+Writes one line per reading with the given timestamp. Readings
+without channel are numbered by their position, starting at 1
+*/
+func (n *NDJSONWriter) WriteReadings(timestamp time.Time, readings []protocol.PressureReading) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for idx, reading := range readings {
+		line := ndjsonReading{
+			Type:      "reading",
+			Time:      timestamp,
+			Device:    n.Device,
+			Metadata:  n.Metadata,
+			Channel:   reading.Channel,
+			Label:     reading.Label,
+			Gauge:     reading.Gauge,
+			Equipment: reading.Equipment,
+			Unit:      n.Unit,
+			Status:    reading.Status,
+
+			Sensor:      reading.Sensor,
+			Uncertainty: reading.Uncertainty,
+			OutOfRange:  reading.OutOfRange,
+		}
+		if line.Channel == 0 {
+			line.Channel = idx + 1
+		}
+		if reading.Status == "OK" {
+			line.Value = &reading.Value
+		}
+		if err := n.encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Writes an event line. Data is encoded as JSON and should be
+made of exported fields; error values must be converted to
+strings beforehand
+*/
+func (n *NDJSONWriter) WriteEvent(timestamp time.Time, event string, data any) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.encoder.Encode(ndjsonEvent{
+		Type:     "event",
+		Time:     timestamp,
+		Device:   n.Device,
+		Metadata: n.Metadata,
+		Event:    event,
+		Data:     data,
+	})
+}
@@ -0,0 +1,45 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	var output strings.Builder
+	writer := export.NewNDJSONWriter(&output, "sector1")
+	writer.Unit = "Torr"
+	writer.Metadata = map[string]string{"rack": "R3"}
+	readings := []protocol.PressureReading{
+		{Channel: 1, Label: "Chamber", Gauge: "VGC-BC1-01", Value: 1.5e-7, Status: "OK", Sensor: "CC"},
+		{Value: 0, Status: "Sensor misconnected"},
+	}
+	timestamp := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	if err := writer.WriteReadings(timestamp, readings); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteEvent(timestamp, "relay_activated", map[string]int{"relay": 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Values are null unless the status is OK, and channels default
+	// to the position of the reading
+	expected := []string{
+		`{"type":"reading","time":"2026-10-17T12:00:00Z","device":"sector1","metadata":{"rack":"R3"},"channel":1,"label":"Chamber","gauge":"VGC-BC1-01","value":1.5e-7,"unit":"Torr","status":"OK","sensor":"CC"}`,
+		`{"type":"reading","time":"2026-10-17T12:00:00Z","device":"sector1","metadata":{"rack":"R3"},"channel":2,"value":null,"unit":"Torr","status":"Sensor misconnected"}`,
+		`{"type":"event","time":"2026-10-17T12:00:00Z","device":"sector1","metadata":{"rack":"R3"},"event":"relay_activated","data":{"relay":3}}`,
+	}
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), output.String())
+	}
+	for idx, line := range lines {
+		if line != expected[idx] {
+			t.Errorf("line %d: expected %s, got %s", idx+1, expected[idx], line)
+		}
+	}
+}
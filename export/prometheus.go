@@ -0,0 +1,175 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Metrics of a device: its driver stats, its facility metadata
+added as labels to every series, and its last readings, if any,
+exported as pressures labeled with their channel and facility
+gauge
+*/
+type PrometheusDevice struct {
+	Stats    protocol.Stats
+	Metadata protocol.Metadata
+	Readings []protocol.PressureReading
+}
+
+/*
+Writes the driver stats of several devices, by device name, in
+the Prometheus text exposition format, without depending on
+the Prometheus client library. Latencies are exported as
+histograms per device and command mnemonic
+*/
+func WritePrometheus(writer io.Writer, devices map[string]protocol.Stats) error {
+	metrics := make(map[string]PrometheusDevice, len(devices))
+	for name, stats := range devices {
+		metrics[name] = PrometheusDevice{Stats: stats}
+	}
+	return WritePrometheusDevices(writer, metrics)
+}
+
+/*
+Writes the metrics of several devices, by device name, like
+WritePrometheus. Every series of a device is labeled with its
+metadata (location, sector, rack, contact and extra fields),
+and the valid readings are exported as mks937b_pressure with
+their channel, gauge and equipment
+*/
+func WritePrometheusDevices(writer io.Writer, devices map[string]PrometheusDevice) error {
+	w := bufio.NewWriter(writer)
+	names := slices.Sorted(maps.Keys(devices))
+	labels := make(map[string]string, len(devices))
+	for _, name := range names {
+		labels[name] = deviceLabels(name, devices[name].Metadata)
+	}
+
+	counters := []struct {
+		name, help string
+		value      func(stats protocol.Stats) uint64
+	}{
+		{"mks937b_commands_total", "Commands sent to the device.", func(s protocol.Stats) uint64 { return s.Commands }},
+		{"mks937b_errors_total", "Commands that failed.", func(s protocol.Stats) uint64 { return s.Errors }},
+		{"mks937b_timeouts_total", "Commands that timed out.", func(s protocol.Stats) uint64 { return s.Timeouts }},
+		{"mks937b_parse_failures_total", "Replies that could not be parsed or did not match.", func(s protocol.Stats) uint64 { return s.ParseFailures }},
+		{"mks937b_naks_total", "Commands answered with NAK.", func(s protocol.Stats) uint64 { return s.NAKs }},
+		{"mks937b_reconnects_total", "Connections after the first one.", func(s protocol.Stats) uint64 { return s.Reconnects }},
+		{"mks937b_reconnect_attempts_total", "Connection attempts after the first connection, including failed ones.", func(s protocol.Stats) uint64 { return s.ReconnectAttempts }},
+	}
+	for _, counter := range counters {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", counter.name, counter.help, counter.name)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s{%s} %d\n", counter.name, labels[name], counter.value(devices[name].Stats))
+		}
+	}
+
+	gauges := []struct {
+		name, help string
+		value      func(stats protocol.Stats) float64
+	}{
+		{"mks937b_uptime_seconds", "Duration of the current connection, 0 while disconnected.", func(s protocol.Stats) float64 { return s.Uptime.Seconds() }},
+		{"mks937b_last_recovery_seconds", "Time to recover of the last reconnect.", func(s protocol.Stats) float64 { return s.LastRecovery.Seconds() }},
+		{"mks937b_max_recovery_seconds", "Longest time to recover of a reconnect.", func(s protocol.Stats) float64 { return s.MaxRecovery.Seconds() }},
+	}
+	for _, gauge := range gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", gauge.name, gauge.help, gauge.name)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s{%s} %g\n", gauge.name, labels[name], gauge.value(devices[name].Stats))
+		}
+	}
+
+	metric := "mks937b_command_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Latency of the commands by mnemonic.\n# TYPE %s histogram\n", metric, metric)
+	for _, name := range names {
+		perCommand := devices[name].Stats.PerCommand
+		for _, command := range slices.Sorted(maps.Keys(perCommand)) {
+			stats := perCommand[command]
+			labels := fmt.Sprintf("%s,command=%s", labels[name], quoteLabel(command))
+			var cumulative uint64
+			for idx, bound := range protocol.LatencyBuckets {
+				cumulative += stats.Histogram[idx]
+				le := strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)
+				fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", metric, labels, le, cumulative)
+			}
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", metric, labels, stats.Count)
+			fmt.Fprintf(w, "%s_sum{%s} %g\n", metric, labels, stats.Total.Seconds())
+			fmt.Fprintf(w, "%s_count{%s} %d\n", metric, labels, stats.Count)
+		}
+	}
+
+	metric = "mks937b_pressure"
+	header := false
+	for _, name := range names {
+		for _, reading := range devices[name].Readings {
+			if reading.Status != "OK" {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(w, "# HELP %s Last valid pressure of the channel, in the unit of the readings.\n# TYPE %s gauge\n", metric, metric)
+				header = true
+			}
+			labels := fmt.Sprintf("%s,channel=\"%d\"", labels[name], reading.Channel)
+			if reading.Gauge != "" {
+				labels += ",gauge=" + quoteLabel(reading.Gauge)
+			}
+			if reading.Equipment != "" {
+				labels += ",equipment=" + quoteLabel(reading.Equipment)
+			}
+			fmt.Fprintf(w, "%s{%s} %g\n", metric, labels, reading.Value)
+		}
+	}
+	return w.Flush()
+}
+
+/*
+Returns the labels of a device: its name and the metadata other
+than the name, sorted, with the keys made valid label names
+*/
+func deviceLabels(name string, metadata protocol.Metadata) string {
+	pairs := []string{"device=" + quoteLabel(name)}
+	fields := metadata.Labels()
+	delete(fields, "name")
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		pairs = append(pairs, labelName(key)+"="+quoteLabel(fields[key]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+/*
+Replaces the characters not allowed in a label name by
+underscores, e.g. for the extra metadata keys
+*/
+func labelName(key string) string {
+	name := []byte(key)
+	for idx, char := range name {
+		letter := char == '_' || 'a' <= char && char <= 'z' || 'A' <= char && char <= 'Z'
+		digit := idx > 0 && '0' <= char && char <= '9'
+		if !letter && !digit {
+			name[idx] = '_'
+		}
+	}
+	return string(name)
+}
+
+/*
+Quotes a label value escaping backslashes, quotes and newlines
+*/
+func quoteLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
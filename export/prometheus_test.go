@@ -0,0 +1,52 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestWritePrometheusDevices(t *testing.T) {
+	var stats protocol.Stats
+	stats.Commands = 12
+	stats.PerCommand = map[string]protocol.CommandStats{
+		"PR1": {Count: 2, Total: 40 * time.Millisecond},
+	}
+	devices := map[string]export.PrometheusDevice{
+		"sector1": {
+			Stats: stats,
+			Metadata: protocol.Metadata{
+				Name:     "sector1",
+				Location: "Building 2",
+				Sector:   "S01",
+				Extra:    map[string]string{"cost-center": "VAC"},
+			},
+			Readings: []protocol.PressureReading{
+				{Channel: 1, Gauge: "VGC-BC1-01", Equipment: "EQ-20431", Value: 1.5e-07, Status: "OK"},
+				{Channel: 2, Status: "Sensor misconnected"},
+			},
+		},
+	}
+	var output strings.Builder
+	if err := export.WritePrometheusDevices(&output, devices); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := `device="sector1",cost_center="VAC",location="Building 2",sector="S01"`
+	expected := []string{
+		`mks937b_commands_total{` + labels + `} 12`,
+		`mks937b_command_duration_seconds_count{` + labels + `,command="PR1"} 2`,
+		`mks937b_pressure{` + labels + `,channel="1",gauge="VGC-BC1-01",equipment="EQ-20431"} 1.5e-07`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output.String(), line+"\n") {
+			t.Errorf("expected %q in\n%s", line, output.String())
+		}
+	}
+	if strings.Contains(output.String(), `channel="2"`) {
+		t.Error("expected the invalid reading to be left out")
+	}
+}
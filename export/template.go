@@ -0,0 +1,105 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// Default template of readings, one line per channel
+const DefaultReadingsTemplate = `{{range .Readings}}{{$.Time.Format "2006-01-02 15:04:05"}} ` +
+	`CH{{.Channel}}{{if .Label}} ({{.Label}}){{end}}: ` +
+	`{{if eq .Status "OK"}}{{sci .Value 2}} {{$.Unit}}{{else}}{{.Status}}{{end}}
+{{end}}`
+
+/*
+Data handed to the template when formatting readings
+*/
+type ReadingsData struct {
+	Time     time.Time
+	Unit     string
+	Readings []protocol.PressureReading
+}
+
+/*
+Formats readings and snapshots with a user defined text/template,
+so output can be customized without changing code. Besides the
+builtin functions, templates can use:
+  - sci value digits: value in scientific notation, e.g. 1.23E-07
+  - fixed value digits: value in decimal notation
+  - pretty value digits: value with significant digits for
+    operators, e.g. 5.2×10⁻⁷
+  - upper, lower: changes the case of a string
+  - rfc3339 time: formats a time as RFC 3339
+*/
+type TemplateFormatter struct {
+	Unit string // Unit handed to readings templates
+
+	template *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"sci": func(value float64, digits int) string {
+		return strconv.FormatFloat(value, 'E', digits, 64)
+	},
+	"fixed": func(value float64, digits int) string {
+		return strconv.FormatFloat(value, 'f', digits, 64)
+	},
+	"pretty": protocol.FormatPretty,
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"rfc3339": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+}
+
+/*
+Parses a template, DefaultReadingsTemplate is used if text is empty
+*/
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	if text == "" {
+		text = DefaultReadingsTemplate
+	}
+	parsed, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateFormatter{template: parsed}, nil
+}
+
+/*
+Executes the template with any data and writes the result
+*/
+func (t *TemplateFormatter) Execute(writer io.Writer, data any) error {
+	return t.template.Execute(writer, data)
+}
+
+/*
+Formats readings taken at the same timestamp. The template
+receives a ReadingsData
+*/
+func (t *TemplateFormatter) FormatReadings(timestamp time.Time, readings []protocol.PressureReading) (string, error) {
+	var b strings.Builder
+	err := t.Execute(&b, ReadingsData{Time: timestamp, Unit: t.Unit, Readings: readings})
+	return b.String(), err
+}
+
+/*
+Formats a status snapshot. The template receives the
+protocol.Snapshot
+*/
+func (t *TemplateFormatter) FormatSnapshot(snapshot protocol.Snapshot) (string, error) {
+	var b strings.Builder
+	err := t.Execute(&b, snapshot)
+	return b.String(), err
+}
@@ -0,0 +1,31 @@
+package export_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	formatter, err := export.NewTemplateFormatter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatter.Unit = "Torr"
+	readings := []protocol.PressureReading{
+		{Channel: 1, Label: "Chamber", Value: 1.234e-7, Status: "OK"},
+		{Channel: 2, Status: "Combination disabled"},
+	}
+	timestamp := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	output, err := formatter.FormatReadings(timestamp, readings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "2026-10-17 12:00:00 CH1 (Chamber): 1.23E-07 Torr\n" +
+		"2026-10-17 12:00:00 CH2: Combination disabled\n"
+	if output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Critical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	}
+	return "critical"
+}
+
+type AlarmEventKind int
+
+const (
+	AlarmRaised AlarmEventKind = iota
+	AlarmEscalated
+	AlarmCleared
+	AlarmAcknowledged
+)
+
+func (k AlarmEventKind) String() string {
+	names := []string{"raised", "escalated", "cleared", "acknowledged"}
+	if k < 0 || int(k) >= len(names) {
+		return "unknown"
+	}
+	return names[k]
+}
+
+type Alarm struct {
+	Key          string            `json:"key"` // Device and source, unique per alarm
+	Device       string            `json:"device"`
+	Source       string            `json:"source"`              // e.g. "communication", "channel 1", "relay 3"
+	Gauge        string            `json:"gauge,omitempty"`     // Facility gauge of channel alarms, see SetGaugeMap
+	Equipment    string            `json:"equipment,omitempty"` // Equipment ID of the gauge
+	Metadata     protocol.Metadata `json:"metadata,omitzero"`   // Facility metadata of the device, see SetMetadata
+	Severity     Severity          `json:"severity"`
+	Message      string            `json:"message"`
+	Raised       time.Time         `json:"raised"`
+	LastSeen     time.Time         `json:"last_seen"`
+	Count        int               `json:"count"` // Number of raises merged in the alarm
+	Active       bool              `json:"active"`
+	Acknowledged bool              `json:"acknowledged"`
+	AckBy        string            `json:"ack_by,omitempty"`
+}
+
+type AlarmEvent struct {
+	Kind  AlarmEventKind
+	Time  time.Time
+	Alarm Alarm
+}
+
+/*
+Aggregates the alarms of all devices in a single stream. An
+alarm raised again while active is merged in the existing one
+instead of producing a new event, unless its severity
+increases. Alarms stay listed until they are both cleared and
+acknowledged.
+
+It is safe for concurrent use
+*/
+type AlarmAggregator struct {
+	OnEvent func(event AlarmEvent)
+
+	alarms   map[string]*Alarm
+	gauges   map[string]protocol.GaugeMap // By device
+	metadata map[string]protocol.Metadata // By device
+	mutex    sync.Mutex
+}
+
+func NewAlarmAggregator() *AlarmAggregator {
+	return &AlarmAggregator{alarms: make(map[string]*Alarm)}
+}
+
+/*
+Names the channel alarms of a device, whose source is
+"channel N", after the facility gauges of the map, e.g. the
+one of Device.Device.GaugeMap
+*/
+func (a *AlarmAggregator) SetGaugeMap(device string, gauges protocol.GaugeMap) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.gauges == nil {
+		a.gauges = make(map[string]protocol.GaugeMap)
+	}
+	a.gauges[device] = gauges
+}
+
+/*
+Sets the facility metadata (location, sector, rack, contact)
+carried by the alarms of a device, e.g. the one of
+Device.Device.Metadata
+*/
+func (a *AlarmAggregator) SetMetadata(device string, metadata protocol.Metadata) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.metadata == nil {
+		a.metadata = make(map[string]protocol.Metadata)
+	}
+	a.metadata[device] = metadata
+}
+
+/*
+Sets the gauge map and the metadata of every device of a fleet,
+see SetGaugeMap and SetMetadata
+*/
+func (a *AlarmAggregator) AddFleet(fleet *Fleet) {
+	for _, device := range fleet.Devices {
+		a.SetGaugeMap(device.Name, device.Device.GaugeMap())
+		a.SetMetadata(device.Name, device.Device.Metadata())
+	}
+}
+
+/*
+Returns the facility gauge of a channel alarm source
+*/
+func (a *AlarmAggregator) gauge(device string, source string) protocol.Gauge {
+	var channel int
+	if _, err := fmt.Sscanf(source, "channel %d", &channel); err != nil {
+		return protocol.Gauge{}
+	}
+	gauge, _ := a.gauges[device].Channel(channel)
+	return gauge
+}
+
+func alarmKey(device string, source string) string {
+	return device + "/" + source
+}
+
+/*
+Raises an alarm of a device source, or merges it in the active
+alarm of that source
+*/
+func (a *AlarmAggregator) Raise(device string, source string, severity Severity, message string) {
+	now := time.Now()
+	key := alarmKey(device, source)
+
+	a.mutex.Lock()
+	alarm, ok := a.alarms[key]
+	kind := AlarmRaised
+	switch {
+	case ok && alarm.Active && severity <= alarm.Severity:
+		alarm.Count++
+		alarm.LastSeen = now
+		a.mutex.Unlock()
+		return
+	case ok && alarm.Active:
+		kind = AlarmEscalated
+		alarm.Count++
+	default:
+		gauge := a.gauge(device, source)
+		alarm = &Alarm{
+			Key: key, Device: device, Source: source, Gauge: gauge.Name, Equipment: gauge.Equipment,
+			Metadata: a.metadata[device], Raised: now, Count: 1,
+		}
+		a.alarms[key] = alarm
+	}
+	alarm.Severity = severity
+	alarm.Message = message
+	alarm.LastSeen = now
+	alarm.Active = true
+	alarm.Acknowledged = false
+	alarm.AckBy = ""
+	event := AlarmEvent{Kind: kind, Time: now, Alarm: *alarm}
+	a.mutex.Unlock()
+
+	a.emit(event)
+}
+
+/*
+Clears the active alarm of a device source, if any
+*/
+func (a *AlarmAggregator) Clear(device string, source string) {
+	now := time.Now()
+	key := alarmKey(device, source)
+
+	a.mutex.Lock()
+	alarm, ok := a.alarms[key]
+	if !ok || !alarm.Active {
+		a.mutex.Unlock()
+		return
+	}
+	alarm.Active = false
+	if alarm.Acknowledged {
+		delete(a.alarms, key)
+	}
+	event := AlarmEvent{Kind: AlarmCleared, Time: now, Alarm: *alarm}
+	a.mutex.Unlock()
+
+	a.emit(event)
+}
+
+/*
+Acknowledges an alarm by its key
+*/
+func (a *AlarmAggregator) Acknowledge(key string, by string) error {
+	a.mutex.Lock()
+	alarm, ok := a.alarms[key]
+	if !ok {
+		a.mutex.Unlock()
+		return fmt.Errorf("no alarm %q", key)
+	}
+	alarm.Acknowledged = true
+	alarm.AckBy = by
+	if !alarm.Active {
+		delete(a.alarms, key)
+	}
+	event := AlarmEvent{Kind: AlarmAcknowledged, Time: time.Now(), Alarm: *alarm}
+	a.mutex.Unlock()
+
+	a.emit(event)
+	return nil
+}
+
+/*
+Returns the listed alarms, the most severe first and then by
+raise time
+*/
+func (a *AlarmAggregator) Alarms() []Alarm {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	alarms := make([]Alarm, 0, len(a.alarms))
+	for _, alarm := range a.alarms {
+		alarms = append(alarms, *alarm)
+	}
+	slices.SortFunc(alarms, func(x, y Alarm) int {
+		if x.Severity != y.Severity {
+			return cmp.Compare(y.Severity, x.Severity)
+		}
+		return x.Raised.Compare(y.Raised)
+	})
+	return alarms
+}
+
+/*
+Raises or clears the relay and communication alarms of every
+device of a fleet snapshot. Active relays raise warnings and
+devices that failed to answer raise critical alarms
+*/
+func (a *AlarmAggregator) Update(snapshot Snapshot) {
+	for _, device := range snapshot.Devices {
+		if device.Error != "" {
+			a.Raise(device.Name, "communication", Critical, device.Error)
+			continue
+		}
+		a.Clear(device.Name, "communication")
+		for idx, active := range device.Relays {
+			source := fmt.Sprintf("relay %d", idx+1)
+			if active {
+				a.Raise(device.Name, source, Warning, fmt.Sprintf("relay %d activated", idx+1))
+			} else {
+				a.Clear(device.Name, source)
+			}
+		}
+	}
+}
+
+/*
+Returns a watchdog event handler that raises and clears the
+alarms of a device
+*/
+func (a *AlarmAggregator) WatchdogHandler(device string) func(event protocol.WatchdogEvent) {
+	return func(event protocol.WatchdogEvent) {
+		source := fmt.Sprintf("channel %d", event.Channel)
+		switch event.Kind {
+		case protocol.WatchdogBusDead:
+			a.Raise(device, "communication", Critical, fmt.Sprintf("device silent: %v", event.Err))
+		case protocol.WatchdogBusRecovered:
+			a.Clear(device, "communication")
+		case protocol.WatchdogGaugeFault:
+			a.Raise(device, source, Warning, event.Status)
+		case protocol.WatchdogGaugeRecovered:
+			a.Clear(device, source)
+		case protocol.WatchdogIdentityChanged:
+			a.Raise(device, "identity", Critical, event.Err.Error())
+		case protocol.WatchdogSafeState:
+			if event.Err != nil {
+				a.Raise(device, "safe state", Critical, event.Err.Error())
+			}
+		}
+	}
+}
+
+func (a *AlarmAggregator) emit(event AlarmEvent) {
+	if a.OnEvent != nil {
+		a.OnEvent(event)
+	}
+}
@@ -0,0 +1,151 @@
+package fleet_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/fleet"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestAlarmAggregator(t *testing.T) {
+	var events []fleet.AlarmEvent
+	alarms := fleet.NewAlarmAggregator()
+	alarms.OnEvent = func(event fleet.AlarmEvent) {
+		events = append(events, event)
+	}
+
+	snapshot := fleet.Snapshot{Devices: []fleet.DeviceSnapshot{
+		{Name: "sector1", Relays: []bool{true, true, false}},
+	}}
+	for range 3 {
+		alarms.Update(snapshot)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 raised events, got %d", len(events))
+	}
+	listed := alarms.Alarms()
+	if len(listed) != 2 || listed[0].Count != 3 {
+		t.Fatalf("unexpected alarms %+v", listed)
+	}
+
+	snapshot.Devices[0].Relays[0] = false
+	alarms.Update(snapshot)
+	if len(alarms.Alarms()) != 2 {
+		t.Fatal("expected the cleared alarm to be listed until acknowledged")
+	}
+	if err := alarms.Acknowledge("sector1/relay 1", "operator"); err != nil {
+		t.Fatal(err)
+	}
+	if len(alarms.Alarms()) != 1 {
+		t.Fatal("expected the cleared and acknowledged alarm to be removed")
+	}
+
+	alarms.SetGaugeMap("sector1", protocol.GaugeMap{"A2": {Name: "VGP-BC1-02"}})
+	alarms.SetMetadata("sector1", protocol.Metadata{Name: "sector1", Location: "Building 2", Rack: "R3", Contact: "vacuum@lab"})
+	alarms.Raise("sector1", "channel 2", fleet.Warning, "sensor misconnected")
+	if last := events[len(events)-1]; last.Alarm.Gauge != "VGP-BC1-02" {
+		t.Errorf("expected the channel alarm to name its gauge, got %+v", last.Alarm)
+	}
+	if last := events[len(events)-1]; last.Alarm.Metadata.Rack != "R3" || last.Alarm.Metadata.Contact != "vacuum@lab" {
+		t.Errorf("expected the alarm to carry the device metadata, got %+v", last.Alarm.Metadata)
+	}
+
+	alarms.Raise("sector1", "relay 2", fleet.Critical, "escalated")
+	if last := events[len(events)-1]; last.Kind != fleet.AlarmEscalated {
+		t.Errorf("expected an escalation event, got %v", last.Kind)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&payload)
+		texts = append(texts, payload.Text)
+	}))
+	defer server.Close()
+
+	notifier, err := fleet.NewNotifier("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.Webhooks = []fleet.Webhook{{URL: server.URL}}
+	notifier.RateLimit = time.Minute
+	notifier.QuietHours = &fleet.QuietHours{From: 22 * time.Hour, To: 7 * time.Hour, Severity: fleet.Critical}
+
+	day := time.Date(2026, time.October, 17, 14, 0, 0, 0, time.UTC)
+	night := time.Date(2026, time.October, 17, 23, 0, 0, 0, time.UTC)
+	relay := fleet.Alarm{Key: "sector1/relay 1", Device: "sector1", Source: "relay 1", Severity: fleet.Warning, Message: "relay 1 activated"}
+	silent := fleet.Alarm{Key: "sector2/communication", Device: "sector2", Source: "communication", Severity: fleet.Critical, Message: "timeout"}
+	events := []fleet.AlarmEvent{
+		{Kind: fleet.AlarmRaised, Time: day, Alarm: relay},
+		{Kind: fleet.AlarmCleared, Time: day.Add(time.Second), Alarm: relay},
+		{Kind: fleet.AlarmRaised, Time: day.Add(2 * time.Second), Alarm: relay},  // Rate limited
+		{Kind: fleet.AlarmCleared, Time: day.Add(3 * time.Second), Alarm: relay}, // Raise not notified
+		{Kind: fleet.AlarmRaised, Time: night, Alarm: relay},                     // Quiet hours
+		{Kind: fleet.AlarmRaised, Time: night, Alarm: silent},
+		{Kind: fleet.AlarmAcknowledged, Time: night, Alarm: silent},
+	}
+	for _, event := range events {
+		if err := notifier.Notify(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"[WARNING] sector1 relay 1 alarm raised: relay 1 activated",
+		"[WARNING] sector1 relay 1 alarm cleared: relay 1 activated",
+		"[CRITICAL] sector2 communication alarm raised: timeout",
+	}
+	if len(texts) != len(want) {
+		t.Fatalf("expected %d notifications, got %q", len(want), texts)
+	}
+	for idx := range want {
+		if texts[idx] != want[idx] {
+			t.Errorf("notification %d = %q, want %q", idx, texts[idx], want[idx])
+		}
+	}
+}
+
+func TestNotifierHandlerOrder(t *testing.T) {
+	received := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&payload)
+		// A slow delivery of the raise must not let the clear overtake it
+		if strings.Contains(payload.Text, "raised") {
+			time.Sleep(50 * time.Millisecond)
+		}
+		received <- payload.Text
+	}))
+	defer server.Close()
+
+	notifier, err := fleet.NewNotifier("{{.Kind}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.Webhooks = []fleet.Webhook{{URL: server.URL}}
+	notifier.OnError = func(err error) { t.Error(err) }
+
+	handler := notifier.Handler()
+	relay := fleet.Alarm{Key: "sector1/relay 1", Device: "sector1", Source: "relay 1"}
+	now := time.Now()
+	handler(fleet.AlarmEvent{Kind: fleet.AlarmRaised, Time: now, Alarm: relay})
+	handler(fleet.AlarmEvent{Kind: fleet.AlarmCleared, Time: now.Add(time.Millisecond), Alarm: relay})
+
+	for _, want := range []string{"raised", "cleared"} {
+		select {
+		case text := <-received:
+			if text != want {
+				t.Errorf("expected %q, got %q", want, text)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected the %s notification", want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+	"github.com/devicehub-go/unicomm"
+)
+
+type Device struct {
+	Name      string
+	Transport string
+	Config    protocol.DeviceConfig
+	Device    *protocol.MKS937B
+}
+
+/*
+Set of controllers created from a manifest. Devices sharing a
+transport share the same communication, and their exchanges are
+serialized by a lock of the communication
+*/
+type Fleet struct {
+	Devices []*Device
+
+	transports map[string]Transport
+	buses      map[string]unicomm.Unicomm
+}
+
+type Result struct {
+	Name    string
+	Address int
+	Err     error
+}
+
+func (r Result) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s (address %03d): FAILED, %s", r.Name, r.Address, r.Err)
+	}
+	return fmt.Sprintf("%s (address %03d): OK", r.Name, r.Address)
+}
+
+/*
+Creates the communications and devices of a manifest. Nothing
+is connected until the devices are used
+*/
+func New(manifest Manifest) (*Fleet, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	transports, _ := manifest.AllTransports()
+	fleet := &Fleet{transports: transports, buses: make(map[string]unicomm.Unicomm)}
+	for name, transport := range transports {
+		fleet.buses[name], _ = transport.Communication()
+	}
+	for _, entry := range manifest.Devices {
+		device := &protocol.MKS937B{
+			Communication: fleet.buses[entry.Transport],
+			Address:       entry.Address,
+		}
+		if policy := transports[entry.Transport].Reconnect; policy != nil {
+			device.Apply(protocol.WithReconnectPolicy(*policy))
+		}
+		metadata := entry.Metadata
+		if metadata.Name == "" {
+			metadata.Name = entry.Name
+		}
+		device.SetMetadata(metadata)
+		device.SetGaugeMap(entry.Gauges)
+		fleet.Devices = append(fleet.Devices, &Device{
+			Name:      entry.Name,
+			Transport: entry.Transport,
+			Config:    entry.Config,
+			Device:    device,
+		})
+	}
+	return fleet, nil
+}
+
+/*
+Returns the device with a name, or nil if there is none
+*/
+func (f *Fleet) Lookup(name string) *Device {
+	for _, device := range f.Devices {
+		if device.Name == name {
+			return device
+		}
+	}
+	return nil
+}
+
+/*
+Connects the transport of a device with its reconnect policy,
+unless it is already connected by another device of the same
+bus
+*/
+func (d *Device) connect() error {
+	return d.Device.Reconnect(context.Background())
+}
+
+/*
+Provisions every device with its configuration, in manifest
+order, and returns one result per device. A failing device
+does not stop the others
+*/
+func (f *Fleet) ApplyAll() []Result {
+	results := make([]Result, 0, len(f.Devices))
+	for _, device := range f.Devices {
+		result := Result{Name: device.Name, Address: device.Device.Address}
+		if result.Err = device.connect(); result.Err == nil {
+			result.Err = device.Device.ApplyConfig(device.Config)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+/*
+Returns a text summary of results, one line per device
+followed by the number of failures
+*/
+func Summary(results []Result) string {
+	var b strings.Builder
+	failed := 0
+	for _, result := range results {
+		b.WriteString(result.String() + "\n")
+		if result.Err != nil {
+			failed++
+		}
+	}
+	fmt.Fprintf(&b, "%d of %d devices provisioned\n", len(results)-failed, len(results))
+	return b.String()
+}
+
+/*
+Disconnects every connected transport
+*/
+func (f *Fleet) Close() error {
+	var errs []error
+	for name, bus := range f.buses {
+		if !bus.IsConnected() {
+			continue
+		}
+		if err := bus.Disconnect(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+/*
+Changes the baud rate of every device of a serial transport and
+of the transport itself, see protocol.MigrateBaudRate
+*/
+func (f *Fleet) MigrateBaudRate(transport string, baudRate int) error {
+	bus, ok := f.buses[transport]
+	if !ok {
+		return fmt.Errorf("unknown transport %q", transport)
+	}
+	var addresses []int
+	for _, device := range f.Devices {
+		if device.Transport == transport {
+			addresses = append(addresses, device.Device.Address)
+		}
+	}
+	if !bus.IsConnected() {
+		if err := bus.Connect(); err != nil {
+			return err
+		}
+	}
+	return protocol.MigrateBaudRate(bus, addresses, baudRate)
+}
@@ -0,0 +1,142 @@
+package fleet_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/fleet"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// RS-485 bus answering every address. A frame written while another
+// one waits for its reply is an interleaving
+type sharedBus struct {
+	mutex       sync.Mutex
+	connected   bool
+	pending     []string
+	written     []string
+	interleaved int
+}
+
+func (b *sharedBus) Connect() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.connected = true
+	return nil
+}
+
+func (b *sharedBus) Disconnect() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.connected = false
+	return nil
+}
+
+func (b *sharedBus) IsConnected() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.connected
+}
+
+func (b *sharedBus) Read(uint) ([]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (b *sharedBus) Write(message []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if len(b.pending) > 0 {
+		b.interleaved++
+	}
+	b.pending = append(b.pending, string(message))
+	b.written = append(b.written, string(message))
+	return nil
+}
+
+func (b *sharedBus) ReadUntil(string) ([]byte, error) {
+	// The controller takes a while to answer
+	time.Sleep(time.Millisecond)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if len(b.pending) == 0 {
+		return nil, fmt.Errorf("read timeout")
+	}
+	frame := strings.TrimSuffix(b.pending[0], ";FF")
+	b.pending = b.pending[1:]
+	return fmt.Appendf(nil, "@%sACK%s;FF", frame[1:4], reply(frame[4:])), nil
+}
+
+func reply(command string) string {
+	if _, parameter, ok := strings.Cut(command, "!"); ok {
+		return parameter
+	}
+	switch {
+	case strings.HasPrefix(command, "PR"):
+		return "1.00E-07"
+	case strings.HasPrefix(command, "FV"):
+		return "1.14"
+	case command == "U?":
+		return "TORR"
+	}
+	return "NAK"
+}
+
+func TestApplyAllSharedBus(t *testing.T) {
+	manifest := fleet.Manifest{
+		Transports: map[string]fleet.Transport{
+			"ts1": {Protocol: "tcp", Host: "10.0.0.5", Port: 4001},
+		},
+		Devices: []fleet.DeviceEntry{
+			{Name: "sector1", Transport: "ts1", Address: 1, Config: protocol.DeviceConfig{
+				Unit:   "Torr",
+				Labels: map[int]string{1: "BC1 ion pump"},
+				Relays: []protocol.RelayConfig{{Relay: 1, Direction: "BELOW", SetPoint: 1e-5, Hysteresis: 2e-5, Enable: "SET"}},
+			}},
+			{Name: "sector2", Transport: "ts1", Address: 2},
+		},
+	}
+	devices, err := fleet.New(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := &sharedBus{}
+	for _, device := range devices.Devices {
+		device.Device.Communication = bus
+	}
+
+	// Polling the second device while the first one is provisioned
+	polled := devices.Lookup("sector2").Device
+	if err := polled.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if _, err := polled.GetPressure(1); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+	results := devices.ApplyAll()
+	close(stop)
+	wg.Wait()
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Error(result)
+		}
+	}
+	if count := bus.interleaved; count != 0 {
+		t.Errorf("expected the exchanges to be serialized, %d frames interleaved", count)
+	}
+}
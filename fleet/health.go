@@ -0,0 +1,93 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+)
+
+type DeviceHealth struct {
+	Name        string    `json:"name"`
+	Connected   bool      `json:"connected"`
+	LastSuccess time.Time `json:"last_success"`
+	Commands    uint64    `json:"commands"`
+	Errors      uint64    `json:"errors"`
+	ErrorRate   float64   `json:"error_rate"` // Errors per command over the last protocol.RollingWindow
+	Healthy     bool      `json:"healthy"`
+}
+
+type Health struct {
+	Healthy bool           `json:"healthy"`
+	Devices []DeviceHealth `json:"devices"`
+}
+
+/*
+Summarizes the health of every device from its driver stats.
+A device is healthy when it is connected and its last
+successful command is more recent than maxAge
+*/
+func (f *Fleet) Health(maxAge time.Duration) Health {
+	health := Health{Healthy: true}
+	now := time.Now()
+	for _, device := range f.Devices {
+		stats := device.Device.Stats()
+		result := DeviceHealth{
+			Name:        device.Name,
+			Connected:   device.Device.IsConnected(),
+			LastSuccess: stats.LastSuccess,
+			Commands:    stats.Commands,
+			Errors:      stats.Errors,
+			ErrorRate:   stats.Recent.ErrorRate(),
+		}
+		result.Healthy = result.Connected && now.Sub(stats.LastSuccess) <= maxAge
+		health.Healthy = health.Healthy && result.Healthy
+		health.Devices = append(health.Devices, result)
+	}
+	return health
+}
+
+/*
+Returns an HTTP handler serving the fleet health as JSON, with
+status 200 when every device is healthy and 503 otherwise, for
+Kubernetes probes and load balancers
+*/
+func (f *Fleet) HealthHandler(maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := f.Health(maxAge)
+		w.Header().Set("Content-Type", "application/json")
+		if !health.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(health)
+	})
+}
+
+/*
+Returns an HTTP handler serving the driver stats and the
+pressures of every device in the Prometheus text exposition
+format, labeled with the device metadata. The pressures are read
+on every scrape
+*/
+func (f *Fleet) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snapshot := f.Snapshot()
+		metrics := make(map[string]export.PrometheusDevice, len(f.Devices))
+		for idx, device := range f.Devices {
+			metrics[device.Name] = export.PrometheusDevice{
+				Stats:    device.Device.Stats(),
+				Metadata: device.Device.Metadata(),
+				Readings: snapshot.Devices[idx].Pressures,
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		export.WritePrometheusDevices(w, metrics)
+	})
+}
@@ -0,0 +1,75 @@
+package fleet_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/fleet"
+)
+
+func TestHealthHandler(t *testing.T) {
+	manifest := fleet.Manifest{
+		Transports: map[string]fleet.Transport{
+			"ts1": {Protocol: "tcp", Host: "10.0.0.5", Port: 4001},
+			"ts2": {Protocol: "tcp", Host: "10.0.0.6", Port: 4001},
+		},
+		Devices: []fleet.DeviceEntry{
+			{Name: "sector1", Transport: "ts1", Address: 1},
+			{Name: "sector2", Transport: "ts2", Address: 1},
+		},
+	}
+	devices, err := fleet.New(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, device := range devices.Devices {
+		device.Device.Communication = &sharedBus{}
+	}
+	up := devices.Lookup("sector1").Device
+	if err := up.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := up.GetPressure(1); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func() (int, fleet.Health) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		devices.HealthHandler(time.Minute).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var health fleet.Health
+		if err := json.NewDecoder(recorder.Body).Decode(&health); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code, health
+	}
+
+	// A disconnected device makes the fleet unhealthy
+	code, health := serve()
+	if code != http.StatusServiceUnavailable || health.Healthy {
+		t.Errorf("expected status 503, got %d and %+v", code, health)
+	}
+	if len(health.Devices) != 2 || !health.Devices[0].Healthy || health.Devices[0].Commands != 1 ||
+		health.Devices[1].Healthy || health.Devices[1].Connected {
+		t.Errorf("unexpected device health %+v", health.Devices)
+	}
+
+	down := devices.Lookup("sector2").Device
+	if err := down.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := down.GetPressure(1); err != nil {
+		t.Fatal(err)
+	}
+	if code, health := serve(); code != http.StatusOK || !health.Healthy {
+		t.Errorf("expected status 200, got %d and %+v", code, health)
+	}
+
+	// A connected device without a recent success is unhealthy
+	if health := devices.Health(0); health.Healthy || health.Devices[0].Healthy {
+		t.Errorf("expected stale devices to be unhealthy, got %+v", health)
+	}
+}
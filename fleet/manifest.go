@@ -0,0 +1,220 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Communication shared by the devices of a bus or terminal server
+port. Serial transports use Device, BaudRate and Parity, TCP
+transports use Host and Port, optionally with TLS, and RFC 2217 terminal servers use
+Host, Port, BaudRate and Parity, which are set on the remote
+serial port
+*/
+type Transport struct {
+	Protocol string        `yaml:"protocol"` // serial, tcp or rfc2217
+	Device   string        `yaml:"device,omitempty"`
+	BaudRate int           `yaml:"baud_rate,omitempty"` // Default is 9600
+	Parity   string        `yaml:"parity,omitempty"`    // NONE, EVEN or ODD, default is NONE
+	Host     string        `yaml:"host,omitempty"`
+	Port     uint          `yaml:"port,omitempty"`
+	Timeout  time.Duration `yaml:"timeout,omitempty"` // Read and write timeout, e.g. 500ms
+
+	Reconnect *protocol.ReconnectPolicy `yaml:"reconnect,omitempty"` // Default is protocol.DefaultReconnectPolicy
+	RS485     *protocol.RS485Direction  `yaml:"rs485,omitempty"`     // RTS direction control of serial transports
+	TLS       *protocol.TLSOptions      `yaml:"tls,omitempty"`       // TLS of tcp transports
+}
+
+type DeviceEntry struct {
+	Name      string                `yaml:"name"`
+	Transport string                `yaml:"transport"` // Name of the transport
+	Address   int                   `yaml:"address"`
+	Metadata  protocol.Metadata     `yaml:"metadata,omitempty"`
+	Config    protocol.DeviceConfig `yaml:"config,omitempty"`
+	Gauges    protocol.GaugeMap     `yaml:"gauges,omitempty"` // Facility gauges by slot
+}
+
+/*
+Fleet manifest listing the transports, the terminal servers
+whose ports are transports as well, and the devices reached
+through them, e.g.
+
+	transports:
+	  bus1: {protocol: serial, device: /dev/ttyUSB0, baud_rate: 9600, rs485: {post_hold: 1ms}}
+	  ts1: {protocol: tcp, host: 10.0.0.5, port: 4001, timeout: 500ms}
+	  secure: {protocol: tcp, host: 10.0.0.8, port: 4443, tls: {ca_file: ca.pem, cert_file: client.pem, key_file: client.key}}
+	  ts2: {protocol: rfc2217, host: 10.0.0.7, port: 4001, baud_rate: 19200, parity: EVEN}
+	terminal_servers:
+	  rack12: {host: 10.0.0.6, first_port: 4001, ports: 16, timeout: 500ms}
+	devices:
+	  - name: sector1-gauges
+	    transport: bus1
+	    address: 1
+	    metadata: {location: Hall B, sector: "1", rack: R12, contact: vacuum-oncall}
+	    config:
+	      unit: Torr
+	      labels: {1: BC1 ion pump}
+	    gauges:
+	      A1: {name: VGC-BC1-01, equipment: EQ-20431}
+	  - name: sector2-gauges
+	    transport: rack12/3
+	    address: 1
+*/
+type Manifest struct {
+	Transports      map[string]Transport      `yaml:"transports"`
+	TerminalServers map[string]TerminalServer `yaml:"terminal_servers,omitempty"`
+	Devices         []DeviceEntry             `yaml:"devices"`
+}
+
+var serialParity = map[string]unicommserial.Parity{
+	"":     unicommserial.NoParity,
+	"NONE": unicommserial.NoParity,
+	"EVEN": unicommserial.EvenParity,
+	"ODD":  unicommserial.OddParity,
+}
+
+/*
+Loads and validates a manifest from a YAML file
+*/
+func LoadManifest(path string) (Manifest, error) {
+	var manifest Manifest
+	if err := protocol.LoadYAML(path, &manifest); err != nil {
+		return manifest, err
+	}
+	return manifest, manifest.Validate()
+}
+
+/*
+Verifies that every device has a unique name and refers to a
+valid transport
+*/
+func (m Manifest) Validate() error {
+	transports, err := m.AllTransports()
+	if err != nil {
+		return err
+	}
+	for name, transport := range transports {
+		if _, err := transport.Communication(); err != nil {
+			return fmt.Errorf("transport %s: %w", name, err)
+		}
+	}
+	names := make(map[string]bool)
+	for _, device := range m.Devices {
+		if device.Name == "" || names[device.Name] {
+			return fmt.Errorf("device names must be unique and not empty, got %q", device.Name)
+		}
+		names[device.Name] = true
+		if _, ok := transports[device.Transport]; !ok {
+			return fmt.Errorf("device %s: unknown transport %q", device.Name, device.Transport)
+		}
+		if device.Address < 1 || 254 < device.Address {
+			return fmt.Errorf("device %s: %w", device.Name, protocol.NewErrInvalidAddress(device.Address))
+		}
+		if err := device.Gauges.Validate(); err != nil {
+			return fmt.Errorf("device %s: %w", device.Name, err)
+		}
+	}
+	return nil
+}
+
+/*
+Converts the transport to communication options. RFC 2217
+transports have none, see Communication
+*/
+func (t Transport) Options() (unicomm.Options, error) {
+	switch t.Protocol {
+	case "serial":
+		if t.TLS != nil {
+			return unicomm.Options{}, fmt.Errorf("tls requires a tcp transport")
+		}
+		parity, ok := serialParity[t.Parity]
+		if !ok {
+			return unicomm.Options{}, protocol.NewErrInvalidParity(t.Parity)
+		}
+		baudRate := t.BaudRate
+		if baudRate == 0 {
+			baudRate = 9600
+		}
+		return unicomm.Options{
+			Protocol: unicomm.Serial,
+			Serial: unicommserial.SerialOptions{
+				PortName:     t.Device,
+				BaudRate:     baudRate,
+				DataBits:     8,
+				StopBits:     unicommserial.OneStopBit,
+				Parity:       parity,
+				ReadTimeout:  t.Timeout,
+				WriteTimeout: t.Timeout,
+			},
+		}, nil
+	case "tcp":
+		if t.RS485 != nil {
+			return unicomm.Options{}, fmt.Errorf("rs485 direction control requires a serial transport")
+		}
+		return unicomm.Options{
+			Protocol: unicomm.TCP,
+			TCP: unicommtcp.TCPOptions{
+				Host:         t.Host,
+				Port:         t.Port,
+				ReadTimeout:  t.Timeout,
+				WriteTimeout: t.Timeout,
+			},
+		}, nil
+	case "rfc2217":
+		return unicomm.Options{}, fmt.Errorf("rfc2217 transports have no unicomm options")
+	}
+	return unicomm.Options{}, fmt.Errorf("protocol must be serial, tcp or rfc2217, got %q", t.Protocol)
+}
+
+/*
+Creates the communication of the transport, not connected
+*/
+func (t Transport) Communication() (unicomm.Unicomm, error) {
+	if t.Protocol == "rfc2217" {
+		if t.RS485 != nil {
+			return nil, fmt.Errorf("rs485 direction control requires a serial transport")
+		}
+		if t.TLS != nil {
+			return nil, fmt.Errorf("tls requires a tcp transport")
+		}
+		if _, ok := serialParity[t.Parity]; !ok {
+			return nil, protocol.NewErrInvalidParity(t.Parity)
+		}
+		return protocol.NewRFC2217(protocol.RFC2217Options{
+			Host:         t.Host,
+			Port:         t.Port,
+			BaudRate:     t.BaudRate,
+			Parity:       t.Parity,
+			ReadTimeout:  t.Timeout,
+			WriteTimeout: t.Timeout,
+		}), nil
+	}
+	options, err := t.Options()
+	if err != nil {
+		return nil, err
+	}
+	communication := unicomm.New(options)
+	if port, ok := communication.(*unicommserial.UnicommSerial); ok && t.RS485 != nil {
+		communication = protocol.NewRS485Port(port, *t.RS485)
+	}
+	if tcp, ok := communication.(*unicommtcp.UnicommTCP); ok && t.TLS != nil {
+		config, err := t.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		communication = protocol.NewTLSPort(tcp, config)
+	}
+	return communication, nil
+}
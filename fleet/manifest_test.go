@@ -0,0 +1,114 @@
+package fleet_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/fleet"
+	"github.com/devicehub-go/mks-937b/protocol"
+	"github.com/devicehub-go/unicomm"
+)
+
+const manifest = `
+transports:
+  bus1: {protocol: serial, device: /dev/ttyUSB0, baud_rate: 19200, parity: EVEN, rs485: {post_hold: 2ms}}
+  ts1: {protocol: tcp, host: 10.0.0.5, port: 4001, timeout: 500ms}
+devices:
+  - name: sector1
+    transport: bus1
+    address: 1
+    config:
+      unit: Torr
+      labels: {1: BC1 ion pump}
+  - name: sector2
+    transport: ts1
+    address: 2
+`
+
+func TestLoadManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleet.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := fleet.LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Devices) != 2 || loaded.Devices[0].Config.Labels[1] != "BC1 ion pump" {
+		t.Fatalf("unexpected devices %+v", loaded.Devices)
+	}
+	options, err := loaded.Transports["ts1"].Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.Protocol != unicomm.TCP || options.TCP.ReadTimeout != 500*time.Millisecond {
+		t.Errorf("unexpected TCP options %+v", options.TCP)
+	}
+
+	if rs485 := loaded.Transports["bus1"].RS485; rs485 == nil || rs485.PostHold != 2*time.Millisecond {
+		t.Errorf("unexpected RS-485 direction control %+v", rs485)
+	}
+	ts1 := loaded.Transports["ts1"]
+	ts1.RS485 = loaded.Transports["bus1"].RS485
+	if _, err := ts1.Options(); err == nil {
+		t.Error("expected an error for RS-485 direction control on TCP")
+	}
+
+	secure := loaded.Transports["ts1"]
+	secure.TLS = &protocol.TLSOptions{InsecureSkipVerify: true}
+	if communication, err := secure.Communication(); err != nil {
+		t.Error(err)
+	} else if _, ok := communication.(*protocol.TLSPort); !ok {
+		t.Errorf("expected a TLS communication, got %T", communication)
+	}
+	secure.TLS.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := secure.Communication(); err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+	bus1 := loaded.Transports["bus1"]
+	bus1.TLS = secure.TLS
+	if _, err := bus1.Communication(); err == nil {
+		t.Error("expected an error for TLS on a serial transport")
+	}
+
+	loaded.Devices[1].Transport = "bus2"
+	if err := loaded.Validate(); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
+
+func TestTerminalServerPorts(t *testing.T) {
+	manifest := fleet.Manifest{
+		TerminalServers: map[string]fleet.TerminalServer{
+			"rack12": {Host: "10.0.0.6", FirstPort: 4001, Ports: 16},
+		},
+		Devices: []fleet.DeviceEntry{{Name: "sector2", Transport: "rack12/3", Address: 1}},
+	}
+	if err := manifest.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	transports, _ := manifest.AllTransports()
+	if len(transports) != 16 || transports["rack12/3"].Port != 4003 {
+		t.Errorf("unexpected transports %+v", transports)
+	}
+
+	manifest.TerminalServers["rack12"] = fleet.TerminalServer{
+		Host: "10.0.0.6", FirstPort: 4001, Ports: 16, Protocol: "rfc2217", BaudRate: 19200, Parity: "EVEN",
+	}
+	transports, _ = manifest.AllTransports()
+	communication, err := transports["rack12/3"].Communication()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rfc2217, ok := communication.(*protocol.RFC2217)
+	if !ok || rfc2217.Options.Port != 4003 || rfc2217.Options.BaudRate != 19200 || rfc2217.Options.Parity != "EVEN" {
+		t.Errorf("unexpected RFC 2217 communication %+v", communication)
+	}
+
+	manifest.Devices[0].Transport = "rack12/17"
+	if err := manifest.Validate(); err == nil {
+		t.Error("expected an error for a port out of the server")
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/export"
+)
+
+// Default text of the notifications
+const DefaultNotificationTemplate = `[{{upper .Alarm.Severity.String}}] {{.Alarm.Device}} {{.Alarm.Source}}{{with .Alarm.Gauge}} ({{.}}){{end}} alarm {{.Kind}}: {{.Alarm.Message}}`
+
+/*
+Incoming webhook receiving {"text": "..."}, the payload accepted
+by Slack, Microsoft Teams and Mattermost
+*/
+type Webhook struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+/*
+SMTP server and recipients of the notification emails. The
+PLAIN authentication is used when a username is set
+*/
+type SMTP struct {
+	Address  string   `yaml:"address"` // host:port
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+/*
+Daily period, possibly spanning midnight, during which only
+alarms of a minimum severity are notified
+*/
+type QuietHours struct {
+	From     time.Duration `yaml:"from"`     // Time of day, e.g. 22h
+	To       time.Duration `yaml:"to"`       // Time of day, e.g. 7h
+	Severity Severity      `yaml:"severity"` // Minimum severity notified during the period
+}
+
+/*
+Returns true if a time is within the period
+*/
+func (q QuietHours) contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	clock := t.Sub(midnight)
+	if q.From <= q.To {
+		return clock >= q.From && clock < q.To
+	}
+	return clock >= q.From || clock < q.To
+}
+
+/*
+Sends raised, escalated and cleared alarm events to webhooks and
+by email, so on-call staff hear about vacuum excursions.
+Acknowledgments are not notified. An alarm raised again is
+notified at most once per RateLimit, escalations excepted, and
+during quiet hours only alarms of the quiet hours severity are.
+Clears are notified only for notified alarms.
+
+It is safe for concurrent use
+*/
+type Notifier struct {
+	Webhooks   []Webhook
+	SMTP       *SMTP
+	RateLimit  time.Duration // Minimum time between notifications of an alarm
+	QuietHours *QuietHours
+	Client     *http.Client
+	OnError    func(err error) // Delivery errors of Handler
+
+	template *export.TemplateFormatter
+	sent     map[string]notified     // By alarm key
+	queues   map[string][]AlarmEvent // Events waiting for delivery by alarm key
+	mutex    sync.Mutex
+}
+
+/*
+Creates a notifier formatting the notifications with a
+text/template receiving the AlarmEvent, with the functions of
+export.TemplateFormatter. DefaultNotificationTemplate is used
+if text is empty
+*/
+func NewNotifier(text string) (*Notifier, error) {
+	if text == "" {
+		text = DefaultNotificationTemplate
+	}
+	formatter, err := export.NewTemplateFormatter(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		template: formatter,
+		sent:     make(map[string]notified),
+		queues:   make(map[string][]AlarmEvent),
+	}, nil
+}
+
+/*
+Notification state of an alarm
+*/
+type notified struct {
+	raised time.Time // Last notified raise or escalation
+	active bool      // True if the current raise was notified
+}
+
+/*
+Returns true if an event must be notified, and records it. A
+clear is only notified when the raise it ends was
+*/
+func (n *Notifier) admit(event AlarmEvent) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	state := n.sent[event.Alarm.Key]
+	switch event.Kind {
+	case AlarmCleared:
+		if !state.active {
+			return false
+		}
+		n.sent[event.Alarm.Key] = notified{raised: state.raised}
+		return true
+	case AlarmRaised, AlarmEscalated:
+		quiet := n.QuietHours != nil && n.QuietHours.contains(event.Time) && event.Alarm.Severity < n.QuietHours.Severity
+		limited := event.Kind == AlarmRaised && event.Time.Sub(state.raised) < n.RateLimit
+		if quiet || limited {
+			return false
+		}
+		n.sent[event.Alarm.Key] = notified{raised: event.Time, active: true}
+		return true
+	}
+	return false
+}
+
+/*
+Notifies an alarm event to every webhook and by email, unless
+it is filtered out. Returns the delivery errors joined
+*/
+func (n *Notifier) Notify(event AlarmEvent) error {
+	if !n.admit(event) {
+		return nil
+	}
+	var text strings.Builder
+	if err := n.template.Execute(&text, event); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, webhook := range n.Webhooks {
+		errs = append(errs, n.post(webhook, text.String()))
+	}
+	if n.SMTP != nil {
+		subject := fmt.Sprintf("%s alarm %s: %s %s", event.Alarm.Severity, event.Kind, event.Alarm.Device, event.Alarm.Source)
+		errs = append(errs, n.SMTP.send(subject, text.String()))
+	}
+	return errors.Join(errs...)
+}
+
+/*
+Returns an alarm event handler, e.g. for AlarmAggregator.OnEvent,
+notifying in background so alarms are never delayed by a slow
+server. The events of an alarm are delivered one at a time in
+the order received, so a clear never overtakes its raise, while
+different alarms are delivered concurrently. Errors are passed
+to OnError
+*/
+func (n *Notifier) Handler() func(event AlarmEvent) {
+	return func(event AlarmEvent) {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+
+		key := event.Alarm.Key
+		queue, delivering := n.queues[key]
+		n.queues[key] = append(queue, event)
+		if !delivering {
+			go n.deliver(key)
+		}
+	}
+}
+
+/*
+Notifies the queued events of an alarm until its queue is empty
+*/
+func (n *Notifier) deliver(key string) {
+	for {
+		n.mutex.Lock()
+		queue := n.queues[key]
+		if len(queue) == 0 {
+			delete(n.queues, key)
+			n.mutex.Unlock()
+			return
+		}
+		event := queue[0]
+		n.queues[key] = queue[1:]
+		n.mutex.Unlock()
+
+		if err := n.Notify(event); err != nil && n.OnError != nil {
+			n.OnError(err)
+		}
+	}
+}
+
+/*
+Posts a text to a webhook
+*/
+func (n *Notifier) post(webhook Webhook, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range webhook.Headers {
+		request.Header.Set(key, value)
+	}
+	response, err := n.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s answered %s", webhook.URL, response.Status)
+	}
+	return nil
+}
+
+/*
+Sends an email to the recipients
+*/
+func (s *SMTP) send(subject string, text string) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := strings.Cut(s.Address, ":")
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	message := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From, strings.Join(s.To, ", "), subject, text,
+	)
+	return smtp.SendMail(s.Address, auth, s.From, s.To, []byte(message))
+}
@@ -0,0 +1,78 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+type DeviceSnapshot struct {
+	Name      string                     `json:"name" yaml:"name"`
+	Time      time.Time                  `json:"time" yaml:"time"`
+	Pressures []protocol.PressureReading `json:"pressures" yaml:"pressures"`
+	Relays    []bool                     `json:"relays" yaml:"relays"` // Activation status of relays 1 to 12
+	Error     string                     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type Snapshot struct {
+	Time    time.Time        `json:"time" yaml:"time"`
+	Devices []DeviceSnapshot `json:"devices" yaml:"devices"`
+}
+
+/*
+Gathers the pressures, statuses and relay (alarm) states of all
+devices, in manifest order. Buses are read concurrently while
+the devices of a same bus are read one after the other. Devices
+that fail keep their error in the snapshot
+*/
+func (f *Fleet) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Time:    time.Now(),
+		Devices: make([]DeviceSnapshot, len(f.Devices)),
+	}
+	f.forEachBus(func(idx int, device *Device) {
+		result := DeviceSnapshot{Name: device.Name}
+		err := device.connect()
+		if err == nil {
+			result.Pressures, err = device.Device.GetPressures()
+		}
+		if err == nil {
+			result.Relays, err = device.Device.GetRelayStatuses()
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Time = time.Now()
+		snapshot.Devices[idx] = result
+	})
+	return snapshot
+}
+
+/*
+Runs fn for every device, with one routine per transport so
+devices sharing a bus are never accessed at the same time.
+It returns when all devices are done
+*/
+func (f *Fleet) forEachBus(fn func(idx int, device *Device)) {
+	buses := make(map[string][]int)
+	for idx, device := range f.Devices {
+		buses[device.Transport] = append(buses[device.Transport], idx)
+	}
+
+	var wg sync.WaitGroup
+	for _, indexes := range buses {
+		wg.Go(func() {
+			for _, idx := range indexes {
+				fn(idx, f.Devices[idx])
+			}
+		})
+	}
+	wg.Wait()
+}
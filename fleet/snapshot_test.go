@@ -0,0 +1,50 @@
+package fleet_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/fleet"
+)
+
+func TestSnapshotOrder(t *testing.T) {
+	manifest := fleet.Manifest{
+		Transports: map[string]fleet.Transport{
+			"ts1": {Protocol: "tcp", Host: "10.0.0.5", Port: 4001},
+			"ts2": {Protocol: "tcp", Host: "10.0.0.6", Port: 4001},
+		},
+		Devices: []fleet.DeviceEntry{
+			{Name: "sector1", Transport: "ts1", Address: 1},
+			{Name: "sector2", Transport: "ts2", Address: 1},
+			{Name: "sector3", Transport: "ts1", Address: 2},
+		},
+	}
+	devices, err := fleet.New(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, other := &sharedBus{}, &sharedBus{}
+	devices.Devices[0].Device.Communication = shared
+	devices.Devices[1].Device.Communication = other
+	devices.Devices[2].Device.Communication = shared
+
+	snapshot := devices.Snapshot()
+	var names []string
+	for _, device := range snapshot.Devices {
+		names = append(names, device.Name)
+	}
+	if expected := []string{"sector1", "sector2", "sector3"}; !slices.Equal(names, expected) {
+		t.Errorf("expected devices %v in manifest order, got %v", expected, names)
+	}
+	// The devices of the shared bus are read one after the other, in manifest order
+	if shared.interleaved != 0 {
+		t.Errorf("expected no interleaving on the shared bus, got %d", shared.interleaved)
+	}
+	first := slices.IndexFunc(shared.written, func(frame string) bool { return frame[1:4] == "002" })
+	if first < 0 || slices.ContainsFunc(shared.written[first:], func(frame string) bool { return frame[1:4] == "001" }) {
+		t.Errorf("expected address 001 to be read before 002, got %v", shared.written)
+	}
+	if len(other.written) == 0 {
+		t.Error("expected the second bus to be read")
+	}
+}
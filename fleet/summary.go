@@ -0,0 +1,393 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Pressure statistics of a channel over a summary period. Only
+readings with an OK status are part of the statistics
+*/
+type ChannelSummary struct {
+	Device     string  `json:"device"`
+	Channel    int     `json:"channel"`
+	Label      string  `json:"label,omitempty"`
+	Gauge      string  `json:"gauge,omitempty"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Mean       float64 `json:"mean"`
+	Samples    int     `json:"samples"`
+	Invalid    int     `json:"invalid"`              // Readings whose status was not OK
+	Limit      float64 `json:"limit,omitempty"`      // Excursion limit, see SummaryCollector.Limits
+	Excursions int     `json:"excursions,omitempty"` // Times the pressure rose above the limit
+}
+
+/*
+Activity of an alarm over a summary period
+*/
+type AlarmSummary struct {
+	Key          string   `json:"key"`
+	Device       string   `json:"device"`
+	Source       string   `json:"source"`
+	Gauge        string   `json:"gauge,omitempty"`
+	Severity     Severity `json:"severity"` // Highest severity of the period
+	Message      string   `json:"message"`  // Last message
+	Raised       int      `json:"raised"`   // Raise and escalation events
+	Cleared      int      `json:"cleared"`
+	Acknowledged int      `json:"acknowledged"`
+	Active       bool     `json:"active"` // Still active at the end of the period
+}
+
+/*
+Summary of a shift or a day for handover logs
+*/
+type ShiftSummary struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Channels []ChannelSummary `json:"channels"` // By device and channel
+	Alarms   []AlarmSummary   `json:"alarms"`   // The most severe first
+}
+
+type channelStats struct {
+	summary ChannelSummary
+	sum     float64
+	above   bool
+}
+
+/*
+Collects the readings of the devices and the alarm events over
+a period, such as a shift or a day, and summarizes them per
+channel and per alarm. Alarms still active when a period is
+closed are carried over to the next one.
+
+It is safe for concurrent use
+*/
+type SummaryCollector struct {
+	Limits map[string]float64 // Excursion limits by device and channel, e.g. "sector1/channel 1"
+
+	from     time.Time
+	channels map[string]*channelStats
+	alarms   map[string]*AlarmSummary
+	mutex    sync.Mutex
+}
+
+/*
+Creates a new collector whose first period starts at a time
+*/
+func NewSummaryCollector(from time.Time) *SummaryCollector {
+	return &SummaryCollector{
+		Limits:   make(map[string]float64),
+		from:     from,
+		channels: make(map[string]*channelStats),
+		alarms:   make(map[string]*AlarmSummary),
+	}
+}
+
+/*
+Adds the readings of a device. Channels are numbered from 1
+when the readings have none
+*/
+func (c *SummaryCollector) Add(device string, readings []protocol.PressureReading) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for idx, reading := range readings {
+		channel := reading.Channel
+		if channel == 0 {
+			channel = idx + 1
+		}
+		key := alarmKey(device, fmt.Sprintf("channel %d", channel))
+		stats, ok := c.channels[key]
+		if !ok {
+			stats = &channelStats{summary: ChannelSummary{Device: device, Channel: channel}}
+			c.channels[key] = stats
+		}
+		stats.summary.Label, stats.summary.Gauge = reading.Label, reading.Gauge
+		if reading.Status != "OK" {
+			stats.summary.Invalid++
+			continue
+		}
+		if stats.summary.Samples == 0 || reading.Value < stats.summary.Min {
+			stats.summary.Min = reading.Value
+		}
+		stats.summary.Max = max(stats.summary.Max, reading.Value)
+		stats.summary.Samples++
+		stats.sum += reading.Value
+
+		limit, ok := c.Limits[key]
+		stats.summary.Limit = limit
+		above := ok && reading.Value > limit
+		if above && !stats.above {
+			stats.summary.Excursions++
+		}
+		stats.above = above
+	}
+}
+
+/*
+Records an alarm event, e.g. from AlarmAggregator.OnEvent
+*/
+func (c *SummaryCollector) Record(event AlarmEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	alarm := event.Alarm
+	summary, ok := c.alarms[alarm.Key]
+	if !ok {
+		summary = &AlarmSummary{Key: alarm.Key, Device: alarm.Device, Source: alarm.Source}
+		c.alarms[alarm.Key] = summary
+	}
+	summary.Gauge = alarm.Gauge
+	summary.Severity = max(summary.Severity, alarm.Severity)
+	summary.Message = alarm.Message
+	summary.Active = alarm.Active
+	switch event.Kind {
+	case AlarmRaised, AlarmEscalated:
+		summary.Raised++
+	case AlarmCleared:
+		summary.Cleared++
+	case AlarmAcknowledged:
+		summary.Acknowledged++
+	}
+}
+
+/*
+Returns the summary of the current period up to a time
+*/
+func (c *SummaryCollector) Summary(to time.Time) ShiftSummary {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.summary(to)
+}
+
+/*
+Closes the current period at a time, returning its summary,
+and starts the next one
+*/
+func (c *SummaryCollector) Close(to time.Time) ShiftSummary {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	summary := c.summary(to)
+	c.from = to
+	c.channels = make(map[string]*channelStats)
+	alarms := make(map[string]*AlarmSummary)
+	for key, alarm := range c.alarms {
+		if alarm.Active {
+			alarms[key] = &AlarmSummary{
+				Key: key, Device: alarm.Device, Source: alarm.Source, Gauge: alarm.Gauge,
+				Severity: alarm.Severity, Message: alarm.Message, Active: true,
+			}
+		}
+	}
+	c.alarms = alarms
+	return summary
+}
+
+/*
+Returns the summary of the current period. The caller must
+hold the mutex
+*/
+func (c *SummaryCollector) summary(to time.Time) ShiftSummary {
+	summary := ShiftSummary{From: c.from, To: to}
+	for stats := range maps.Values(c.channels) {
+		channel := stats.summary
+		if channel.Samples > 0 {
+			channel.Mean = stats.sum / float64(channel.Samples)
+		}
+		summary.Channels = append(summary.Channels, channel)
+	}
+	slices.SortFunc(summary.Channels, func(x, y ChannelSummary) int {
+		return cmp.Or(cmp.Compare(x.Device, y.Device), cmp.Compare(x.Channel, y.Channel))
+	})
+	for _, alarm := range c.alarms {
+		summary.Alarms = append(summary.Alarms, *alarm)
+	}
+	slices.SortFunc(summary.Alarms, func(x, y AlarmSummary) int {
+		return cmp.Or(cmp.Compare(y.Severity, x.Severity), cmp.Compare(x.Key, y.Key))
+	})
+	return summary
+}
+
+/*
+Returns the summary as indented JSON
+*/
+func (s ShiftSummary) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+/*
+Returns the name of a channel for the renderings: its gauge,
+label or number
+*/
+func (c ChannelSummary) name() string {
+	return cmp.Or(c.Gauge, c.Label, fmt.Sprintf("channel %d", c.Channel))
+}
+
+/*
+Returns a plain text rendering of the summary, suited to
+emails and handover notes
+*/
+func (s ShiftSummary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Shift summary from %s to %s\n", s.From.Format(time.RFC3339), s.To.Format(time.RFC3339))
+	fmt.Fprintf(&b, "\nChannels:\n")
+	for _, channel := range s.Channels {
+		if channel.Samples == 0 {
+			fmt.Fprintf(&b, "  %s %s: no valid reading (%d invalid)\n", channel.Device, channel.name(), channel.Invalid)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s %s: min %.2E, max %.2E, mean %.2E, %d excursions, %d invalid\n",
+			channel.Device, channel.name(), channel.Min, channel.Max, channel.Mean, channel.Excursions, channel.Invalid)
+	}
+	fmt.Fprintf(&b, "\nAlarms:\n")
+	if len(s.Alarms) == 0 {
+		fmt.Fprintf(&b, "  none\n")
+	}
+	for _, alarm := range s.Alarms {
+		state := "cleared"
+		if alarm.Active {
+			state = "ACTIVE"
+		}
+		fmt.Fprintf(&b, "  [%s] %s: raised %d, cleared %d, acknowledged %d, %s: %s\n",
+			alarm.Severity, alarm.Key, alarm.Raised, alarm.Cleared, alarm.Acknowledged, state, alarm.Message)
+	}
+	return b.String()
+}
+
+/*
+Returns a Markdown rendering of the summary, suited to
+handover logs
+*/
+func (s ShiftSummary) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Shift Summary\n\n")
+	fmt.Fprintf(&b, "From %s to %s\n\n", s.From.Format(time.RFC3339), s.To.Format(time.RFC3339))
+	fmt.Fprintf(&b, "## Channels\n\n")
+	fmt.Fprintf(&b, "| Device | Channel | Min | Max | Mean | Samples | Invalid | Limit | Excursions |\n")
+	fmt.Fprintf(&b, "|---|---|---|---|---|---|---|---|---|\n")
+	for _, channel := range s.Channels {
+		values := []string{"-", "-", "-"}
+		if channel.Samples > 0 {
+			for idx, value := range []float64{channel.Min, channel.Max, channel.Mean} {
+				values[idx] = protocol.FormatPretty(value, 3)
+			}
+		}
+		limit := "-"
+		if channel.Limit > 0 {
+			limit = protocol.FormatPretty(channel.Limit, 3)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d | %d | %s | %d |\n",
+			channel.Device, channel.name(), values[0], values[1], values[2],
+			channel.Samples, channel.Invalid, limit, channel.Excursions)
+	}
+	fmt.Fprintf(&b, "\n## Alarms\n\n")
+	if len(s.Alarms) == 0 {
+		fmt.Fprintf(&b, "No alarm.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "| Severity | Device | Source | Raised | Cleared | Acknowledged | Active | Last message |\n")
+	fmt.Fprintf(&b, "|---|---|---|---|---|---|---|---|\n")
+	for _, alarm := range s.Alarms {
+		source := alarm.Source
+		if alarm.Gauge != "" {
+			source = fmt.Sprintf("%s (%s)", source, alarm.Gauge)
+		}
+		active := "no"
+		if alarm.Active {
+			active = "yes"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %d | %s | %s |\n",
+			alarm.Severity, alarm.Device, source, alarm.Raised, alarm.Cleared, alarm.Acknowledged, active, alarm.Message)
+	}
+	return b.String()
+}
+
+/*
+Closes the periods of a summary collector at the times of a
+schedule, e.g. "0 6,14,22 * * *" for three shifts, and hands
+each summary to OnSummary
+*/
+type SummaryJob struct {
+	Collector *SummaryCollector
+	Schedule  protocol.Schedule
+	OnSummary func(summary ShiftSummary)
+
+	stop  chan struct{}
+	done  chan struct{}
+	mutex sync.Mutex
+}
+
+/*
+Starts the job in background
+*/
+func (j *SummaryJob) Start() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.stop != nil {
+		return
+	}
+	j.stop = make(chan struct{})
+	j.done = make(chan struct{})
+	go j.run(j.stop, j.done)
+}
+
+/*
+Stops the job and waits for the background routine to end.
+The current period is left open
+*/
+func (j *SummaryJob) Stop() {
+	j.mutex.Lock()
+	stop, done := j.stop, j.done
+	j.stop, j.done = nil, nil
+	j.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+/*
+Job loop executed until stop is closed
+*/
+func (j *SummaryJob) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		next := j.Schedule.Next(time.Now())
+		if next.IsZero() {
+			<-stop
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		summary := j.Collector.Close(next)
+		if j.OnSummary != nil {
+			j.OnSummary(summary)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package fleet_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/fleet"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestSummaryCollector(t *testing.T) {
+	start := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	collector := fleet.NewSummaryCollector(start)
+	collector.Limits["sector1/channel 1"] = 1e-6
+
+	for _, value := range []float64{5e-7, 2e-6, 3e-6, 4e-7, 1.5e-6} {
+		collector.Add("sector1", []protocol.PressureReading{
+			{Channel: 1, Value: value, Status: "OK"},
+			{Channel: 2, Status: "Sensor is not connected"},
+		})
+	}
+	alarms := fleet.NewAlarmAggregator()
+	alarms.OnEvent = collector.Record
+	alarms.Raise("sector1", "relay 1", fleet.Warning, "relay 1 activated")
+	alarms.Raise("sector1", "relay 1", fleet.Critical, "relay 1 activated")
+	alarms.Raise("sector1", "communication", fleet.Critical, "timeout")
+	alarms.Clear("sector1", "communication")
+
+	summary := collector.Close(start.Add(8 * time.Hour))
+	if len(summary.Channels) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(summary.Channels))
+	}
+	channel := summary.Channels[0]
+	if channel.Min != 4e-7 || channel.Max != 3e-6 || channel.Samples != 5 || channel.Excursions != 2 {
+		t.Errorf("unexpected channel summary %+v", channel)
+	}
+	if summary.Channels[1].Invalid != 5 {
+		t.Errorf("expected 5 invalid readings, got %d", summary.Channels[1].Invalid)
+	}
+	if len(summary.Alarms) != 2 || summary.Alarms[0].Key != "sector1/communication" || summary.Alarms[1].Raised != 2 {
+		t.Errorf("unexpected alarm summaries %+v", summary.Alarms)
+	}
+	if !strings.Contains(summary.Markdown(), "| critical | sector1 | relay 1 | 2 | 0 | 0 | yes |") {
+		t.Errorf("unexpected Markdown:\n%s", summary.Markdown())
+	}
+
+	next := collector.Summary(start.Add(9 * time.Hour))
+	if len(next.Channels) != 0 || len(next.Alarms) != 1 || !next.Alarms[0].Active {
+		t.Errorf("expected only the active alarm carried over, got %+v", next)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package fleet
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Multi-port terminal server whose serial ports are reached on
+consecutive TCP ports. Port n (from 1) is the transport named
+"<server>/<n>", e.g. ts1/3, at FirstPort+n-1. With the rfc2217
+protocol, BaudRate and Parity are set on every serial port
+*/
+type TerminalServer struct {
+	Host      string               `yaml:"host"`
+	FirstPort uint                 `yaml:"first_port"`         // TCP port of serial port 1, e.g. 4001
+	Ports     int                  `yaml:"ports"`              // Number of serial ports
+	Protocol  string               `yaml:"protocol,omitempty"` // tcp or rfc2217, default is tcp
+	BaudRate  int                  `yaml:"baud_rate,omitempty"`
+	Parity    string               `yaml:"parity,omitempty"`
+	Timeout   time.Duration        `yaml:"timeout,omitempty"`
+	TLS       *protocol.TLSOptions `yaml:"tls,omitempty"` // Shared by all ports
+
+	Reconnect *protocol.ReconnectPolicy `yaml:"reconnect,omitempty"` // Shared by all ports
+}
+
+/*
+Returns the transports of the serial ports of the server
+*/
+func (s TerminalServer) transports(name string) (map[string]Transport, error) {
+	if s.Host == "" || s.FirstPort == 0 || s.Ports < 1 {
+		return nil, fmt.Errorf("terminal server %s needs a host, a first port and a number of ports", name)
+	}
+	if s.FirstPort+uint(s.Ports)-1 > 65535 {
+		return nil, fmt.Errorf("terminal server %s: ports exceed 65535", name)
+	}
+	kind := s.Protocol
+	if kind == "" {
+		kind = "tcp"
+	}
+	if kind != "tcp" && kind != "rfc2217" {
+		return nil, fmt.Errorf("terminal server %s: protocol must be tcp or rfc2217, got %q", name, kind)
+	}
+	transports := make(map[string]Transport, s.Ports)
+	for idx := range s.Ports {
+		transports[fmt.Sprintf("%s/%d", name, idx+1)] = Transport{
+			Protocol:  kind,
+			Host:      s.Host,
+			Port:      s.FirstPort + uint(idx),
+			BaudRate:  s.BaudRate,
+			Parity:    s.Parity,
+			TLS:       s.TLS,
+			Timeout:   s.Timeout,
+			Reconnect: s.Reconnect,
+		}
+	}
+	return transports, nil
+}
+
+/*
+Returns the transports of the manifest together with the ones
+of the terminal server ports
+*/
+func (m Manifest) AllTransports() (map[string]Transport, error) {
+	transports := make(map[string]Transport, len(m.Transports))
+	for name, transport := range m.Transports {
+		transports[name] = transport
+	}
+	for name, server := range m.TerminalServers {
+		ports, err := server.transports(name)
+		if err != nil {
+			return nil, err
+		}
+		for port, transport := range ports {
+			if _, ok := transports[port]; ok {
+				return nil, fmt.Errorf("transport %s is defined twice", port)
+			}
+			transports[port] = transport
+		}
+	}
+	return transports, nil
+}
+
+/*
+State of a transport and of the devices reached through it
+*/
+type PortState struct {
+	Transport  string   `json:"transport"`
+	Host       string   `json:"host,omitempty"`
+	Port       uint     `json:"port,omitempty"`
+	Connected  bool     `json:"connected"`
+	Devices    []string `json:"devices"`
+	Reconnects uint64   `json:"reconnects"` // Of all devices of the transport
+	LastError  string   `json:"last_error,omitempty"`
+}
+
+/*
+Returns the state of every transport used by a device, sorted
+by name
+*/
+func (f *Fleet) PortStates() []PortState {
+	states := make(map[string]*PortState)
+	lastErrors := make(map[string]time.Time)
+	for _, device := range f.Devices {
+		state, ok := states[device.Transport]
+		if !ok {
+			transport := f.transports[device.Transport]
+			state = &PortState{
+				Transport: device.Transport,
+				Host:      transport.Host,
+				Port:      transport.Port,
+				Connected: f.buses[device.Transport].IsConnected(),
+			}
+			states[device.Transport] = state
+		}
+		stats := device.Device.Stats()
+		state.Devices = append(state.Devices, device.Name)
+		state.Reconnects += stats.Reconnects
+		if stats.LastError != "" && stats.LastErrorTime.After(lastErrors[device.Transport]) {
+			state.LastError = stats.LastError
+			lastErrors[device.Transport] = stats.LastErrorTime
+		}
+	}
+
+	result := make([]PortState, 0, len(states))
+	for _, state := range states {
+		result = append(result, *state)
+	}
+	slices.SortFunc(result, func(x, y PortState) int {
+		return cmp.Compare(x.Transport, y.Transport)
+	})
+	return result
+}
@@ -0,0 +1,265 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package framelog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+File layout: the 8 byte magic followed by records made of the
+signed varint time delta in microseconds from the previous
+record (from the Unix epoch for the first one), one direction
+byte, the uvarint payload length and the payload
+*/
+const magic = "MKSFLOG1"
+
+// Maximum payload accepted by the reader, frames of the device
+// are a few tens of bytes
+const maxPayload = 1 << 16
+
+var ErrInvalidFormat = errors.New("invalid frame log format")
+
+// Log ending in the middle of a record, e.g. when the process
+// crashed while writing it
+var errTruncatedRecord = fmt.Errorf("%w: truncated record", ErrInvalidFormat)
+
+type Direction byte
+
+const (
+	Sent Direction = iota
+	Received
+)
+
+func (d Direction) String() string {
+	if d == Sent {
+		return "TX"
+	}
+	return "RX"
+}
+
+type Frame struct {
+	Time      time.Time
+	Direction Direction
+	Data      []byte
+}
+
+/*
+Append-only writer of frames
+*/
+type Writer struct {
+	writer *bufio.Writer
+	file   *os.File
+	last   int64 // Time of the last record in microseconds
+	buffer [2*binary.MaxVarintLen64 + 1]byte
+	mutex  sync.Mutex
+}
+
+/*
+Creates a writer that starts a new log on w
+*/
+func NewWriter(w io.Writer) (*Writer, error) {
+	writer := &Writer{writer: bufio.NewWriter(w)}
+	if _, err := writer.writer.WriteString(magic); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+/*
+Opens a log file for appending, creating it if needed. The
+existing records are scanned to continue the time deltas. A
+last record cut short, e.g. by a crash while it was written,
+is removed so the log continues after the last complete one
+*/
+func Open(path string) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		writer, err := NewWriter(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		writer.file = file
+		return writer, nil
+	}
+
+	reader, err := NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	for {
+		_, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, errTruncatedRecord) {
+			err = file.Truncate(reader.offset)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return &Writer{writer: bufio.NewWriter(file), file: file, last: reader.last}, nil
+}
+
+/*
+Appends a frame to the log
+*/
+func (w *Writer) Write(frame Frame) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := frame.Time.UnixMicro()
+	n := binary.PutVarint(w.buffer[:], now-w.last)
+	w.buffer[n] = byte(frame.Direction)
+	n++
+	n += binary.PutUvarint(w.buffer[n:], uint64(len(frame.Data)))
+	if _, err := w.writer.Write(w.buffer[:n]); err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(frame.Data); err != nil {
+		return err
+	}
+	w.last = now
+	return nil
+}
+
+/*
+Writes the buffered frames to the underlying writer
+*/
+func (w *Writer) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.writer.Flush()
+}
+
+/*
+Flushes the buffered frames and closes the file, if the writer
+was created with Open
+*/
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if w.file != nil {
+		err = errors.Join(err, w.file.Close())
+	}
+	return err
+}
+
+/*
+Sequential reader of frames
+*/
+type Reader struct {
+	reader *countingReader
+	last   int64
+	offset int64 // End of the last complete record, header included
+}
+
+/*
+Buffered reader counting the bytes read
+*/
+type countingReader struct {
+	reader *bufio.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.reader.ReadByte()
+	if err == nil {
+		c.count++
+	}
+	return b, err
+}
+
+/*
+Creates a reader and validates the log header
+*/
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{reader: &countingReader{reader: bufio.NewReader(r)}}
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(reader.reader, header); err != nil {
+		return nil, ErrInvalidFormat
+	}
+	if string(header) != magic {
+		return nil, ErrInvalidFormat
+	}
+	reader.offset = reader.reader.count
+	return reader, nil
+}
+
+/*
+Returns the next frame, or io.EOF at the end of the log
+*/
+func (r *Reader) Next() (Frame, error) {
+	var frame Frame
+
+	delta, err := binary.ReadVarint(r.reader)
+	if err == io.EOF {
+		return frame, err
+	}
+	if err != nil {
+		return frame, truncated(err)
+	}
+	direction, err := r.reader.ReadByte()
+	if err != nil {
+		return frame, truncated(err)
+	}
+	size, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return frame, truncated(err)
+	}
+	if direction > byte(Received) || size > maxPayload {
+		return frame, ErrInvalidFormat
+	}
+	frame.Data = make([]byte, size)
+	if _, err := io.ReadFull(r.reader, frame.Data); err != nil {
+		return frame, truncated(err)
+	}
+
+	r.last += delta
+	r.offset = r.reader.count
+	frame.Time = time.UnixMicro(r.last)
+	frame.Direction = Direction(direction)
+	return frame, nil
+}
+
+/*
+Reports a log ending in the middle of a record
+*/
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errTruncatedRecord
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package framelog_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestAppendAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bus.flog")
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	frames := []framelog.Frame{
+		{Time: start, Direction: framelog.Sent, Data: []byte("@001PR1?;FF")},
+		{Time: start.Add(35 * time.Millisecond), Direction: framelog.Received, Data: []byte("@001ACK1.23E-07;FF")},
+	}
+
+	// Each frame is written by a separate writer to exercise appending
+	for _, frame := range frames {
+		log, err := framelog.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := log.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+		if err := log.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := framelog.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range frames {
+		frame, err := reader.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !frame.Time.Equal(expected.Time) || frame.Direction != expected.Direction || !bytes.Equal(frame.Data, expected.Data) {
+			t.Fatalf("expected %v, got %v", expected, frame)
+		}
+	}
+
+	replay, err := framelog.NewReplay(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := &protocol.MKS937B{Communication: replay, Address: 1}
+	device.Connect()
+	reading, err := device.GetPressure(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reading.Value != 1.23e-7 || reading.Status != "OK" {
+		t.Fatalf("unexpected reading %+v", reading)
+	}
+	if _, err := device.GetPressure(2); err == nil {
+		t.Fatal("expected an error once the log is exhausted")
+	}
+}
+
+func TestOpenCrashedLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bus.flog")
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	sent := framelog.Frame{Time: start, Direction: framelog.Sent, Data: []byte("@001PR1?;FF")}
+	received := framelog.Frame{Time: start.Add(35 * time.Millisecond), Direction: framelog.Received, Data: []byte("@001ACK1.23E-07;FF")}
+
+	log, err := framelog.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Write(sent); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+	complete, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The process crashed in the middle of the reply record
+	log, err = framelog.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Write(received)
+	log.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)-5], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err = framelog.Open(path)
+	if err != nil {
+		t.Fatalf("expected the torn record to be dropped, got %v", err)
+	}
+	if err := log.Write(received); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, complete) {
+		t.Fatal("expected the complete records to be kept")
+	}
+	reader, err := framelog.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []framelog.Frame{sent, received} {
+		frame, err := reader.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !frame.Time.Equal(expected.Time) || !bytes.Equal(frame.Data, expected.Data) {
+			t.Fatalf("expected %v, got %v", expected, frame)
+		}
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("expected the end of the log, got %v", err)
+	}
+}
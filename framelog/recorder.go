@@ -0,0 +1,65 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package framelog
+
+import (
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+/*
+Communication wrapper that logs every frame written to and
+read from the wrapped communication. Logging errors do not
+interrupt the communication and are reported to OnError
+*/
+type Recorder struct {
+	unicomm.Unicomm
+	Log     *Writer
+	OnError func(err error)
+}
+
+/*
+Wraps a communication so its frames are logged, e.g.
+
+	device.Communication = framelog.NewRecorder(device.Communication, log)
+*/
+func NewRecorder(communication unicomm.Unicomm, log *Writer) *Recorder {
+	return &Recorder{Unicomm: communication, Log: log}
+}
+
+func (r *Recorder) Write(message []byte) error {
+	r.record(Sent, message)
+	return r.Unicomm.Write(message)
+}
+
+func (r *Recorder) Read(size uint) ([]byte, error) {
+	response, err := r.Unicomm.Read(size)
+	if len(response) > 0 {
+		r.record(Received, response)
+	}
+	return response, err
+}
+
+func (r *Recorder) ReadUntil(delimiter string) ([]byte, error) {
+	response, err := r.Unicomm.ReadUntil(delimiter)
+	if len(response) > 0 {
+		r.record(Received, response)
+	}
+	return response, err
+}
+
+func (r *Recorder) record(direction Direction, data []byte) {
+	frame := Frame{
+		Time:      time.Now(),
+		Direction: direction,
+		Data:      append([]byte(nil), data...),
+	}
+	if err := r.Log.Write(frame); err != nil && r.OnError != nil {
+		r.OnError(err)
+	}
+}
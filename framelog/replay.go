@@ -0,0 +1,124 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package framelog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+/*
+Communication that plays back a frame log instead of talking
+to a device. Each write must match the next sent frame of the
+log, and reads return the received frames that follow it, so
+a driver repeats the recorded session exactly
+*/
+type Replay struct {
+	frames    []Frame
+	next      int
+	connected bool
+	mutex     sync.Mutex
+}
+
+/*
+Loads all frames of a log to be replayed
+*/
+func NewReplay(r io.Reader) (*Replay, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	replay := &Replay{}
+	for {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			return replay, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		replay.frames = append(replay.frames, frame)
+	}
+}
+
+func (r *Replay) Connect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.connected = true
+	return nil
+}
+
+func (r *Replay) Disconnect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.connected = false
+	return nil
+}
+
+func (r *Replay) IsConnected() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.connected
+}
+
+func (r *Replay) Write(message []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.next >= len(r.frames) {
+		return io.EOF
+	}
+	frame := r.frames[r.next]
+	if frame.Direction != Sent || !bytes.Equal(frame.Data, message) {
+		return fmt.Errorf("replay diverged at frame %d, expected %s %q got TX %q",
+			r.next, frame.Direction, frame.Data, message)
+	}
+	r.next++
+	return nil
+}
+
+func (r *Replay) Read(size uint) ([]byte, error) {
+	return r.read()
+}
+
+func (r *Replay) ReadUntil(delimiter string) ([]byte, error) {
+	return r.read()
+}
+
+/*
+Returns the next received frame
+*/
+func (r *Replay) read() ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.next >= len(r.frames) {
+		return nil, io.EOF
+	}
+	frame := r.frames[r.next]
+	if frame.Direction != Received {
+		return nil, fmt.Errorf("replay diverged at frame %d, expected %s %q got a read",
+			r.next, frame.Direction, frame.Data)
+	}
+	r.next++
+	return frame.Data, nil
+}
+
+/*
+Returns the number of frames not replayed yet
+*/
+func (r *Replay) Remaining() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.frames) - r.next
+}
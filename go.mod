@@ -5,6 +5,7 @@ go 1.25.0
 require (
 	github.com/devicehub-go/unicomm v0.0.0-20251128162816-bc0c3bed619d
 	go.bug.st/serial v1.6.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -1,7 +1,7 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 23rd, 2025
-Last update: September 23rd, 2025
+Last update: October 17th, 2026
 */
 
 package mks937b
@@ -17,10 +17,15 @@ with the device through the selected protocol.
 
 For MKS 937B some usual character format are: 8 data bits,
 1 stop bit, and no parity. Baudrate by default is 9600
+
+Driver behavior can be customized with protocol options,
+e.g. protocol.WithReadOnly()
 */
-func New(address int, options unicomm.Options) *protocol.MKS937B {
-	return &protocol.MKS937B{
+func New(address int, options unicomm.Options, opts ...protocol.Option) *protocol.MKS937B {
+	device := &protocol.MKS937B{
 		Communication: unicomm.New(options),
 		Address:       address,
 	}
+	device.Apply(opts...)
+	return device
 }
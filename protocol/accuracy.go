@@ -0,0 +1,66 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+// Repeatability of each sensor type, relative to the indicated
+// pressure at constant temperature, from the controller
+// specifications. Absolute accuracy also depends on the gas and
+// the calibration and is usually worse
+var SensorRepeatability = map[string]float64{
+	"CC": 0.05,
+	"HC": 0.05,
+	"PR": 0.05,
+	"CP": 0.05,
+	"CM": 0.0025,
+}
+
+/*
+Returns the reading with the uncertainty of a sensor type
+attached, given the pressure unit of the value. Readings
+outside the measuring range of the sensor are flagged, since
+e.g. 1e-9 Torr from a Pirani is meaningless. Readings that are
+not OK or from unknown sensor types only get the sensor type
+*/
+func (r PressureReading) WithAccuracy(sensor string, unit string) PressureReading {
+	r.Sensor = sensor
+	r.Uncertainty = 0
+	r.OutOfRange = false
+	if r.Status != "OK" {
+		return r
+	}
+	if repeatability, ok := SensorRepeatability[sensor]; ok {
+		r.Uncertainty = repeatability * r.Value
+	}
+	factor, known := torrConversion[unit]
+	if limits, ok := sensorRange[sensor]; ok && known {
+		r.OutOfRange = r.Value < limits[0]*factor || limits[1]*factor < r.Value
+	}
+	return r
+}
+
+/*
+Reads the pressures from all device channels with the sensor
+type, uncertainty and range flag of each reading attached
+*/
+func (m *MKS937B) GetPressuresWithAccuracy() ([]PressureReading, error) {
+	sensors, err := m.GetSensorTypes()
+	if err != nil {
+		return nil, err
+	}
+	unit, err := m.readingUnit()
+	if err != nil {
+		return nil, err
+	}
+	pressures, err := m.GetPressures()
+	if err != nil {
+		return nil, err
+	}
+	for idx := range pressures {
+		pressures[idx] = pressures[idx].WithAccuracy(sensors[idx], unit)
+	}
+	return pressures, nil
+}
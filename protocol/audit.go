@@ -0,0 +1,220 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type AuditFinding struct {
+	Channel  int    `json:"channel" yaml:"channel"`
+	Relay    int    `json:"relay" yaml:"relay"`       // 0 for control and protection findings
+	Severity string `json:"severity" yaml:"severity"` // error or warning
+	Message  string `json:"message" yaml:"message"`
+}
+
+// Front panel name of each channel
+var channelNames = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// Measuring range in Torr for each sensor type
+var sensorRange = map[string][2]float64{
+	"CC": {1e-11, 1e-2},
+	"HC": {1e-10, 1e-2},
+	"PR": {5e-4, 4e2},
+	"CP": {1e-3, 1e3},
+}
+
+/*
+Compares the configured control, protection and relay set
+points against the current readings and sensor types, and
+reports configurations that are impossible or that would
+trip as soon as they are armed.
+
+Errors flag settings that can never work as intended, while
+warnings flag settings that would act immediately. Only the
+channels and relays of the variant are audited, and modules or
+controlling channels outside its readings are reported with
+ErrUnexpectedReply
+*/
+func (m *MKS937B) AuditSetpoints() ([]AuditFinding, error) {
+	var findings []AuditFinding
+
+	unit, err := m.readingUnit()
+	if err != nil {
+		return nil, err
+	}
+	factor, ok := torrConversion[unit]
+	if !ok {
+		return nil, NewErrInvalidUnit(unit)
+	}
+	modules, err := m.GetModuleTypes()
+	if err != nil {
+		return nil, err
+	}
+	sensors, err := m.GetSensorTypes()
+	if err != nil {
+		return nil, err
+	}
+	pressures, err := m.GetPressures()
+	if err != nil {
+		return nil, err
+	}
+
+	ionGauges := ionGaugeChannels(modules)
+	variant := m.Variant()
+	for channel := range variant.ChannelsOf(ControlCommands) {
+		if _, ok := ionGauges[channel]; !ok {
+			continue
+		}
+		channelFindings, err := m.auditControl(channel, pressures)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, channelFindings...)
+	}
+
+	for relay := range variant.ChannelsOf(RelayCommands) {
+		slot := (relay - 1) / 4
+		if slot >= len(modules) || modules[slot] == "NC" {
+			continue
+		}
+		channel := RelayChannel(relay, modules[slot])
+		if channel > len(sensors) || channel > len(pressures) {
+			// The module does not fit the channels of the variant
+			return nil, NewErrUnexpectedReply("MT", strings.Join(modules, ","))
+		}
+		sensor := sensors[channel-1]
+		if modules[slot] == "HC" || modules[slot] == "CC" {
+			sensor = modules[slot]
+		}
+		config, err := m.GetRelayConfig(relay)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, auditRelay(channel, sensor, factor, config, pressures[channel-1])...)
+	}
+	return findings, nil
+}
+
+/*
+Audits the control and protection set points of an ionization
+gauge on a control channel of the variant
+*/
+func (m *MKS937B) auditControl(channel int, pressures []PressureReading) ([]AuditFinding, error) {
+	var findings []AuditFinding
+	add := func(severity string, format string, args ...any) {
+		findings = append(findings, AuditFinding{
+			Channel:  channel,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if channel > len(pressures) {
+		return nil, NewErrUnexpectedReply("PRZ", fmt.Sprintf("%d pressures", len(pressures)))
+	}
+	protection, err := m.GetProtectionTarget(channel)
+	if err != nil {
+		return nil, err
+	}
+	gauge := pressures[channel-1]
+	if protection > 0 && gauge.Status == "OK" && gauge.Value > protection {
+		add("warning", "pressure %.2E is above the protection set point %.2E", gauge.Value, protection)
+	}
+
+	source, err := m.GetControlChannelStatus(channel)
+	if err != nil {
+		return nil, err
+	}
+	sourceIdx := slices.Index(channelNames, source)
+	if sourceIdx < 0 {
+		return findings, nil
+	}
+	if sourceIdx >= len(pressures) {
+		// The controlling channel is not a channel of the variant
+		return nil, NewErrUnexpectedReply(fmt.Sprintf("CSE%d", channel), source)
+	}
+	if sourceIdx == channel-1 {
+		add("error", "gauge is controlled by its own channel %s", source)
+	}
+
+	target, err := m.GetTarget(channel)
+	if err != nil {
+		return nil, err
+	}
+	hysteresis, err := m.GetHysterisesTarget(channel)
+	if err != nil {
+		return nil, err
+	}
+	if hysteresis < 1.2*target {
+		add("error", "control hysteresis %.2E is below 1.2 x control set point %.2E", hysteresis, target)
+	}
+
+	mode, err := m.GetControlMode(channel)
+	if err != nil {
+		return nil, err
+	}
+	controlling := pressures[sourceIdx]
+	if mode == "OFF" || controlling.Status != "OK" {
+		return findings, nil
+	}
+	power, err := m.GetPowerStatus(channel)
+	if err != nil {
+		return nil, err
+	}
+	if power && controlling.Value > hysteresis {
+		add("warning", "%s pressure %.2E is above the control hysteresis, gauge will be turned OFF", source, controlling.Value)
+	}
+	if !power && mode == "AUTO" && controlling.Value < target {
+		add("warning", "%s pressure %.2E is below the control set point, gauge will be turned ON", source, controlling.Value)
+	}
+	return findings, nil
+}
+
+/*
+Audits a relay configuration against its sensor type and the
+current pressure of its channel
+*/
+func auditRelay(channel int, sensor string, factor float64, config RelayConfig, pressure PressureReading) []AuditFinding {
+	var findings []AuditFinding
+	add := func(severity string, format string, args ...any) {
+		findings = append(findings, AuditFinding{
+			Channel:  channel,
+			Relay:    config.Relay,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if (sensor == "HC" || sensor == "CC") && config.Direction != "BELOW" {
+		add("error", "relays driven by %s sensors must have direction BELOW", sensor)
+	}
+	if config.Direction == "BELOW" && config.Hysteresis <= config.SetPoint {
+		add("error", "hysteresis %.2E must be above the set point %.2E for direction BELOW", config.Hysteresis, config.SetPoint)
+	}
+	if config.Direction == "ABOVE" && config.Hysteresis >= config.SetPoint {
+		add("error", "hysteresis %.2E must be below the set point %.2E for direction ABOVE", config.Hysteresis, config.SetPoint)
+	}
+	if limits, ok := sensorRange[sensor]; ok {
+		low, high := limits[0]*factor, limits[1]*factor
+		if config.SetPoint < low || high < config.SetPoint {
+			add("error", "set point %.2E is outside the %s range %.2E to %.2E", config.SetPoint, sensor, low, high)
+		}
+	}
+
+	if config.Enable == "ENABLE" || pressure.Status != "OK" {
+		return findings
+	}
+	below := config.Direction == "BELOW" && pressure.Value < config.SetPoint
+	above := config.Direction == "ABOVE" && pressure.Value > config.SetPoint
+	if below || above {
+		add("warning", "pressure %.2E already satisfies the set point, relay activates as soon as it is enabled", pressure.Value)
+	}
+	return findings
+}
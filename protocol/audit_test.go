@@ -0,0 +1,125 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func auditRelayFrames(relay string, setPoint, hysteresis, direction, enable string) []string {
+	return []string{
+		"@001SP" + relay + "?;FF", "@001ACK" + setPoint + ";FF",
+		"@001SH" + relay + "?;FF", "@001ACK" + hysteresis + ";FF",
+		"@001SD" + relay + "?;FF", "@001ACK" + direction + ";FF",
+		"@001EN" + relay + "?;FF", "@001ACK" + enable + ";FF",
+	}
+}
+
+func TestAuditSetpoints(t *testing.T) {
+	relay := auditRelayFrames
+	pairs := []string{
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001MT?;FF", "@001ACKCC,NC,NC;FF",
+		"@001STA?;FF", "@001ACKCC,NC;FF",
+		"@001STB?;FF", "@001ACKNC,NC;FF",
+		"@001STC?;FF", "@001ACKNC,NC;FF",
+		"@001PRZ?;FF", "@001ACK5.00E-08 NOGAUGE NOGAUGE NOGAUGE NOGAUGE NOGAUGE;FF",
+		"@001PRO1?;FF", "@001ACK1.00E-08;FF",
+		"@001CSE1?;FF", "@001ACKA1;FF",
+		"@001CSP1?;FF", "@001ACK2.00E-03;FF",
+		"@001CHP1?;FF", "@001ACK2.10E-03;FF",
+		"@001CTL1?;FF", "@001ACKAUTO;FF",
+		"@001CP1?;FF", "@001ACKON;FF",
+	}
+	pairs = append(pairs, relay("1", "1.00E-06", "2.00E-06", "BELOW", "ENABLE")...)
+	pairs = append(pairs, relay("2", "1.00E-06", "5.00E-07", "ABOVE", "ENABLE")...)
+	pairs = append(pairs, relay("3", "1.00E-01", "2.00E-01", "BELOW", "ENABLE")...)
+	pairs = append(pairs, relay("4", "1.00E-06", "2.00E-06", "BELOW", "CLEAR")...)
+
+	// The audit only reads, so it runs on a read-only device
+	device := replayDevice(t, pairs...)
+	device.Apply(protocol.WithReadOnly())
+	findings, err := device.AuditSetpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		relay    int
+		severity string
+	}{
+		{0, "warning"}, // Pressure above the protection set point
+		{0, "error"},   // Controlled by its own channel
+		{0, "error"},   // Hysteresis below 1.2 x set point
+		{2, "error"},   // Direction ABOVE on a CC sensor
+		{3, "error"},   // Set point outside the CC range
+		{4, "warning"}, // Activates as soon as it is enabled
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("expected %d findings, got %+v", len(expected), findings)
+	}
+	for idx, finding := range findings {
+		if finding.Channel != 1 || finding.Relay != expected[idx].relay || finding.Severity != expected[idx].severity {
+			t.Errorf("finding %d: expected relay %d %s, got %+v", idx, expected[idx].relay, expected[idx].severity, finding)
+		}
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("expected every frame to be replayed, %d left", remaining)
+	}
+}
+
+func TestAuditSetpointsError(t *testing.T) {
+	device := replayDevice(t,
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001MT?;FF", "@002ACKCC,NC,NC;FF",
+	)
+	if findings, err := device.AuditSetpoints(); err == nil || findings != nil {
+		t.Errorf("expected the audit to stop at the failed query, got %+v, %v", findings, err)
+	}
+}
+
+func TestAuditSetpointsThreeChannel(t *testing.T) {
+	pairs := []string{
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001MT?;FF", "@001ACKCC,NC,NC;FF",
+		"@001STA?;FF", "@001ACKCC,NC;FF",
+		"@001STB?;FF", "@001ACKPR,NC;FF",
+		"@001PRZ?;FF", "@001ACK5.00E-09 NOGAUGE 1.00E-03;FF",
+		"@001PRO1?;FF", "@001ACK1.00E-05;FF",
+		"@001CSE1?;FF", "@001ACKB1;FF",
+		"@001CSP1?;FF", "@001ACK2.00E-03;FF",
+		"@001CHP1?;FF", "@001ACK5.00E-03;FF",
+		"@001CTL1?;FF", "@001ACKAUTO;FF",
+		"@001CP1?;FF", "@001ACKON;FF",
+	}
+	for _, relay := range []string{"1", "2", "3", "4"} {
+		pairs = append(pairs, auditRelayFrames(relay, "1.00E-06", "2.00E-06", "BELOW", "ENABLE")...)
+	}
+	device := replayDevice(t, pairs...)
+	device.Apply(protocol.WithVariant(protocol.ThreeChannelVariant))
+	findings, err := device.AuditSetpoints()
+	if err != nil || len(findings) != 0 {
+		t.Fatalf("AuditSetpoints() = %+v, %v", findings, err)
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("expected every frame to be replayed, %d left", remaining)
+	}
+}
+
+func TestAuditSetpointsOutsideVariant(t *testing.T) {
+	device := replayDevice(t,
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001MT?;FF", "@001ACKCC,PR,NC;FF",
+		"@001STA?;FF", "@001ACKCC,NC;FF",
+		"@001STB?;FF", "@001ACKPR,NC;FF",
+		"@001PRZ?;FF", "@001ACK5.00E-09 NOGAUGE 1.00E-03;FF",
+		"@001PRO1?;FF", "@001ACK1.00E-05;FF",
+		"@001CSE1?;FF", "@001ACKB2;FF",
+	)
+	device.Apply(protocol.WithVariant(protocol.ThreeChannelVariant))
+	var unexpected *protocol.ErrUnexpectedReply
+	if findings, err := device.AuditSetpoints(); !errors.As(err, &unexpected) || findings != nil {
+		t.Errorf("AuditSetpoints() = %+v, %v, want ErrUnexpectedReply", findings, err)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"sync"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+// Locks of the communications by identity. Devices at different
+// addresses of a RS-485 bus share the communication, and each one
+// has its own mutex, so the bus needs a lock of its own for a
+// frame and its reply not to be interleaved with another device's
+var busLocks sync.Map
+
+/*
+Takes the lock of a communication, shared by every device using
+it, and returns the function releasing it
+*/
+func lockBus(communication unicomm.Unicomm) func() {
+	lock, _ := busLocks.LoadOrStore(communication, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+/*
+Takes the lock of the communication of the device for a
+transaction, unless the caller already holds it
+*/
+func (m *MKS937B) lockBus() func() {
+	if m.busHeld {
+		return func() {}
+	}
+	return lockBus(m.Communication)
+}
+
+/*
+Returns a device at an address of a bus whose lock is held by
+the caller, e.g. for a discovery or a baud rate migration
+*/
+func heldBusDevice(bus unicomm.Unicomm, address int) *MKS937B {
+	return &MKS937B{Communication: bus, Address: address, busHeld: true}
+}
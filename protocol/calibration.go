@@ -0,0 +1,258 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Calibration methods recorded by the driver
+const (
+	CalibrationZero        = "zero"
+	CalibrationAtmosphere  = "atmosphere"
+	CalibrationSensitivity = "sensitivity"
+)
+
+// Range of the ATM calibration pressure, in the device unit
+const (
+	atmosphereMin = 100.0
+	atmosphereMax = 1000.0
+)
+
+/*
+Calibration metadata of the gauge on a channel: when it was
+last calibrated, how, and how often it must be
+*/
+type CalibrationRecord struct {
+	Channel        int           `json:"channel" yaml:"channel"`
+	LastCalibrated time.Time     `json:"last_calibrated" yaml:"last_calibrated"`
+	Method         string        `json:"method,omitempty" yaml:"method,omitempty"`     // zero, atmosphere, sensitivity or set by the application
+	Interval       time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"` // 0 disables the due date, e.g. 8760h
+}
+
+/*
+Returns the date the gauge is due for calibration, or the zero
+time if it has no interval
+*/
+func (r CalibrationRecord) Due() time.Time {
+	if r.Interval <= 0 {
+		return time.Time{}
+	}
+	return r.LastCalibrated.Add(r.Interval)
+}
+
+/*
+Returns true if the gauge is due for calibration at a time
+*/
+func (r CalibrationRecord) IsDue(now time.Time) bool {
+	due := r.Due()
+	return !due.IsZero() && !now.Before(due)
+}
+
+/*
+Calibration records by channel, stored in a local YAML file
+*/
+type Calibrations map[int]CalibrationRecord
+
+/*
+Loads the calibration records of a file. A missing file gives
+no record
+*/
+func LoadCalibrations(path string) (Calibrations, error) {
+	calibrations := make(Calibrations)
+	if err := LoadYAML(path, &calibrations); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for channel, record := range calibrations {
+		if channel < 1 || channel > len(channelNames) {
+			return nil, fmt.Errorf("%w: calibration of unknown channel %d", ErrInvalidParameter, channel)
+		}
+		record.Channel = channel
+		calibrations[channel] = record
+	}
+	return calibrations, nil
+}
+
+// Loads the calibration records of a file and keeps it up to
+// date as records change, including after the zero, atmosphere
+// and sensitivity calibrations of the driver. The file is not
+// used when it cannot be loaded: the error is returned by
+// CalibrationError and published by CheckCalibrations
+func WithCalibrationFile(path string) Option {
+	return func(m *MKS937B) {
+		if err := m.SetCalibrationFile(path); err != nil {
+			m.calibrationErr = fmt.Errorf("calibration file %s: %w", path, err)
+		}
+	}
+}
+
+/*
+Returns the error of the calibration file of WithCalibrationFile,
+if it could not be loaded
+*/
+func (m *MKS937B) CalibrationError() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.calibrationErr
+}
+
+/*
+Loads the calibration records of a file and saves them to it
+from then on
+*/
+func (m *MKS937B) SetCalibrationFile(path string) error {
+	calibrations, err := LoadCalibrations(path)
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calibrations = calibrations
+	m.calibrationFile = path
+	m.calibrationDue = make(map[int]bool)
+	m.calibrationErr = nil
+	return nil
+}
+
+/*
+Returns the calibration records by channel
+*/
+func (m *MKS937B) Calibrations() Calibrations {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return maps.Clone(m.calibrations)
+}
+
+/*
+Sets the calibration record of a channel, e.g. to register its
+interval or an external calibration, and saves the records
+*/
+func (m *MKS937B) SetCalibration(record CalibrationRecord) error {
+	if err := m.checkChannel(ReadingCommands, record.Channel); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.calibrations == nil {
+		m.calibrations = make(Calibrations)
+	}
+	m.calibrations[record.Channel] = record
+	delete(m.calibrationDue, record.Channel)
+	if m.calibrationFile == "" {
+		return nil
+	}
+	return SaveYAML(m.calibrationFile, m.calibrations)
+}
+
+/*
+Records that the gauge of a channel was calibrated now with a
+method, keeping its interval
+*/
+func (m *MKS937B) RecordCalibration(channel int, method string) error {
+	m.mutex.Lock()
+	record := m.calibrations[channel]
+	m.mutex.Unlock()
+
+	record.Channel = channel
+	record.LastCalibrated = time.Now()
+	record.Method = method
+	return m.SetCalibration(record)
+}
+
+/*
+Returns the records of the gauges due for calibration at a
+time, by channel, and publishes an EventCalibrationDue for each
+of them once, until it is calibrated again. An error loading the
+calibration file is published once as an EventCalibrationDue
+with Err. The watchdog calls it at every probe when its
+CheckCalibrations field is set
+*/
+func (m *MKS937B) CheckCalibrations(now time.Time) []CalibrationRecord {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.calibrationErr != nil && !m.calibrationErrReported {
+		m.calibrationErrReported = true
+		m.publish(Event{
+			Kind:    EventCalibrationDue,
+			Message: "calibration due dates are not tracked",
+			Err:     m.calibrationErr,
+		})
+	}
+
+	var due []CalibrationRecord
+	for _, channel := range slices.Sorted(maps.Keys(m.calibrations)) {
+		record := m.calibrations[channel]
+		if !record.IsDue(now) {
+			continue
+		}
+		due = append(due, record)
+		if m.calibrationDue[channel] {
+			continue
+		}
+		if m.calibrationDue == nil {
+			m.calibrationDue = make(map[int]bool)
+		}
+		m.calibrationDue[channel] = true
+		m.publish(Event{
+			Kind:    EventCalibrationDue,
+			Channel: channel,
+			Message: fmt.Sprintf("channel %d calibration was due on %s", channel, record.Due().Format(time.DateOnly)),
+		})
+	}
+	return due
+}
+
+/*
+Adjusts the zero of the Pirani or Convection Pirani sensor on a
+channel (1 to 6), which must be at a pressure below its
+measuring range, and records the calibration. User calibration
+must be enabled, see RunWithUserCalibration
+*/
+func (m *MKS937B) ZeroSensor(channel int) error {
+	if err := m.checkChannel(ReadingCommands, channel); err != nil {
+		return err
+	}
+	if err := m.execute(fmt.Sprintf("VAC%d", channel)); err != nil {
+		return err
+	}
+	return m.RecordCalibration(channel, CalibrationZero)
+}
+
+/*
+Adjusts the atmosphere reading of the Pirani or Convection
+Pirani sensor on a channel (1 to 6) to the pressure it is
+vented to, in the unit of the readings, and records the
+calibration. The pressure must be between 100 and 1000 in the
+device unit. User calibration must be enabled, see
+RunWithUserCalibration
+*/
+func (m *MKS937B) CalibrateAtmosphere(channel int, pressure float64) error {
+	if err := m.checkChannel(ReadingCommands, channel); err != nil {
+		return err
+	}
+	value, err := m.toDevice(pressure)
+	if err != nil {
+		return err
+	}
+	if value < atmosphereMin || value > atmosphereMax {
+		return NewErrInvalidPressureRange(atmosphereMin, atmosphereMax, value, "(device unit)")
+	}
+	if err := m.setNumber(fmt.Sprintf("ATM%d", channel), value); err != nil {
+		return err
+	}
+	return m.RecordCalibration(channel, CalibrationAtmosphere)
+}
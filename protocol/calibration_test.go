@@ -0,0 +1,89 @@
+package protocol_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestCalibrationTracking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calibrations.yaml")
+	device := replayDevice(t, "@001VAC1!;FF", "@001ACKOK;FF")
+	if err := device.SetCalibrationFile(path); err != nil {
+		t.Fatal(err)
+	}
+	events, unsubscribe := device.Events().Subscribe(4)
+	defer unsubscribe()
+
+	lastYear := time.Now().AddDate(-1, 0, -1)
+	record := protocol.CalibrationRecord{Channel: 1, LastCalibrated: lastYear, Interval: 365 * 24 * time.Hour}
+	if err := device.SetCalibration(record); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if due := device.CheckCalibrations(time.Now()); len(due) != 1 || due[0].Channel != 1 {
+			t.Fatalf("expected channel 1 to be due, got %v", due)
+		}
+	}
+	if event := <-events; event.Kind != protocol.EventCalibrationDue || event.Channel != 1 {
+		t.Errorf("unexpected event %v on channel %d", event.Kind, event.Channel)
+	}
+	if len(events) != 0 {
+		t.Error("expected the due event to be published once")
+	}
+
+	if err := device.ZeroSensor(1); err != nil {
+		t.Fatal(err)
+	}
+	if due := device.CheckCalibrations(time.Now()); len(due) != 0 {
+		t.Errorf("expected no gauge due after the zero calibration, got %v", due)
+	}
+	saved, err := protocol.LoadCalibrations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved[1].Method != protocol.CalibrationZero || saved[1].Interval != record.Interval || !saved[1].LastCalibrated.After(lastYear) {
+		t.Errorf("unexpected saved record %+v", saved[1])
+	}
+}
+
+func TestSensorZeroAndAtmosphere(t *testing.T) {
+	device := replayDevice(t, "@001VAC1!;FF", "@001NAK160;FF", "@001ATM2!7.60E+02;FF", "@001ACK7.60E+02;FF")
+	if err := device.ZeroSensor(1); err == nil {
+		t.Error("expected an error for a NAK reply")
+	}
+	if err := device.CalibrateAtmosphere(2, 50); err == nil {
+		t.Error("expected an error for a pressure below 100")
+	}
+	if err := device.CalibrateAtmosphere(2, 760); err != nil {
+		t.Fatal(err)
+	}
+	if record := device.Calibrations()[2]; record.Method != protocol.CalibrationAtmosphere {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+func TestCalibrationFileError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calibrations.yaml")
+	if err := os.WriteFile(path, []byte("1: [not a record"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	device := replayDevice(t)
+	device.Apply(protocol.WithCalibrationFile(path))
+	if device.CalibrationError() == nil {
+		t.Fatal("expected an error for a corrupt calibration file")
+	}
+	events, unsubscribe := device.Events().Subscribe(4)
+	defer unsubscribe()
+	device.CheckCalibrations(time.Now())
+	device.CheckCalibrations(time.Now())
+	if event := <-events; event.Kind != protocol.EventCalibrationDue || event.Err == nil {
+		t.Errorf("expected the file error to be published, got %v", event)
+	}
+	if len(events) != 0 {
+		t.Error("expected the file error to be published once")
+	}
+}
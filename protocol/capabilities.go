@@ -0,0 +1,170 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import "iter"
+
+/*
+Features of a controller derived from its module layout, so
+applications can adapt to the installed modules instead of
+assuming a fixed layout
+*/
+type Capabilities struct {
+	Modules []string `json:"modules" yaml:"modules"` // Module of slots A, B and C: CC, HC, CM, PR, FC or NC
+	Option  string   `json:"option" yaml:"option"`   // Communication option: NA, PF (PROFIBUS) or PC
+}
+
+/*
+Creates the capabilities of a module layout as returned by
+GetModuleTypes
+*/
+func NewCapabilities(modules []string) Capabilities {
+	var capabilities Capabilities
+	for idx, module := range modules {
+		if idx < 3 {
+			capabilities.Modules = append(capabilities.Modules, module)
+		} else if idx == 3 {
+			capabilities.Option = module
+		}
+	}
+	for len(capabilities.Modules) < 3 {
+		capabilities.Modules = append(capabilities.Modules, "NC")
+	}
+	return capabilities
+}
+
+/*
+Returns the capabilities of the controller from its cached
+module layout, see DeviceInfo
+*/
+func (m *MKS937B) Capabilities() (Capabilities, error) {
+	info, err := m.DeviceInfo()
+	if err != nil {
+		return Capabilities{}, err
+	}
+	return NewCapabilities(info.System.Modules), nil
+}
+
+/*
+Returns the module of the slot of a channel from 1 to 6, or NC
+*/
+func (c Capabilities) Module(channel int) string {
+	if channel < 1 || 6 < channel || len(c.Modules) < 3 {
+		return "NC"
+	}
+	return c.Modules[(channel-1)/2]
+}
+
+func (c Capabilities) ionGauge(channel int) bool {
+	module := c.Module(channel)
+	return channel%2 == 1 && (module == "HC" || module == "CC")
+}
+
+/*
+Returns true if a module can connect a sensor on a channel.
+HC and CC modules have a single sensor, on the first channel of
+their slot
+*/
+func (c Capabilities) HasChannel(channel int) bool {
+	module := c.Module(channel)
+	if module == "NC" {
+		return false
+	}
+	return channel%2 == 1 || (module != "HC" && module != "CC")
+}
+
+/*
+Returns true if the channel has a Hot Cathode module
+*/
+func (c Capabilities) SupportsHotCathode(channel int) bool {
+	return c.ionGauge(channel) && c.Module(channel) == "HC"
+}
+
+/*
+Returns true if the channel has a Cold Cathode module
+*/
+func (c Capabilities) SupportsColdCathode(channel int) bool {
+	return c.ionGauge(channel) && c.Module(channel) == "CC"
+}
+
+/*
+Returns true if the channel supports control and protection
+set points, which only apply to HC and CC sensors
+*/
+func (c Capabilities) SupportsControl(channel int) bool {
+	return c.ionGauge(channel)
+}
+
+/*
+Returns true if the channel supports degas, which only Hot
+Cathodes do
+*/
+func (c Capabilities) SupportsDegas(channel int) bool {
+	return c.SupportsHotCathode(channel)
+}
+
+/*
+Returns true if a combination channel (1 or 2) can be used,
+which needs at least two sensor channels
+*/
+func (c Capabilities) HasCombination(channel int) bool {
+	if channel < 1 || 2 < channel {
+		return false
+	}
+	count := 0
+	for sensor := 1; sensor <= 6; sensor++ {
+		if c.HasChannel(sensor) {
+			count++
+		}
+	}
+	return count >= 2
+}
+
+/*
+Returns the number of relays, four per installed module
+*/
+func (c Capabilities) RelayCount() int {
+	count := 0
+	for _, module := range c.Modules {
+		if module != "NC" {
+			count += 4
+		}
+	}
+	return count
+}
+
+/*
+Returns true if the PROFIBUS option is installed
+*/
+func (c Capabilities) HasProfibus() bool {
+	return c.Option == "PF"
+}
+
+/*
+Iterates over the channels, or relays, of a class of commands
+that are available with the installed modules
+*/
+func (c Capabilities) Channels(class CommandClass) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for channel := range Channels(class) {
+			available := false
+			switch class {
+			case ReadingCommands:
+				available = c.HasChannel(channel)
+			case ControlCommands:
+				available = c.SupportsControl(channel)
+			case CombinationCommands:
+				available = c.HasCombination(channel)
+			case RelayCommands:
+				available = c.Module(2*((channel-1)/4)+1) != "NC"
+			}
+			if available && !yield(channel) {
+				return
+			}
+		}
+	}
+}
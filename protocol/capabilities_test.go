@@ -0,0 +1,75 @@
+package protocol_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestCapabilities(t *testing.T) {
+	capabilities := protocol.NewCapabilities([]string{"HC", "PR", "NC", "PF"})
+
+	if !capabilities.SupportsDegas(1) || capabilities.SupportsDegas(3) || capabilities.SupportsHotCathode(2) {
+		t.Error("expected degas only on channel 1")
+	}
+	if capabilities.RelayCount() != 8 || !capabilities.HasProfibus() || !capabilities.HasCombination(1) {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+	readings := slices.Collect(capabilities.Channels(protocol.ReadingCommands))
+	if !slices.Equal(readings, []int{1, 3, 4}) {
+		t.Errorf("expected reading channels 1, 3 and 4, got %v", readings)
+	}
+	relays := slices.Collect(capabilities.Channels(protocol.RelayCommands))
+	if !slices.Equal(relays, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("expected relays 1 to 8, got %v", relays)
+	}
+}
+
+func TestThreeChannelVariant(t *testing.T) {
+	device := replayDevice(t,
+		"@001PRZ?;FF", "@001ACK1.00E-03 ATM 4.20E-08;FF",
+		"@001PRZ?;FF", "@001ACK1.00E-03 ATM 4.20E-08;FF",
+		"@001STA?;FF", "@001ACKPR,PR;FF",
+		"@001STB?;FF", "@001ACKCC,NC;FF",
+	)
+	// The 3-channel layout is not documented, so it is never detected
+	var unexpected *protocol.ErrUnexpectedReply
+	if _, err := device.GetPressures(); !errors.As(err, &unexpected) {
+		t.Fatalf("GetPressures() = %v, want ErrUnexpectedReply", err)
+	}
+	device.Apply(protocol.WithVariant(protocol.ThreeChannelVariant))
+	pressures, err := device.GetPressures()
+	if err != nil || len(pressures) != 3 || pressures[2].Value != 4.2e-08 {
+		t.Fatalf("GetPressures() = %+v, %v", pressures, err)
+	}
+	if name := device.Variant().Name; name != protocol.ThreeChannelVariant.Name {
+		t.Fatalf("Variant() = %s, want %s", name, protocol.ThreeChannelVariant.Name)
+	}
+	sensors, err := device.GetSensorTypes()
+	if err != nil || !slices.Equal(sensors, []string{"PR", "PR", "CC"}) {
+		t.Fatalf("GetSensorTypes() = %v, %v", sensors, err)
+	}
+
+	var invalidChannel *protocol.ErrInvalidChannel
+	if _, err := device.GetPressure(4); !errors.As(err, &invalidChannel) {
+		t.Errorf("GetPressure(4) = %v, want ErrInvalidChannel", err)
+	}
+	var invalidControl *protocol.ErrInvalidChannelControl
+	if _, err := device.GetControlMode(5); !errors.As(err, &invalidControl) {
+		t.Errorf("GetControlMode(5) = %v, want ErrInvalidChannelControl", err)
+	} else if want := "channel must be an integer value among 1 or 3, got 5"; err.Error() != want {
+		t.Errorf("GetControlMode(5) = %q, want %q", err, want)
+	}
+	if err := device.SetControlMode(5, "AUTO"); !errors.As(err, &invalidControl) {
+		t.Errorf("SetControlMode(5) = %v, want ErrInvalidChannelControl", err)
+	}
+	var invalidRelay *protocol.ErrInvalidRelay
+	if _, err := device.GetRelaySetPoint(9); !errors.As(err, &invalidRelay) || invalidRelay.MaxRelay != 8 {
+		t.Errorf("GetRelaySetPoint(9) = %v, want ErrInvalidRelay up to 8", err)
+	}
+	if _, err := device.GetCombination(2); !errors.As(err, &invalidChannel) {
+		t.Errorf("GetCombination(2) = %v, want ErrInvalidChannel", err)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"iter"
+	"slices"
+)
+
+type CommandClass int
+
+const (
+	ReadingCommands     CommandClass = iota // Pressure readings (PR), channels 1 to 6
+	ControlCommands                         // Sensor control (CSP, CTL, PRO...), channels 1, 3 and 5
+	CombinationCommands                     // Combination channels (PC, SPC), 1 and 2
+	RelayCommands                           // Relays (SP, SH, SD, EN), 1 to 12
+)
+
+func (c CommandClass) String() string {
+	names := []string{"reading", "control", "combination", "relay"}
+	if c < 0 || int(c) >= len(names) {
+		return "unknown"
+	}
+	return names[c]
+}
+
+// Channels accepted by each class of commands
+var channelCapabilities = map[CommandClass][]int{
+	ReadingCommands:     {1, 2, 3, 4, 5, 6},
+	ControlCommands:     {1, 3, 5},
+	CombinationCommands: {1, 2},
+	RelayCommands:       {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+}
+
+/*
+Returns the channels, or relays, accepted by a class of
+commands, in ascending order
+*/
+func ValidChannels(class CommandClass) []int {
+	return slices.Clone(channelCapabilities[class])
+}
+
+/*
+Iterates over the channels, or relays, accepted by a class of
+commands, e.g.
+
+	for channel := range protocol.Channels(protocol.ControlCommands) {
+		config, err := device.GetControlConfig(channel)
+	}
+*/
+func Channels(class CommandClass) iter.Seq[int] {
+	return slices.Values(channelCapabilities[class])
+}
@@ -0,0 +1,106 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+Routine polling a device in background, such as a watchdog or
+an interlock, stopped when the device is closed
+*/
+type routine interface {
+	Stop()
+}
+
+/*
+Registers a background routine of the device
+*/
+func (m *MKS937B) attach(r routine) {
+	m.routinesMutex.Lock()
+	defer m.routinesMutex.Unlock()
+
+	if m.routines == nil {
+		m.routines = make(map[routine]struct{})
+	}
+	m.routines[r] = struct{}{}
+}
+
+/*
+Unregisters a background routine of the device
+*/
+func (m *MKS937B) detach(r routine) {
+	m.routinesMutex.Lock()
+	defer m.routinesMutex.Unlock()
+
+	delete(m.routines, r)
+}
+
+/*
+Shuts the device down without leaving half written frames on
+the bus. New commands are rejected with ErrClosed, the
+watchdogs, interlocks, keepalives and degas schedulers of the
+device are stopped, the in-flight transaction is allowed to
+finish and the device is disconnected. Event subscriptions are closed last, after the
+disconnected event.
+
+When the context is done first, the communication is closed
+under the in-flight transaction, which then fails, and the
+context error is returned. Connect reopens the device
+*/
+func (m *MKS937B) Close(ctx context.Context) error {
+	m.closed.Store(true)
+
+	m.routinesMutex.Lock()
+	routines := make([]routine, 0, len(m.routines))
+	for r := range m.routines {
+		routines = append(routines, r)
+	}
+	m.routinesMutex.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		var group sync.WaitGroup
+		for _, r := range routines {
+			group.Go(r.Stop)
+		}
+		group.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		m.mutex.Lock()
+		close(acquired)
+	}()
+	var err error
+	select {
+	case <-acquired:
+	case <-ctx.Done():
+		err = ctx.Err()
+		m.Communication.Disconnect()
+		<-acquired
+	}
+	defer m.events.close()
+	defer m.mutex.Unlock()
+
+	if m.Communication.IsConnected() {
+		if disconnectErr := m.disconnect(); err == nil {
+			err = disconnectErr
+		}
+	} else if err != nil {
+		m.stats.disconnected()
+		m.publish(Event{Kind: EventDisconnected})
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+Exponent notation of the numbers written to the controller,
+e.g. 1.00E-05 or 1.0E-5
+*/
+type NumberFormat struct {
+	Digits         int // Digits of the mantissa after the decimal point
+	ExponentDigits int // Minimum digits of the exponent
+}
+
+// Notation of the manual, e.g. 1.00E-05, accepted by most firmware
+var ManualNumberFormat = NumberFormat{Digits: 2, ExponentDigits: 2}
+
+// Short notation, e.g. 1.0E-5
+var CompactNumberFormat = NumberFormat{Digits: 1, ExponentDigits: 1}
+
+/*
+Formats a number in the notation
+*/
+func (f NumberFormat) Format(value float64) string {
+	mantissa, exponent := value, 0
+	if value != 0 && !math.IsInf(value, 0) && !math.IsNaN(value) {
+		// Split from the formatted value so rounding up (9.99 to 10.0) moves the exponent
+		formatted := strconv.FormatFloat(value, 'E', f.Digits, 64)
+		idx := strings.IndexByte(formatted, 'E')
+		exponent, _ = strconv.Atoi(formatted[idx+1:])
+		mantissa, _ = strconv.ParseFloat(formatted[:idx], 64)
+	}
+	sign := "+"
+	if exponent < 0 {
+		sign, exponent = "-", -exponent
+	}
+	return fmt.Sprintf("%.*fE%s%0*d", f.Digits, mantissa, sign, f.ExponentDigits, exponent)
+}
+
+// Uses another notation for the numbers written to the controller
+// than ManualNumberFormat, e.g. for a firmware revision that NAKs it
+func WithNumberFormat(format NumberFormat) Option {
+	return func(m *MKS937B) {
+		m.numberFormat = &format
+	}
+}
+
+/*
+Formats a number in the notation of the device
+*/
+func (m *MKS937B) formatNumber(value float64) string {
+	m.mutex.Lock()
+	format := m.numberFormat
+	m.mutex.Unlock()
+
+	if format == nil {
+		return ManualNumberFormat.Format(value)
+	}
+	return format.Format(value)
+}
+
+/*
+Sets a number in the notation of the device
+*/
+func (m *MKS937B) setNumber(command string, value float64) error {
+	return m.Set(command, m.formatNumber(value))
+}
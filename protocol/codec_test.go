@@ -0,0 +1,42 @@
+package protocol_test
+
+import (
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestNumberFormat(t *testing.T) {
+	cases := []struct {
+		format protocol.NumberFormat
+		value  float64
+		want   string
+	}{
+		{protocol.ManualNumberFormat, 1e-5, "1.00E-05"},
+		{protocol.ManualNumberFormat, 5e3, "5.00E+03"},
+		{protocol.ManualNumberFormat, 0, "0.00E+00"},
+		{protocol.ManualNumberFormat, 9.999e-3, "1.00E-02"},
+		{protocol.CompactNumberFormat, 1e-5, "1.0E-5"},
+		{protocol.CompactNumberFormat, 2.5e-11, "2.5E-11"},
+	}
+	for _, c := range cases {
+		if got := c.format.Format(c.value); got != c.want {
+			t.Errorf("Format(%g) = %s, want %s", c.value, got, c.want)
+		}
+	}
+}
+
+func TestWithNumberFormat(t *testing.T) {
+	device := replayDevice(t,
+		"@001ATM2!7.60E+02;FF", "@001ACK7.60E+02;FF",
+		"@001ATM2!7.5E+2;FF", "@001ACK7.5E+2;FF",
+	)
+	// The notation of the manual is used without reading the firmware version
+	if err := device.CalibrateAtmosphere(2, 760); err != nil {
+		t.Fatal(err)
+	}
+	device.Apply(protocol.WithNumberFormat(protocol.CompactNumberFormat))
+	if err := device.CalibrateAtmosphere(2, 750); err != nil {
+		t.Fatal(err)
+	}
+}
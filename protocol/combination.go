@@ -0,0 +1,100 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Combination struct {
+	High   string `json:"high" yaml:"high"`     // Channel of the high pressure range sensor
+	Middle string `json:"middle" yaml:"middle"` // Channel of the middle pressure range sensor
+	Low    string `json:"low" yaml:"low"`       // Channel of the low pressure range sensor
+}
+
+/*
+Gets the sensors assigned to a combination channel (1 or 2).
+
+The crossover between the sensors is fixed by the firmware:
+PR/CP and HC/CC readings are smoothed between 1e-4 and 1e-3
+Torr, CP hands over to a CM above 5% of its full scale, and
+CMs hand over to the next range above 95% of full scale
+*/
+func (m *MKS937B) GetCombination(channel int) (Combination, error) {
+	var combination Combination
+
+	if err := m.checkChannel(CombinationCommands, channel); err != nil {
+		return combination, err
+	}
+	command := fmt.Sprintf("SPC%d", channel)
+	response, err := m.Query(command)
+	if err != nil {
+		return combination, err
+	}
+	sensors := strings.Split(response, ",")
+	if len(sensors) != 3 {
+		return combination, NewErrUnexpectedReply(command, response)
+	}
+	combination.High = strings.TrimSpace(sensors[0])
+	combination.Middle = strings.TrimSpace(sensors[1])
+	combination.Low = strings.TrimSpace(sensors[2])
+	return combination, nil
+}
+
+/*
+Assigns the sensors of a combination channel (1 or 2).
+
+Valid values are A1, A2, B1, B2, C1, C2 or NA when no sensor
+is assigned. HC/CC can only be the low range sensor, PR/CP
+the middle range sensor, and CM the high range sensor
+*/
+func (m *MKS937B) SetCombination(channel int, combination Combination) error {
+	valid := []string{"A1", "A2", "B1", "B2", "C1", "C2", "NA"}
+
+	if err := m.checkChannel(CombinationCommands, channel); err != nil {
+		return err
+	}
+	sensors := []string{combination.High, combination.Middle, combination.Low}
+	for _, sensor := range sensors {
+		if !slices.Contains(valid, sensor) {
+			return NewErrInvalidCombination(sensor)
+		}
+	}
+	command := fmt.Sprintf("SPC%d", channel)
+	return m.Set(command, strings.Join(sensors, ","))
+}
+
+/*
+Returns true if the combination channel (1 or 2) is enabled
+*/
+func (m *MKS937B) GetCombinationStatus(channel int) (bool, error) {
+	if err := m.checkChannel(CombinationCommands, channel); err != nil {
+		return false, err
+	}
+	response, err := m.Query(fmt.Sprintf("EPC%d", channel))
+	if err != nil {
+		return false, err
+	}
+	return response == "Enable", nil
+}
+
+/*
+Enables or disables the combination channel (1 or 2). When
+disabled, its analog output is 10 V
+*/
+func (m *MKS937B) SetCombinationStatus(channel int, status bool) error {
+	if err := m.checkChannel(CombinationCommands, channel); err != nil {
+		return err
+	}
+	command := fmt.Sprintf("EPC%d", channel)
+	if status {
+		return m.Set(command, "Enable")
+	}
+	return m.Set(command, "Disable")
+}
@@ -0,0 +1,111 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version of the DeviceConfig, RelayConfig and ControlConfig JSON
+// documents. It is increased whenever a field is renamed or
+// removed, while new fields keep the version.
+const ConfigVersion = 1
+
+/*
+Settings provisioned on a controller. Empty fields are left
+unchanged. Configurations without version, e.g. written by
+hand, are taken as the current version
+*/
+type DeviceConfig struct {
+	Version int            `json:"version,omitempty" yaml:"version,omitempty"` // ConfigVersion
+	Unit    string         `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Labels  map[int]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Relays  []RelayConfig  `json:"relays,omitempty" yaml:"relays,omitempty"`
+}
+
+/*
+Applies a configuration to the controller. The unit is written
+first, since relay set points are expressed in it, and each
+relay is written in the order direction, set point, hysteresis
+and enable. It stops at the first error
+*/
+func (m *MKS937B) ApplyConfig(config DeviceConfig) error {
+	if err := checkConfigVersion(config.Version); err != nil {
+		return err
+	}
+	if config.Unit != "" {
+		if err := m.SetPressureUnit(config.Unit); err != nil {
+			return err
+		}
+	}
+	for channel, label := range config.Labels {
+		if err := m.SetChannelLabel(channel, label); err != nil {
+			return err
+		}
+	}
+	for _, relay := range config.Relays {
+		steps := []func() error{
+			func() error { return m.SetRelayDirection(relay.Relay, relay.Direction) },
+			func() error { return m.SetRelaySetPoint(relay.Relay, relay.SetPoint) },
+			func() error { return m.SetRelayHysteresis(relay.Relay, relay.Hysteresis) },
+			func() error { return m.SetRelayEnable(relay.Relay, relay.Enable) },
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return fmt.Errorf("relay %d: %w", relay.Relay, err)
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Decodes a device configuration JSON document, rejecting
+documents written by a newer, incompatible version
+*/
+func ParseDeviceConfig(data []byte) (DeviceConfig, error) {
+	return parseConfig(data, func(config DeviceConfig) int { return config.Version })
+}
+
+/*
+Decodes a relay configuration JSON document, rejecting
+documents written by a newer, incompatible version
+*/
+func ParseRelayConfig(data []byte) (RelayConfig, error) {
+	return parseConfig(data, func(config RelayConfig) int { return config.Version })
+}
+
+/*
+Decodes a control configuration JSON document, rejecting
+documents written by a newer, incompatible version
+*/
+func ParseControlConfig(data []byte) (ControlConfig, error) {
+	return parseConfig(data, func(config ControlConfig) int { return config.Version })
+}
+
+/*
+Decodes a configuration JSON document and checks its version
+*/
+func parseConfig[T any](data []byte, version func(config T) int) (T, error) {
+	var config T
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, err
+	}
+	return config, checkConfigVersion(version(config))
+}
+
+/*
+Returns an error unless a configuration version is supported.
+Version 0 stands for an unversioned configuration
+*/
+func checkConfigVersion(version int) error {
+	if version < 0 || ConfigVersion < version {
+		return NewErrUnsupportedVersion(ConfigVersion, version)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	relay := protocol.RelayConfig{
+		Version: protocol.ConfigVersion, Relay: 3, SetPoint: 1e-5, Hysteresis: 2e-5, Direction: "BELOW", Enable: "ENABLE",
+	}
+	device := protocol.DeviceConfig{
+		Version: protocol.ConfigVersion,
+		Unit:    "TORR",
+		Labels:  map[int]string{1: "Chamber", 2: "Foreline"},
+		Relays:  []protocol.RelayConfig{relay},
+	}
+	control := protocol.ControlConfig{
+		Version: protocol.ConfigVersion, Channel: 1, Module: "HC", SetPoint: 2e-3, Hysteresis: 4e-3,
+		ControlChannel: "A1", ControlMode: "AUTO", Power: true, Filament: 2, EmissionCurrent: "AUTO100", DegasTime: 30,
+	}
+
+	data, err := json.Marshal(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedDevice, err := protocol.ParseDeviceConfig(data)
+	if err != nil || !reflect.DeepEqual(parsedDevice, device) {
+		t.Errorf("expected %+v, got %+v, %v", device, parsedDevice, err)
+	}
+
+	data, err = json.Marshal(relay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedRelay, err := protocol.ParseRelayConfig(data)
+	if err != nil || parsedRelay != relay {
+		t.Errorf("expected %+v, got %+v, %v", relay, parsedRelay, err)
+	}
+
+	data, err = json.Marshal(control)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedControl, err := protocol.ParseControlConfig(data)
+	if err != nil || parsedControl != control {
+		t.Errorf("expected %+v, got %+v, %v", control, parsedControl, err)
+	}
+}
+
+func TestConfigVersion(t *testing.T) {
+	if _, err := protocol.ParseDeviceConfig([]byte(`{"unit": "TORR"}`)); err != nil {
+		t.Errorf("expected an unversioned configuration to be accepted, got %v", err)
+	}
+	var unsupported *protocol.ErrUnsupportedVersion
+	if _, err := protocol.ParseRelayConfig([]byte(`{"version": 2, "relay": 1}`)); !errors.As(err, &unsupported) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+	device := &protocol.MKS937B{}
+	if err := device.ApplyConfig(protocol.DeviceConfig{Version: 2}); !errors.As(err, &unsupported) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
@@ -1,15 +1,18 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 24rd, 2025
-Last update: September 24rd, 2025
+Last update: October 17th, 2026
 */
 
 package protocol
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"strconv"
+	"strings"
 )
 
 var SensorStatus = map[string]string{
@@ -31,79 +34,147 @@ Gets protection set point value for sensor on a
 target channel that must be 1, 3 or 5
 */
 func (m *MKS937B) GetProtectionTarget(channel int) (float64, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("PRO%d", channel)
 	response, err := m.Query(command)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseFloat(response, 64)
+	if response == "DISABLE" {
+		return 0, nil
+	}
+	return m.parseSetPoint(response)
 }
 
 /*
-Sets a protection set point value for sensor on a
-target channel that must be 1, 3 or 5.
+Sets a protection set point value in Torr for sensor on a
+target channel that must be 1, 3 or 5. The value is converted
+to the unit configured on the device.
 
 The valid PRO range is 1e-5 to 1e-2 Torr. Use 0 for disable
 and the default value is 5e-3 Torr
 */
 func (m *MKS937B) SetProtectionTarget(channel int, target float64) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	return m.SetProtectionPressure(channel, Pressure{Value: target, Unit: "Torr"})
+}
+
+/*
+Sets a protection set point in any unit for sensor on a
+target channel that must be 1, 3 or 5, see SetProtectionTarget
+*/
+func (m *MKS937B) SetProtectionPressure(channel int, target Pressure) error {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
+	}
+	if target.Value == 0 {
+		return m.DisableProtection(channel)
+	}
+	var invalid *ErrInvalidPressureRange
+	if err := checkPressureRange(target, 1e-5, 1e-2); errors.As(err, &invalid) {
+		return NewErrInvalidPRO(target.Value, target.Unit)
+	} else if err != nil {
+		return err
+	}
+	value, err := m.inDeviceUnit(target)
+	if err != nil {
+		return err
+	}
+	command := fmt.Sprintf("PRO%d", channel)
+	return m.setNumber(command, value.Value)
+}
+
+/*
+Returns true if the protection set point is enabled for the
+sensor on a target channel that must be 1, 3 or 5.
+
+For Hot Cathode sensors the protection set point is the
+overpressure trip: the filament is turned OFF once the
+pressure exceeds it
+*/
+func (m *MKS937B) GetProtectionStatus(channel int) (bool, error) {
+	target, err := m.GetProtectionTarget(channel)
+	if err != nil {
+		return false, err
 	}
-	if target != 0 && target < 1e-5 && 1e-2 < target {
-		return NewErrInvalidPRO(target)
+	return target != 0, nil
+}
+
+/*
+Disables the protection set point for sensor on a target
+channel that must be 1, 3 or 5. Use SetProtectionTarget
+to enable it again
+*/
+func (m *MKS937B) DisableProtection(channel int) error {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	command := fmt.Sprintf("PRO%d", channel)
-	return m.Set(command, fmt.Sprintf("%.2E", target))
+	err := m.setNumber(command, 0)
+
+	// HC modules reply DISABLE instead of echoing the value
+	var unexpected *ErrUnexpectedParameter
+	if errors.As(err, &unexpected) {
+		if value, parseErr := strconv.ParseFloat(unexpected.Got, 64); unexpected.Got == "DISABLE" || (parseErr == nil && value == 0) {
+			return nil
+		}
+	}
+	return err
 }
 
 /*
 Gets the set point value for a sensor on a target channel
 */
 func (m *MKS937B) GetTarget(channel int) (float64, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("CSP%d", channel)
 	response, err := m.Query(command)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseFloat(response, 64)
+	return m.parseSetPoint(response)
 }
 
 /*
-Sets a target for a sensor on a desired channel.
+Sets a target in Torr for a sensor on a desired channel. The
+value is converted to the unit configured on the device.
 
 Valid CSP range is 5e-4 to 1e-2 Torr for Pirani, 
 2e-3 to 1e-2 Torr for Convention Pirani, and 0.2% of 
 full scale to 0.02 Torr for Capacitance Manometer
 */
 func (m *MKS937B) SetTarget(channel int, target float64) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	return m.SetTargetPressure(channel, Pressure{Value: target, Unit: "Torr"})
+}
+
+/*
+Sets a target in any unit for a sensor on a desired channel,
+see SetTarget
+*/
+func (m *MKS937B) SetTargetPressure(channel int, target Pressure) error {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
+	}
+	if err := checkPressureRange(target, 5e-4, 1e-2); err != nil {
+		return err
 	}
-	if target < 5e-4 && 1e-2 < target {
-		return NewErrInvalidRangeExp(5e-4, 1e-2, target)
+	value, err := m.inDeviceUnit(target)
+	if err != nil {
+		return err
 	}
 	command := fmt.Sprintf("CSP%d", channel)
-	return m.Set(command, fmt.Sprintf("%.2E", target))
+	return m.setNumber(command, value.Value)
 }
 
 /*
 Get upper control set point status
 */
 func (m *MKS937B) GetUpperControlStatus(channel int) (bool, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return false, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return false, err
 	}
 	command := fmt.Sprintf("XCS%d", channel)
 	response, err := m.Query(command)
@@ -118,9 +189,8 @@ Sets the upper control set point. If enabled the
 range is extended from 1e-2 Torr to 9.5e-1 Torr
 */
 func (m *MKS937B) SetUpperControlStatus(channel int, status bool) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	command := fmt.Sprintf("XCS%d", channel)
 	if status {
@@ -134,48 +204,65 @@ Gets control set point hysterises value for a
 target channel
 */
 func (m *MKS937B) GetHysterisesTarget(channel int) (float64, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("CHP%d", channel)
 	response, err := m.Query(command)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseFloat(response, 64)
+	return m.parseSetPoint(response)
 }
 
 /*
-Sets a target hysterises value for a target channel
+Sets a target hysterises value in Torr for a target channel.
+The value is converted to the unit configured on the device.
 
 Valid CHP range in 1.2*CSP to 1.1e-2 Torr for convention
 pirani and pirani, and 1.2*CSP to 0.03 Torr for capacitance
 manometer. Default value is 1.5*CSP
 */
 func (m *MKS937B) SetHysterisesTarget(channel int, target float64) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	return m.SetHysterisesPressure(channel, Pressure{Value: target, Unit: "Torr"})
+}
+
+/*
+Sets a target hysterises value in any unit for a target
+channel, see SetHysterisesTarget
+*/
+func (m *MKS937B) SetHysterisesPressure(channel int, target Pressure) error {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
+	}
+	value, err := m.inDeviceUnit(target)
+	if err != nil {
+		return err
 	}
 	CSP, err := m.GetTarget(channel)
 	if err != nil {
 		return err
 	}
-	if target < 1.2*CSP || 0.03 < target {
-		return NewErrInvalidRangeExp(1.2*CSP, 0.03, target)
+	unit, err := m.readingUnit()
+	if err != nil {
+		return err
+	}
+	// Limits in the unit of the target, which inDeviceUnit validated
+	low, _ := Pressure{Value: 1.2 * CSP, Unit: unit}.In(target.Unit)
+	high, _ := Pressure{Value: 0.03, Unit: "Torr"}.In(target.Unit)
+	if target.Value < low.Value || high.Value < target.Value {
+		return NewErrInvalidPressureRange(low.Value, high.Value, target.Value, target.Unit)
 	}
 	command := fmt.Sprintf("CHP%d", channel)
-	return m.Set(command, fmt.Sprintf("%.2E", target))
+	return m.setNumber(command, value.Value)
 }
 
 /*
 Gets the control channel for a sensor on a desired channel
 */
 func (m *MKS937B) GetControlChannelStatus(channel int) (string, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return "", NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return "", err
 	}
 	command := fmt.Sprintf("CSE%d", channel)
 	return m.Query(command)
@@ -188,11 +275,10 @@ a desired channel.
 Valid target options are A1, A2, B1, B2, C1, C2 or OFF
 */
 func (m *MKS937B) SetControlChannelStatus(channel int, target string) error {
-	validChannels := []int{1, 3, 5}
 	validTargets := []string{"A1", "B1", "A2", "B2", "C1", "C2", "OFF"}
 
-	if !slices.Contains(validChannels, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if !slices.Contains(validTargets, target) {
 		return NewErrInvalidCSE(target)
@@ -205,9 +291,8 @@ func (m *MKS937B) SetControlChannelStatus(channel int, target string) error {
 Gets the control mode for a desired channel
 */
 func (m *MKS937B) GetControlMode(channel int) (string, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return "", NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return "", err
 	}
 	command := fmt.Sprintf("CTL%d", channel)
 	return m.Query(command)
@@ -222,11 +307,10 @@ Valid mode are:
 	- OFF: disable control
 */
 func (m *MKS937B) SetControlMode(channel int, mode string) error {
-	validChannels := []int{1, 3, 5}
 	validMode := []string{"AUTO", "SAFE", "OFF"}
 
-	if !slices.Contains(validChannels, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if !slices.Contains(validMode, mode) {
 		return NewErrInvalidControlMode(mode)
@@ -240,9 +324,8 @@ func (m *MKS937B) SetControlMode(channel int, mode string) error {
 Gets active filament for Hot Cathode
 */
 func (m *MKS937B) GetActiveFilament(channel int) (int, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("AF%d", channel)
 	response, err := m.Query(command)
@@ -256,9 +339,8 @@ func (m *MKS937B) GetActiveFilament(channel int) (int, error) {
 Sets active filament for Hot Cathode
 */
 func (m *MKS937B) SetActiveFilament(channel int, filament int) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if filament < 1 && 2 < filament {
 		return NewErrInvalidFilament(filament)
@@ -268,13 +350,63 @@ func (m *MKS937B) SetActiveFilament(channel int, filament int) error {
 	return m.Set(command, fmt.Sprint(filament))
 }
 
+type FilamentHealth struct {
+	Active int    `json:"active" yaml:"active"` // Active filament (1 or 2)
+	Fault  bool   `json:"fault" yaml:"fault"`   // True if the active filament failed
+	Status string `json:"status" yaml:"status"` // Sensor status description
+}
+
+/*
+Gets the active filament and whether it is in fault for the
+Hot Cathode on a desired channel
+*/
+func (m *MKS937B) GetFilamentHealth(channel int) (FilamentHealth, error) {
+	var health FilamentHealth
+
+	active, err := m.GetActiveFilament(channel)
+	if err != nil {
+		return health, err
+	}
+	status, err := m.GetSensorStatus(channel)
+	if err != nil {
+		return health, err
+	}
+	health.Active = active
+	health.Status = status
+	health.Fault = status == SensorStatus["F"]
+	return health, nil
+}
+
+/*
+Switches a Hot Cathode to filament 2 and turns it back ON if
+filament 1 is in fault. Returns true if the switch was made.
+
+The controller has no automatic switchover, so this should be
+called periodically during long unattended runs
+*/
+func (m *MKS937B) FailoverFilament(channel int) (bool, error) {
+	health, err := m.GetFilamentHealth(channel)
+	if err != nil {
+		return false, err
+	}
+	if !health.Fault || health.Active != 1 {
+		return false, nil
+	}
+	if err := m.SetActiveFilament(channel, 2); err != nil {
+		return false, err
+	}
+	if err := m.SetPowerStatus(channel, true); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
 /*
 Gets the emission current
 */
 func (m *MKS937B) GetEmissionCurrent(channel int) (string, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return "", NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return "", err
 	}
 	command := fmt.Sprintf("EC%d", channel)
 	return m.Query(command)
@@ -286,11 +418,10 @@ Sets the emission current
 Valid value for emission are 20UA, 100UA, AUTO20 and AUTO100
 */
 func (m *MKS937B) SetEmissionCurrent(channel int, current string) error {
-	validChannels := []int{1, 3, 5}
 	validCurrent := []string{"20UA", "100UA", "AUTO20", "AUTO100"}
 
-	if !slices.Contains(validChannels, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if !slices.Contains(validCurrent, current) {
 		return NewErrInvalidControlMode(current)
@@ -305,9 +436,8 @@ Gets the gas correction factor for an HC sensor on
 a desired channel
 */
 func (m *MKS937B) GetHCGasCorrection(channel int) (float64, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("GC%d", channel)
 	response, err := m.Query(command)
@@ -321,12 +451,12 @@ func (m *MKS937B) GetHCGasCorrection(channel int) (float64, error) {
 Sets the gas correction factor for an HC sensor on
 a desired channel
 
-Valid range for factor is from 0.1 to 50.0
+Valid range for factor is from 0.1 to 50.0. The factor is a
+ratio, so it does not depend on the pressure unit
 */
 func (m *MKS937B) SetHCGasCorrection(channel int, factor float64) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if factor < 0.1 || 50.0 < factor {
 		return NewErrInvalidRangeExp(0.1, 50, factor)
@@ -340,9 +470,8 @@ Gets the gas correction factor for an CC sensor on
 a desired channel
 */
 func (m *MKS937B) GetCCGasCorrection(channel int) (float64, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("UC%d", channel)
 	response, err := m.Query(command)
@@ -359,9 +488,8 @@ a desired channel
 Valid range for factor is from 0.1 to 10.0
 */
 func (m *MKS937B) SetUCGasCorrection(channel int, factor float64) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if factor < 0.1 || 10.0 < factor {
 		return NewErrInvalidRangeExp(0.1, 10, factor)
@@ -370,14 +498,46 @@ func (m *MKS937B) SetUCGasCorrection(channel int, factor float64) error {
 	return m.Set(command, fmt.Sprintf("%.1f", factor))
 }
 
+/*
+Gets the start delay in seconds of a Cold Cathode on a desired
+channel, during which its relays and analog output stay inactive
+after the high voltage is turned ON
+*/
+func (m *MKS937B) GetCCStartDelay(channel int) (int, error) {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
+	}
+	command := fmt.Sprintf("TDC%d", channel)
+	response, err := m.Query(command)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(response)
+}
+
+/*
+Sets the start delay of a Cold Cathode on a desired channel
+
+Valid range for delay is from 3 to 300 seconds, default is 3
+*/
+func (m *MKS937B) SetCCStartDelay(channel int, delay int) error {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
+	}
+	if delay < 3 || 300 < delay {
+		return NewErrInvalidRangeExp(3, 300, float64(delay))
+	}
+	command := fmt.Sprintf("TDC%d", channel)
+	return m.Set(command, fmt.Sprintf("%03d", delay))
+}
+
 /*
 Gets the channel power status for PR, CP, HC or high
 voltage status for CC
 */
 func (m *MKS937B) GetPowerStatus(channel int) (bool, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return false, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return false, err
 	}
 	command := fmt.Sprintf("CP%d", channel)
 	response, err := m.Query(command)
@@ -392,9 +552,8 @@ Sets the channel power status for PR, CP, HC or high
 voltage status for CC
 */
 func (m *MKS937B) SetPowerStatus(channel int, status bool) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	command := fmt.Sprintf("CP%d", channel)
 	if status {
@@ -407,9 +566,8 @@ func (m *MKS937B) SetPowerStatus(channel int, status bool) error {
 Gets a gas sentivity for an Hot Cathode sensor on the desired channel
 */
 func (m *MKS937B) GetGasSensitivy(channel int) (float64, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("SEN%d", channel)
 	response, err := m.Query(command)
@@ -422,27 +580,60 @@ func (m *MKS937B) GetGasSensitivy(channel int) (float64, error) {
 /*
 Sets a gas sensitivity for an Hot Cathode sensor on the desired channel
 
-Valid range for sensivity is from 1.0 to 50.0
+Valid range for sensivity is from 1.0 to 50.0. The manual
+gives the sensitivity in 1/Torr, so the range is not converted
+to the pressure unit
 */
 func (m *MKS937B) SetGasSentivity(channel int, sensitivity float64) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if sensitivity < 1.0 || 50.0 < sensitivity {
-		return NewErrInvalidRangeExp(0.1, 50, sensitivity)
+		return NewErrInvalidRangeExp(1, 50, sensitivity)
 	}
 	command := fmt.Sprintf("SEN%d", channel)
 	return m.Set(command, fmt.Sprintf("%.1f", sensitivity))
 }
 
+/*
+Calibrates the gas sensitivity of an Hot Cathode sensor on the
+desired channel against a trusted reference pressure, in the
+unit of the readings, measured on the same volume.
+
+The indicated pressure is inversely proportional to the
+sensitivity, so the new value is SEN * reading / reference,
+rounded to the tenth written to the device. Returns the
+sensitivity written, and records the calibration
+*/
+func (m *MKS937B) CalibrateSensitivity(channel int, referencePressure float64) (float64, error) {
+	if referencePressure <= 0 {
+		return 0, NewErrInvalidRangeExp(0, math.Inf(1), referencePressure)
+	}
+	sensitivity, err := m.GetGasSensitivy(channel)
+	if err != nil {
+		return 0, err
+	}
+	reading, err := m.GetPressure(channel)
+	if err != nil {
+		return 0, err
+	}
+	if reading.Status != "OK" {
+		return 0, fmt.Errorf("channel %d reading is unavailable: %s", channel, reading.Status)
+	}
+
+	corrected := math.Round(sensitivity*reading.Value/referencePressure*10) / 10
+	if err := m.SetGasSentivity(channel, corrected); err != nil {
+		return 0, err
+	}
+	return corrected, m.RecordCalibration(channel, CalibrationSensitivity)
+}
+
 /*
 Gets Hot Cathode degas status
 */
 func (m *MKS937B) GetDegasStatus(channel int) (bool, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return false, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return false, err
 	}
 	command := fmt.Sprintf("DG%d", channel)
 	response, err := m.Query(command)
@@ -454,26 +645,70 @@ func (m *MKS937B) GetDegasStatus(channel int) (bool, error) {
 
 /*
 Sets Hot Cathode degas status
+
+When DegasGuard is enabled, degas is only started if the
+filament is ON and the pressure is below 1e-5 Torr
 */
 func (m *MKS937B) SetDegasStatus(channel int, status bool) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	command := fmt.Sprintf("DG%d", channel)
 	if status {
+		if m.DegasGuard {
+			if err := m.checkDegasSafety(channel); err != nil {
+				return err
+			}
+		}
 		return m.Set(command, "ON")
 	}
 	return m.Set(command, "OFF")
 }
 
 /*
-Get Hot Cathode degas time
+Verifies that the filament is ON and the pressure is below
+the degas limit of 1e-5 Torr converted to the unit of the
+readings
+*/
+func (m *MKS937B) checkDegasSafety(channel int) error {
+	power, err := m.GetPowerStatus(channel)
+	if err != nil {
+		return err
+	}
+	if !power {
+		return NewErrDegasUnsafe(channel, "filament is OFF")
+	}
+
+	unit, err := m.readingUnit()
+	if err != nil {
+		return err
+	}
+	factor, ok := torrConversion[unit]
+	if !ok {
+		return NewErrInvalidUnit(unit)
+	}
+	pressure, err := m.GetPressure(channel)
+	if err != nil {
+		return err
+	}
+	if pressure.Status != "OK" {
+		return NewErrDegasUnsafe(channel, "pressure is unavailable: "+pressure.Status)
+	}
+	if limit := 1e-5 * factor; pressure.Value >= limit {
+		return NewErrDegasUnsafe(
+			channel,
+			fmt.Sprintf("pressure %.2E %s is above %.2E %s", pressure.Value, unit, limit, unit),
+		)
+	}
+	return nil
+}
+
+/*
+Get Hot Cathode degas time, in minutes
 */
 func (m *MKS937B) GetDegasTime(channel int) (int, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return 0, NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return 0, err
 	}
 	command := fmt.Sprintf("DGT%d", channel)
 	response, err := m.Query(command)
@@ -484,14 +719,13 @@ func (m *MKS937B) GetDegasTime(channel int) (int, error) {
 }
 
 /*
-Set Hot Cathode degas time
+Set Hot Cathode degas time, in minutes (5 to 240)
 */
 func (m *MKS937B) SetDegasTime(channel int, time int) error {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
-	if time < 5 && 240< time {
+	if time < 5 || 240 < time {
 		return NewErrInvalidRangeExp(5, 240, float64(time))
 	}
 	
@@ -503,9 +737,8 @@ func (m *MKS937B) SetDegasTime(channel int, time int) error {
 Gets the gas type for HC/CC on a desired channel
 */
 func (m *MKS937B) GetGasType(channel int) (string, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return "", NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return "", err
 	}
 	command := fmt.Sprintf("GT%d", channel)
 	return m.Query(command)
@@ -519,11 +752,10 @@ When Custom is selected, one can select GC value other than N2,
 Ar or He.
 */
 func (m *MKS937B) SetGasType(channel int, gas string) error {
-	validChannels := []int{1, 3, 5}
 	validGas := []string{"Nitrogen", "Argon", "Helium", "Custom"}
 
-	if !slices.Contains(validChannels, channel) {
-		return NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return err
 	}
 	if !slices.Contains(validGas, gas) {
 		return NewErrInvalidGas(gas)
@@ -537,9 +769,8 @@ func (m *MKS937B) SetGasType(channel int, gas string) error {
 Gets Hot Cathode sensor status query
 */
 func (m *MKS937B) GetSensorStatus(channel int) (string, error) {
-	valid := []int{1, 3, 5}
-	if !slices.Contains(valid, channel) {
-		return "", NewErrInvalidChannelControl(channel)
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return "", err
 	}
 	command := fmt.Sprintf("T%d", channel)
 	response, err := m.Query(command)
@@ -547,4 +778,134 @@ func (m *MKS937B) GetSensorStatus(channel int) (string, error) {
 		return "", err
 	}
 	return SensorStatus[response], nil
-}
\ No newline at end of file
+}
+
+/*
+Gets the channels (1, 3 or 5) of the slots equipped with an
+ionization gauge module and the module type (HC or CC)
+*/
+func (m *MKS937B) getIonGaugeChannels() (map[int]string, error) {
+	modules, err := m.GetModuleTypes()
+	if err != nil {
+		return nil, err
+	}
+	return ionGaugeChannels(modules), nil
+}
+
+/*
+Returns the channels (1, 3 or 5) of the slots equipped with an
+ionization gauge module in a module layout, see GetModuleTypes
+*/
+func ionGaugeChannels(modules []string) map[int]string {
+	channels := make(map[int]string)
+	for slot := 0; slot < 3 && slot < len(modules); slot++ {
+		if modules[slot] == "HC" || modules[slot] == "CC" {
+			channels[2*slot+1] = modules[slot]
+		}
+	}
+	return channels
+}
+
+/*
+Brings the controller to a safe state by stopping degas and
+turning OFF all Hot Cathode and Cold Cathode gauges. If
+disableControl is true, the control mode is set to OFF before
+so the controlling sensor cannot turn the gauges back ON.
+
+The commands go ahead of the ones waiting for the device, after
+the one in progress. If the installed modules cannot be read,
+e.g. on a degraded bus, every control channel of the variant is
+turned OFF. All gauges are handled even if one of them fails,
+and the errors are returned joined
+*/
+func (m *MKS937B) SafeState(disableControl bool) error {
+	if err := m.authorizeSafeState(disableControl); err != nil {
+		return err
+	}
+	return m.safeState(disableControl, nil)
+}
+
+/*
+Returns an error unless the policies of the device allow the
+settings of the safe state. The emergency stop is not checked
+since it never rejects them
+*/
+func (m *MKS937B) authorizeSafeState(disableControl bool) error {
+	for _, channel := range []int{1, 3, 5} {
+		if disableControl {
+			if err := m.checkPolicy(fmt.Sprintf("CTL%d", channel), "OFF"); err != nil {
+				return err
+			}
+		}
+		if err := m.checkPolicy(fmt.Sprintf("DG%d", channel), "OFF"); err != nil {
+			return err
+		}
+		if err := m.checkPolicy(fmt.Sprintf("CP%d", channel), "OFF"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Clears relays and brings the controller to a safe state without
+checking the policies of the device, see SafeState
+*/
+func (m *MKS937B) safeState(disableControl bool, relays []int) error {
+	unlock := m.lockUrgent()
+	if !m.Communication.IsConnected() {
+		unlock()
+		if err := m.connectOnDemand(); err != nil {
+			return err
+		}
+		unlock = m.lockUrgent()
+	}
+	defer func() { unlock() }()
+	variant := SixChannelVariant
+	if m.variant != nil {
+		variant = *m.variant
+	}
+
+	var errs []error
+	for _, relay := range relays {
+		errs = append(errs, m.set(fmt.Sprintf("EN%d", relay), "CLEAR", "CLEAR"))
+	}
+	channels := make(map[int]string)
+	response, err := m.query("MT")
+	if err == nil {
+		modules := strings.Split(response, ",")
+		for slot := 0; slot < 3 && slot < len(modules); slot++ {
+			if module := strings.TrimSpace(modules[slot]); module == "HC" || module == "CC" {
+				channels[2*slot+1] = module
+			}
+		}
+	} else {
+		errs = append(errs, err)
+		for _, channel := range variant.ValidChannels(ControlCommands) {
+			channels[channel] = ""
+		}
+	}
+
+	for _, channel := range []int{1, 3, 5} {
+		module, ok := channels[channel]
+		if !ok {
+			continue
+		}
+		if disableControl {
+			errs = append(errs, m.set(fmt.Sprintf("CTL%d", channel), "OFF", "OFF"))
+		}
+		switch module {
+		case "HC":
+			degas, err := m.query(fmt.Sprintf("DG%d", channel))
+			if err == nil && degas == "ON" {
+				err = m.set(fmt.Sprintf("DG%d", channel), "OFF", "OFF")
+			}
+			errs = append(errs, err)
+		case "":
+			// Unknown module, degas is stopped in case it is a Hot Cathode
+			errs = append(errs, m.set(fmt.Sprintf("DG%d", channel), "OFF", "OFF"))
+		}
+		errs = append(errs, m.set(fmt.Sprintf("CP%d", channel), "OFF", "OFF"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Fails unless every frame of a replayed device was used
+*/
+func expectReplayed(t *testing.T, device *protocol.MKS937B) {
+	t.Helper()
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("expected every frame to be replayed, %d left", remaining)
+	}
+}
+
+func TestCalibrateSensitivity(t *testing.T) {
+	// 10.0 x 2.00E-09 / 1.5E-09 = 13.33, written as 13.3
+	device := replayDevice(t,
+		"@001SEN1?;FF", "@001ACK10.0;FF",
+		"@001PR1?;FF", "@001ACK2.00E-09;FF",
+		"@001SEN1!13.3;FF", "@001ACK13.3;FF",
+	)
+	sensitivity, err := device.CalibrateSensitivity(1, 1.5e-9)
+	if err != nil || sensitivity != 13.3 {
+		t.Fatalf("CalibrateSensitivity() = %v, %v, want 13.3", sensitivity, err)
+	}
+	if record, ok := device.Calibrations()[1]; !ok || record.Method != protocol.CalibrationSensitivity {
+		t.Errorf("expected a sensitivity calibration record, got %+v", record)
+	}
+	expectReplayed(t, device)
+}
+
+func TestCalibrateSensitivityRejected(t *testing.T) {
+	device := replayDevice(t,
+		"@001SEN1?;FF", "@001ACK10.0;FF",
+		"@001PR1?;FF", "@001ACK1.00E-07;FF",
+		"@001SEN1?;FF", "@001ACK10.0;FF",
+		"@001PR1?;FF", "@001ACKMISCONN;FF",
+	)
+	var invalidRange *protocol.ErrInvalidRangeExp
+	if _, err := device.CalibrateSensitivity(1, 0); !errors.As(err, &invalidRange) {
+		t.Errorf("CalibrateSensitivity(1, 0) = %v, want ErrInvalidRangeExp", err)
+	}
+	// 10.0 x 1.00E-07 / 1.00E-09 = 1000 is outside 1 to 50 and not written
+	if _, err := device.CalibrateSensitivity(1, 1e-9); !errors.As(err, &invalidRange) {
+		t.Errorf("CalibrateSensitivity() = %v, want ErrInvalidRangeExp", err)
+	}
+	if _, err := device.CalibrateSensitivity(1, 1e-9); err == nil {
+		t.Error("expected an error for a misconnected sensor")
+	}
+	if _, ok := device.Calibrations()[1]; ok {
+		t.Error("expected no calibration record")
+	}
+	expectReplayed(t, device)
+}
+
+func TestProtection(t *testing.T) {
+	device := replayDevice(t,
+		"@001PRO1?;FF", "@001ACKDISABLE;FF",
+		"@001PRO3?;FF", "@001ACK5.00E-03;FF",
+		"@001PRO1!0.00E+00;FF", "@001ACKDISABLE;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001PRO3!1.00E-03;FF", "@001ACK1.00E-03;FF",
+	)
+	if enabled, err := device.GetProtectionStatus(1); err != nil || enabled {
+		t.Errorf("GetProtectionStatus(1) = %v, %v, want disabled", enabled, err)
+	}
+	if enabled, err := device.GetProtectionStatus(3); err != nil || !enabled {
+		t.Errorf("GetProtectionStatus(3) = %v, %v, want enabled", enabled, err)
+	}
+	// HC modules reply DISABLE to a zero set point
+	if err := device.SetProtectionTarget(1, 0); err != nil {
+		t.Errorf("SetProtectionTarget(1, 0) = %v", err)
+	}
+	var invalidPRO *protocol.ErrInvalidPRO
+	if err := device.SetProtectionTarget(3, 5e-2); !errors.As(err, &invalidPRO) {
+		t.Errorf("SetProtectionTarget(3, 5e-2) = %v, want ErrInvalidPRO", err)
+	}
+	if err := device.SetProtectionTarget(3, 1e-3); err != nil {
+		t.Errorf("SetProtectionTarget(3, 1e-3) = %v", err)
+	}
+	expectReplayed(t, device)
+}
+
+func TestFailoverFilament(t *testing.T) {
+	device := replayDevice(t,
+		"@001AF1?;FF", "@001ACK1;FF",
+		"@001T1?;FF", "@001ACKG;FF",
+		"@001AF1?;FF", "@001ACK1;FF",
+		"@001T1?;FF", "@001ACKF;FF",
+		"@001AF1!2;FF", "@001ACK2;FF",
+		"@001CP1!ON;FF", "@001ACKON;FF",
+		"@001AF1?;FF", "@001ACK2;FF",
+		"@001T1?;FF", "@001ACKF;FF",
+	)
+	if switched, err := device.FailoverFilament(1); err != nil || switched {
+		t.Errorf("FailoverFilament() = %v, %v on a good filament", switched, err)
+	}
+	if switched, err := device.FailoverFilament(1); err != nil || !switched {
+		t.Errorf("FailoverFilament() = %v, %v on a failed filament 1", switched, err)
+	}
+	// Filament 2 has no spare
+	health, err := device.GetFilamentHealth(1)
+	if err != nil || health.Active != 2 || !health.Fault {
+		t.Errorf("GetFilamentHealth() = %+v, %v", health, err)
+	}
+	expectReplayed(t, device)
+}
+
+func TestCombination(t *testing.T) {
+	device := replayDevice(t,
+		"@001SPC1?;FF", "@001ACKNA, A2, A1;FF",
+		"@001SPC2!NA,B2,B1;FF", "@001ACKNA,B2,B1;FF",
+		"@001EPC1?;FF", "@001ACKEnable;FF",
+		"@001EPC2!Disable;FF", "@001ACKDisable;FF",
+	)
+	combination, err := device.GetCombination(1)
+	if err != nil || combination != (protocol.Combination{High: "NA", Middle: "A2", Low: "A1"}) {
+		t.Errorf("GetCombination(1) = %+v, %v", combination, err)
+	}
+	if err := device.SetCombination(2, protocol.Combination{High: "NA", Middle: "B2", Low: "B1"}); err != nil {
+		t.Errorf("SetCombination(2) = %v", err)
+	}
+	var invalid *protocol.ErrInvalidCombination
+	if err := device.SetCombination(2, protocol.Combination{High: "D1", Middle: "B2", Low: "B1"}); !errors.As(err, &invalid) {
+		t.Errorf("SetCombination(2) = %v, want ErrInvalidCombination", err)
+	}
+	if enabled, err := device.GetCombinationStatus(1); err != nil || !enabled {
+		t.Errorf("GetCombinationStatus(1) = %v, %v", enabled, err)
+	}
+	if err := device.SetCombinationStatus(2, false); err != nil {
+		t.Errorf("SetCombinationStatus(2) = %v", err)
+	}
+	expectReplayed(t, device)
+}
+
+func TestCCStartDelay(t *testing.T) {
+	device := replayDevice(t,
+		"@001TDC3?;FF", "@001ACK010;FF",
+		"@001TDC3!030;FF", "@001ACK030;FF",
+	)
+	if delay, err := device.GetCCStartDelay(3); err != nil || delay != 10 {
+		t.Errorf("GetCCStartDelay(3) = %d, %v, want 10", delay, err)
+	}
+	if err := device.SetCCStartDelay(3, 30); err != nil {
+		t.Errorf("SetCCStartDelay(3, 30) = %v", err)
+	}
+	var invalidRange *protocol.ErrInvalidRangeExp
+	if err := device.SetCCStartDelay(3, 2); !errors.As(err, &invalidRange) {
+		t.Errorf("SetCCStartDelay(3, 2) = %v, want ErrInvalidRangeExp", err)
+	}
+	expectReplayed(t, device)
+}
@@ -0,0 +1,263 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+/*
+Control settings of the gauge on channel 1, 3 or 5. Set points
+are in the unit of the readings, and the Hot Cathode fields are
+only filled for HC modules
+*/
+type ControlConfig struct {
+	Version        int     `json:"version,omitempty" yaml:"version,omitempty"` // ConfigVersion
+	Channel        int     `json:"channel" yaml:"channel"`
+	Module         string  `json:"module" yaml:"module"`                   // Module type of the slot, e.g. HC or CC
+	SetPoint       float64 `json:"set_point" yaml:"set_point"`             // CSP
+	Hysteresis     float64 `json:"hysteresis" yaml:"hysteresis"`           // CHP
+	UpperRange     bool    `json:"upper_range" yaml:"upper_range"`         // XCS, extended set point range
+	ControlChannel string  `json:"control_channel" yaml:"control_channel"` // CSE, e.g. A1 or OFF
+	ControlMode    string  `json:"control_mode" yaml:"control_mode"`       // CTL, AUTO, SAFE or OFF
+	Protection     float64 `json:"protection" yaml:"protection"`           // PRO, 0 when disabled
+	Power          bool    `json:"power" yaml:"power"`                     // CP
+
+	Filament        int    `json:"filament,omitempty" yaml:"filament,omitempty"`                 // AF
+	EmissionCurrent string `json:"emission_current,omitempty" yaml:"emission_current,omitempty"` // EC
+	Degas           bool   `json:"degas,omitempty" yaml:"degas,omitempty"`                       // DG
+	DegasTime       int    `json:"degas_time,omitempty" yaml:"degas_time,omitempty"`             // DGT, in minutes
+}
+
+/*
+Gets every control setting of the gauge on a channel that must
+be 1, 3 or 5. The settings are queried in a row, without other
+commands in between, so a UI can render the full state of a
+channel with one call
+*/
+func (m *MKS937B) GetControlConfig(channel int) (ControlConfig, error) {
+	config := ControlConfig{Version: ConfigVersion, Channel: channel}
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return config, err
+	}
+	modules, err := m.GetModuleTypes()
+	if err != nil {
+		return config, err
+	}
+	if slot := (channel - 1) / 2; slot < len(modules) {
+		config.Module = modules[slot]
+	}
+
+	mnemonics := []string{"CSP", "CHP", "XCS", "CSE", "CTL", "PRO", "CP"}
+	if config.Module == "HC" {
+		mnemonics = append(mnemonics, "AF", "EC", "DG", "DGT")
+	}
+	commands := make([]string, len(mnemonics))
+	for idx, mnemonic := range mnemonics {
+		commands[idx] = fmt.Sprintf("%s%d", mnemonic, channel)
+	}
+	values, err := m.queryBatch(commands...)
+	if err != nil {
+		return config, err
+	}
+
+	if config.SetPoint, err = m.parseSetPoint(values[0]); err != nil {
+		return config, err
+	}
+	if config.Hysteresis, err = m.parseSetPoint(values[1]); err != nil {
+		return config, err
+	}
+	config.UpperRange = values[2] == "ON"
+	config.ControlChannel = values[3]
+	config.ControlMode = values[4]
+	if values[5] != "DISABLE" {
+		if config.Protection, err = m.parseSetPoint(values[5]); err != nil {
+			return config, err
+		}
+	}
+	config.Power = values[6] == "ON"
+	if config.Module == "HC" {
+		if config.Filament, err = strconv.Atoi(values[7]); err != nil {
+			return config, err
+		}
+		config.EmissionCurrent = values[8]
+		config.Degas = values[9] == "ON"
+		if config.DegasTime, err = strconv.Atoi(values[10]); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+/*
+Result of writing a field of a control configuration
+*/
+type FieldResult struct {
+	Field string `json:"field" yaml:"field"` // Field of ControlConfig, e.g. SetPoint
+	Err   error  `json:"-" yaml:"-"`
+}
+
+/*
+Returns true if two set points are equal within the rounding of
+the three significant digits written to the device
+*/
+func sameSetPoint(x float64, y float64) bool {
+	return math.Abs(x-y) <= 0.005*max(math.Abs(x), math.Abs(y))
+}
+
+/*
+Writes the control settings of the gauge on a channel that must
+be 1, 3 or 5, and reads each one back to verify it. Set points
+are in the unit of the readings, as returned by
+GetControlConfig.
+
+Settings are written in dependency order: control channel (CSE),
+extended range (XCS), set point (CSP), hysteresis (CHP), which
+must be above the set point, control mode, protection, the Hot
+Cathode filament, emission current and degas time, and power
+last. Set points are skipped when the control channel is OFF,
+and Hot Cathode fields when they are empty. Degas is an action
+rather than a setting and is never started.
+
+Every field is attempted even if a previous one failed. One
+result per field written is returned, with the errors joined
+*/
+func (m *MKS937B) ApplyControlConfig(channel int, config ControlConfig) ([]FieldResult, error) {
+	if err := m.checkChannel(ControlCommands, channel); err != nil {
+		return nil, err
+	}
+	unit, err := m.readingUnit()
+	if err != nil {
+		return nil, err
+	}
+	pressure := func(value float64) Pressure {
+		return Pressure{Value: value, Unit: unit}
+	}
+	number := func(field string, want float64, get func(int) (float64, error)) func() error {
+		return func() error {
+			got, err := get(channel)
+			if err == nil && !sameSetPoint(want, got) {
+				err = NewErrReadbackMismatch(field, fmt.Sprint(want), fmt.Sprint(got))
+			}
+			return err
+		}
+	}
+	text := func(field string, want string, get func(int) (string, error)) func() error {
+		return func() error {
+			got, err := get(channel)
+			if err == nil && want != got {
+				err = NewErrReadbackMismatch(field, want, got)
+			}
+			return err
+		}
+	}
+	integer := func(field string, want int, get func(int) (int, error)) func() error {
+		return func() error {
+			got, err := get(channel)
+			if err == nil && want != got {
+				err = NewErrReadbackMismatch(field, fmt.Sprint(want), fmt.Sprint(got))
+			}
+			return err
+		}
+	}
+	flag := func(field string, want bool, get func(int) (bool, error)) func() error {
+		return func() error {
+			got, err := get(channel)
+			if err == nil && want != got {
+				err = NewErrReadbackMismatch(field, fmt.Sprint(want), fmt.Sprint(got))
+			}
+			return err
+		}
+	}
+
+	type step struct {
+		field  string
+		write  func() error
+		verify func() error
+	}
+	steps := []step{{
+		"ControlChannel",
+		func() error { return m.SetControlChannelStatus(channel, config.ControlChannel) },
+		text("ControlChannel", config.ControlChannel, m.GetControlChannelStatus),
+	}}
+	if config.ControlChannel != "OFF" {
+		steps = append(steps, step{
+			"UpperRange",
+			func() error { return m.SetUpperControlStatus(channel, config.UpperRange) },
+			flag("UpperRange", config.UpperRange, m.GetUpperControlStatus),
+		}, step{
+			"SetPoint",
+			func() error { return m.SetTargetPressure(channel, pressure(config.SetPoint)) },
+			number("SetPoint", config.SetPoint, m.GetTarget),
+		}, step{
+			"Hysteresis",
+			func() error { return m.SetHysterisesPressure(channel, pressure(config.Hysteresis)) },
+			number("Hysteresis", config.Hysteresis, m.GetHysterisesTarget),
+		})
+	}
+	steps = append(steps, step{
+		"ControlMode",
+		func() error { return m.SetControlMode(channel, config.ControlMode) },
+		text("ControlMode", config.ControlMode, m.GetControlMode),
+	})
+	if config.Protection == 0 {
+		steps = append(steps, step{
+			"Protection",
+			func() error { return m.DisableProtection(channel) },
+			number("Protection", 0, m.GetProtectionTarget),
+		})
+	} else {
+		steps = append(steps, step{
+			"Protection",
+			func() error { return m.SetProtectionPressure(channel, pressure(config.Protection)) },
+			number("Protection", config.Protection, m.GetProtectionTarget),
+		})
+	}
+	if config.Filament != 0 {
+		steps = append(steps, step{
+			"Filament",
+			func() error { return m.SetActiveFilament(channel, config.Filament) },
+			integer("Filament", config.Filament, m.GetActiveFilament),
+		})
+	}
+	if config.EmissionCurrent != "" {
+		steps = append(steps, step{
+			"EmissionCurrent",
+			func() error { return m.SetEmissionCurrent(channel, config.EmissionCurrent) },
+			text("EmissionCurrent", config.EmissionCurrent, m.GetEmissionCurrent),
+		})
+	}
+	if config.DegasTime != 0 {
+		steps = append(steps, step{
+			"DegasTime",
+			func() error { return m.SetDegasTime(channel, config.DegasTime) },
+			integer("DegasTime", config.DegasTime, m.GetDegasTime),
+		})
+	}
+	steps = append(steps, step{
+		"Power",
+		func() error { return m.SetPowerStatus(channel, config.Power) },
+		flag("Power", config.Power, m.GetPowerStatus),
+	})
+
+	results := make([]FieldResult, len(steps))
+	var errs []error
+	for idx, step := range steps {
+		err := step.write()
+		if err == nil {
+			err = step.verify()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.field, err))
+		}
+		results[idx] = FieldResult{Field: step.field, Err: err}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,136 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestGetControlConfig(t *testing.T) {
+	device := replayDevice(t,
+		"@001MT?;FF", "@001ACKHC,CC,NC;FF",
+		"@001CSP1?;FF", "@001ACK2.00E-03;FF",
+		"@001CHP1?;FF", "@001ACK4.00E-03;FF",
+		"@001XCS1?;FF", "@001ACKOFF;FF",
+		"@001CSE1?;FF", "@001ACKA1;FF",
+		"@001CTL1?;FF", "@001ACKAUTO;FF",
+		"@001PRO1?;FF", "@001ACKDISABLE;FF",
+		"@001CP1?;FF", "@001ACKON;FF",
+		"@001AF1?;FF", "@001ACK2;FF",
+		"@001EC1?;FF", "@001ACKAUTO100;FF",
+		"@001DG1?;FF", "@001ACKOFF;FF",
+		"@001DGT1?;FF", "@001ACK30;FF",
+	)
+	config, err := device.GetControlConfig(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := protocol.ControlConfig{
+		Version:         protocol.ConfigVersion,
+		Channel:         1,
+		Module:          "HC",
+		SetPoint:        2e-3,
+		Hysteresis:      4e-3,
+		ControlChannel:  "A1",
+		ControlMode:     "AUTO",
+		Power:           true,
+		Filament:        2,
+		EmissionCurrent: "AUTO100",
+		DegasTime:       30,
+	}
+	if config != expected {
+		t.Errorf("expected %+v, got %+v", expected, config)
+	}
+}
+
+func TestSensorInfo(t *testing.T) {
+	device := replayDevice(t,
+		"@001STB?;FF", "@001ACKCC,CP;FF",
+		"@001PR3?;FF", "@001ACK2.50E-07;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001CP3?;FF", "@001ACKON;FF",
+		"@001GT3?;FF", "@001ACKArgon;FF",
+		"@001T3?;FF", "@001ACKG;FF",
+		"@001UC3?;FF", "@001ACK1.3;FF",
+	)
+	info, err := device.SensorInfo(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != "CC" || !info.Power || info.Pressure.Value != 2.5e-7 || info.Unit != "Torr" {
+		t.Errorf("unexpected sensor info %+v", info)
+	}
+	if info.Status != "Good" || info.GasType != "Argon" || info.GasCorrection != 1.3 {
+		t.Errorf("unexpected sensor info %+v", info)
+	}
+}
+
+func TestApplyControlConfig(t *testing.T) {
+	device := replayDevice(t,
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001CSE1!OFF;FF", "@001ACKOFF;FF",
+		"@001CSE1?;FF", "@001ACKOFF;FF",
+		"@001CTL1!OFF;FF", "@001ACKOFF;FF",
+		"@001CTL1?;FF", "@001ACKSAFE;FF",
+		"@001PRO1!0.00E+00;FF", "@001ACKDISABLE;FF",
+		"@001PRO1?;FF", "@001ACKDISABLE;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+		"@001CP1?;FF", "@001ACKOFF;FF",
+	)
+	device.Apply(protocol.WithNumberFormat(protocol.ManualNumberFormat))
+
+	config := protocol.ControlConfig{ControlChannel: "OFF", ControlMode: "OFF"}
+	results, err := device.ApplyControlConfig(1, config)
+	var mismatch *protocol.ErrReadbackMismatch
+	if !errors.As(err, &mismatch) || mismatch.Field != "ControlMode" || mismatch.Got != "SAFE" {
+		t.Fatalf("expected a control mode readback mismatch, got %v", err)
+	}
+	fields := []string{"ControlChannel", "ControlMode", "Protection", "Power"}
+	if len(results) != len(fields) {
+		t.Fatalf("expected %d results, got %+v", len(fields), results)
+	}
+	for idx, result := range results {
+		if result.Field != fields[idx] || (result.Err != nil) != (result.Field == "ControlMode") {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	device := replayDevice(t,
+		"@001SN?;FF", "@001ACK1234;FF",
+		"@001FV1?;FF", "@001ACK1.20;FF",
+		"@001FV2?;FF", "@001ACK1.20;FF",
+		"@001FV3?;FF", "@001ACK1.20;FF",
+		"@001FV4?;FF", "@001ACK1.20;FF",
+		"@001FV5?;FF", "@001ACK1.20;FF",
+		"@001FV6?;FF", "@001ACK1.20;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001PRZ?;FF", "@001ACK4.20E-08 NOGAUGE NOGAUGE NOGAUGE NOGAUGE NOGAUGE;FF",
+		"@001STA?;FF", "@001ACKCC,NC;FF",
+		"@001STB?;FF", "@001ACKNC,NC;FF",
+		"@001STC?;FF", "@001ACKNC,NC;FF",
+		"@001PR1?;FF", "@001ACK4.20E-08;FF",
+	)
+	report := device.Validate()
+	if !report.Passed || len(report.Checks) != 5 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if check := report.Checks[4]; check.Name != "pressure A1" || check.Detail != "4.2e-08" {
+		t.Errorf("unexpected pressure check %+v", check)
+	}
+}
+
+func TestSetDegasTimeRange(t *testing.T) {
+	device := replayDevice(t, "@001DGT1!240;FF", "@001ACK240;FF")
+	var invalid *protocol.ErrInvalidRangeExp
+	for _, minutes := range []int{4, 241} {
+		if err := device.SetDegasTime(1, minutes); !errors.As(err, &invalid) {
+			t.Errorf("expected ErrInvalidRangeExp for %d minutes, got %v", minutes, err)
+		}
+	}
+	if err := device.SetDegasTime(1, 240); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+Software combination of a Pirani or Convection Pirani channel
+with a CC or HC channel, independent of the combination channels
+of the controller. Below the crossover band the ion gauge is
+used, above it the Pirani, and inside it the readings are
+blended on a log scale, weighted by the position of the Pirani
+reading in the band
+*/
+type Crossover struct {
+	High  int     // Channel of the Pirani sensor
+	Low   int     // Channel of the CC or HC sensor
+	Lower float64 // Lower bound of the crossover band, in the unit of the readings
+	Upper float64 // Upper bound of the crossover band, in the unit of the readings
+}
+
+/*
+Blends the readings of the high and low range sensors. A sensor
+without a valid reading, e.g. a Pirani below its range or an
+ion gauge turned OFF at high pressure, hands over to the other
+one. The result has no channel
+*/
+func (c Crossover) Blend(high PressureReading, low PressureReading) PressureReading {
+	highOK := high.Status == "OK" && high.Value > 0
+	lowOK := low.Status == "OK" && low.Value > 0
+
+	var result PressureReading
+	switch {
+	case !highOK && !lowOK && high.Status == stringResponse["LO<"]:
+		result = low
+	case !highOK && !lowOK:
+		result = high
+	case !highOK || (lowOK && high.Value <= c.Lower):
+		result = low
+	case !lowOK || c.Upper <= high.Value:
+		result = high
+	default:
+		weight := math.Log(high.Value/c.Lower) / math.Log(c.Upper/c.Lower)
+		result = PressureReading{
+			Value:  math.Exp(weight*math.Log(high.Value) + (1-weight)*math.Log(low.Value)),
+			Status: "OK",
+		}
+	}
+	result.Channel = 0
+	result.Label = ""
+	return result
+}
+
+/*
+Reads the pressure of a software combination, see Crossover
+*/
+func (m *MKS937B) GetCrossoverPressure(crossover Crossover) (PressureReading, error) {
+	if crossover.Lower <= 0 || crossover.Upper <= crossover.Lower {
+		return PressureReading{}, fmt.Errorf(
+			"%w: crossover band %.2E to %.2E", ErrInvalidParameter, crossover.Lower, crossover.Upper,
+		)
+	}
+	pressures, err := m.GetPressures()
+	if err != nil {
+		return PressureReading{}, err
+	}
+	// Checked once the pressures are read, which may detect the variant
+	for _, channel := range []int{crossover.High, crossover.Low} {
+		if err := m.checkChannel(ReadingCommands, channel); err != nil {
+			return PressureReading{}, err
+		}
+		if channel > len(pressures) {
+			return PressureReading{}, NewErrInvalidChannel(1, len(pressures), channel)
+		}
+	}
+	return crossover.Blend(pressures[crossover.High-1], pressures[crossover.Low-1]), nil
+}
@@ -0,0 +1,51 @@
+package protocol_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestCrossoverBlend(t *testing.T) {
+	crossover := protocol.Crossover{High: 3, Low: 1, Lower: 1e-4, Upper: 1e-2}
+	ok := func(value float64) protocol.PressureReading {
+		return protocol.PressureReading{Value: value, Status: "OK"}
+	}
+
+	if got := crossover.Blend(ok(5e-2), ok(4e-2)); got.Value != 5e-2 {
+		t.Errorf("expected the Pirani above the band, got %v", got.Value)
+	}
+	if got := crossover.Blend(ok(5e-5), ok(2e-5)); got.Value != 2e-5 {
+		t.Errorf("expected the ion gauge below the band, got %v", got.Value)
+	}
+
+	// Middle of the band on a log scale, geometric mean of the readings
+	got := crossover.Blend(ok(1e-3), ok(4e-4))
+	if want := math.Sqrt(1e-3 * 4e-4); math.Abs(got.Value-want) > 1e-12 {
+		t.Errorf("expected %v in the band, got %v", want, got.Value)
+	}
+
+	off := protocol.PressureReading{Status: "Cold cathode HV if OFF, or HC/PR/CP power if OFF"}
+	if got := crossover.Blend(ok(1e-3), off); got.Value != 1e-3 || got.Status != "OK" {
+		t.Errorf("expected the Pirani with the ion gauge OFF, got %+v", got)
+	}
+}
+
+func TestCrossoverPressureVariant(t *testing.T) {
+	device := replayDevice(t,
+		"@001PRZ?;FF", "@001ACK5.00E-05 NOGAUGE 2.00E-05;FF",
+		"@001PRZ?;FF", "@001ACK5.00E-05 NOGAUGE 2.00E-05;FF",
+	)
+	device.Apply(protocol.WithVariant(protocol.ThreeChannelVariant))
+	crossover := protocol.Crossover{High: 3, Low: 1, Lower: 1e-4, Upper: 1e-2}
+	if got, err := device.GetCrossoverPressure(crossover); err != nil || got.Value != 5e-5 {
+		t.Fatalf("GetCrossoverPressure() = %+v, %v", got, err)
+	}
+	var invalidChannel *protocol.ErrInvalidChannel
+	crossover.High = 5
+	if _, err := device.GetCrossoverPressure(crossover); !errors.As(err, &invalidChannel) {
+		t.Errorf("GetCrossoverPressure() = %v, want ErrInvalidChannel", err)
+	}
+}
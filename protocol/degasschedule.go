@@ -0,0 +1,284 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+/*
+Recurring period during which degas must not run, such as a
+pump-down whose pressure readings are critical
+*/
+type Blackout struct {
+	Schedule Schedule      // Start of the period
+	Duration time.Duration // Length of the period
+}
+
+/*
+Returns true if a degas from start lasting duration overlaps
+an occurrence of the period
+*/
+func (b Blackout) overlaps(start time.Time, duration time.Duration) bool {
+	occurrence := b.Schedule.Next(start.Add(-b.Duration))
+	return !occurrence.IsZero() && occurrence.Before(start.Add(duration))
+}
+
+/*
+Scheduled degas of a channel, skipped with a reason, failed
+with an error, or started
+*/
+type DegasRun struct {
+	Channel     int           `json:"channel" yaml:"channel"`
+	Scheduled   time.Time     `json:"scheduled" yaml:"scheduled"`
+	Started     time.Time     `json:"started" yaml:"started"`                             // Zero unless the degas started
+	Duration    time.Duration `json:"duration" yaml:"duration"`                           // Degas time of the channel (DGT)
+	Skipped     string        `json:"skipped,omitempty" yaml:"skipped,omitempty"`         // Reason the degas did not start
+	Interrupted bool          `json:"interrupted,omitempty" yaml:"interrupted,omitempty"` // Turned OFF for a critical period
+	Error       string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+/*
+Scheduler of Hot Cathode degas. At each time of the schedule,
+degas is started on every channel whose degas time (DGT) does
+not overlap a blackout, outside the critical periods, once the
+filament is ON and the pressure is below the degas limit, as
+checked with DegasGuard, and the precondition, if any, passes.
+Critical is polled while the channels degas, and degas is
+turned OFF as soon as it returns true.
+
+The channels degas together, which the controller allows for
+up to three low power sensors. Runs are kept in the history
+once done
+*/
+type DegasScheduler struct {
+	Device       *MKS937B
+	Schedule     Schedule
+	Channels     []int
+	Blackouts    []Blackout
+	Critical     func() bool             // Pump-down critical period defined by the application
+	Precondition func(channel int) error // Additional check before each degas
+	PollInterval time.Duration           // Polling of Critical while degassing
+	HistorySize  int                     // Number of runs kept in the history
+	OnRun        func(run DegasRun)
+
+	history []DegasRun
+	stop    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex
+}
+
+/*
+Creates a new degas scheduler for Hot Cathodes on channels
+that must be 1, 3 or 5, polling Critical every minute and
+keeping the last 100 runs
+*/
+func NewDegasScheduler(device *MKS937B, schedule Schedule, channels ...int) (*DegasScheduler, error) {
+	for _, channel := range channels {
+		if err := device.checkChannel(ControlCommands, channel); err != nil {
+			return nil, err
+		}
+	}
+	return &DegasScheduler{
+		Device:       device,
+		Schedule:     schedule,
+		Channels:     channels,
+		PollInterval: time.Minute,
+		HistorySize:  100,
+	}, nil
+}
+
+/*
+Starts the scheduler in background. It is stopped when the
+device is closed
+*/
+func (s *DegasScheduler) Start() {
+	s.Device.attach(s)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+/*
+Stops the scheduler and waits for the background routine to
+end. Degas in progress is left to the controller, which turns
+it OFF after the degas time
+*/
+func (s *DegasScheduler) Stop() {
+	s.mutex.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mutex.Unlock()
+
+	s.Device.detach(s)
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+/*
+Returns the runs of the scheduler, oldest first
+*/
+func (s *DegasScheduler) History() []DegasRun {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.history)
+}
+
+/*
+Adds a run to the history and reports it
+*/
+func (s *DegasScheduler) record(run DegasRun) {
+	s.mutex.Lock()
+	s.history = append(s.history, run)
+	if s.HistorySize > 0 && len(s.history) > s.HistorySize {
+		s.history = slices.Delete(s.history, 0, len(s.history)-s.HistorySize)
+	}
+	s.mutex.Unlock()
+
+	if s.OnRun != nil {
+		s.OnRun(run)
+	}
+}
+
+/*
+Returns the reason a degas lasting duration cannot start now,
+or an empty string
+*/
+func (s *DegasScheduler) blocked(channel int, duration time.Duration) string {
+	now := time.Now()
+	for _, blackout := range s.Blackouts {
+		if blackout.overlaps(now, duration) {
+			return fmt.Sprintf("overlaps blackout %s", blackout.Schedule)
+		}
+	}
+	if s.Critical != nil && s.Critical() {
+		return "critical period"
+	}
+	if err := s.Device.checkDegasSafety(channel); err != nil {
+		return err.Error()
+	}
+	if s.Precondition != nil {
+		if err := s.Precondition(channel); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+/*
+Starts degas on the channels that can degas and returns their
+runs. Other runs are recorded right away
+*/
+func (s *DegasScheduler) begin(scheduled time.Time) []DegasRun {
+	var active []DegasRun
+	for _, channel := range s.Channels {
+		run := DegasRun{Channel: channel, Scheduled: scheduled}
+		minutes, err := s.Device.GetDegasTime(channel)
+		if err != nil {
+			run.Error = err.Error()
+			s.record(run)
+			continue
+		}
+		run.Duration = time.Duration(minutes) * time.Minute
+		if run.Skipped = s.blocked(channel, run.Duration); run.Skipped != "" {
+			s.record(run)
+			continue
+		}
+		if err := s.Device.Set(fmt.Sprintf("DG%d", channel), "ON"); err != nil {
+			run.Error = err.Error()
+			s.record(run)
+			continue
+		}
+		run.Started = time.Now()
+		active = append(active, run)
+	}
+	return active
+}
+
+/*
+Waits for the degas of the runs to end, turning it OFF when a
+critical period begins, and records them. Returns false when
+the scheduler is stopped meanwhile
+*/
+func (s *DegasScheduler) watch(active []DegasRun, stop chan struct{}) bool {
+	defer func() {
+		for _, run := range active {
+			s.record(run)
+		}
+	}()
+
+	var end time.Time
+	for _, run := range active {
+		if finish := run.Started.Add(run.Duration); finish.After(end) {
+			end = finish
+		}
+	}
+	poll := s.PollInterval
+	if poll <= 0 {
+		poll = time.Minute
+	}
+	for time.Now().Before(end) {
+		timer := time.NewTimer(min(poll, time.Until(end)))
+		select {
+		case <-stop:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		if s.Critical == nil || !s.Critical() {
+			continue
+		}
+		for idx, run := range active {
+			if time.Now().Before(run.Started.Add(run.Duration)) {
+				active[idx].Interrupted = true
+				if err := s.Device.Set(fmt.Sprintf("DG%d", run.Channel), "OFF"); err != nil {
+					active[idx].Error = err.Error()
+				}
+			}
+		}
+		return true
+	}
+	return true
+}
+
+/*
+Scheduler loop executed until stop is closed
+*/
+func (s *DegasScheduler) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		next := s.Schedule.Next(time.Now())
+		if next.IsZero() {
+			<-stop
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if !s.watch(s.begin(next), stop) {
+			return
+		}
+	}
+}
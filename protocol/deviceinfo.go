@@ -0,0 +1,87 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"slices"
+	"time"
+)
+
+/*
+Relay of an installed module, with the channel whose sensor
+drives it
+*/
+type RelaySummary struct {
+	Relay     int    `json:"relay" yaml:"relay"`
+	Channel   int    `json:"channel" yaml:"channel"`
+	Direction string `json:"direction" yaml:"direction"` // ABOVE or BELOW
+	Enable    string `json:"enable" yaml:"enable"`       // SET, ENABLE or CLEAR
+}
+
+/*
+Description of the controller: identity, communication
+settings, pressure unit, module layout and relays
+*/
+type DeviceInfo struct {
+	System SystemInfo     `json:"system" yaml:"system"`
+	Relays []RelaySummary `json:"relays" yaml:"relays"`
+	ReadAt time.Time      `json:"read_at" yaml:"read_at"`
+}
+
+/*
+Returns the description of the controller, read on the first
+call and then cached. Settings changed afterwards, e.g. the
+unit, are only reflected after RefreshDeviceInfo
+*/
+func (m *MKS937B) DeviceInfo() (DeviceInfo, error) {
+	m.mutex.Lock()
+	cached := m.deviceInfo
+	m.mutex.Unlock()
+
+	if cached != nil {
+		info := *cached
+		info.System.Modules = slices.Clone(info.System.Modules)
+		info.Relays = slices.Clone(info.Relays)
+		return info, nil
+	}
+	return m.RefreshDeviceInfo()
+}
+
+/*
+Reads the description of the controller again and caches it
+*/
+func (m *MKS937B) RefreshDeviceInfo() (DeviceInfo, error) {
+	info := DeviceInfo{ReadAt: time.Now()}
+	var err error
+
+	if info.System, err = m.SystemInfo(); err != nil {
+		return info, err
+	}
+	for relay := 1; relay <= 12; relay++ {
+		slot := (relay - 1) / 4
+		if slot >= len(info.System.Modules) || info.System.Modules[slot] == "NC" {
+			continue
+		}
+		summary := RelaySummary{Relay: relay, Channel: RelayChannel(relay, info.System.Modules[slot])}
+		if summary.Direction, err = m.GetRelayDirection(relay); err != nil {
+			return info, err
+		}
+		if summary.Enable, err = m.GetRelayEnable(relay); err != nil {
+			return info, err
+		}
+		info.Relays = append(info.Relays, summary)
+	}
+
+	// The cache keeps its own slices, the caller may change the returned ones
+	cached := info
+	cached.System.Modules = slices.Clone(info.System.Modules)
+	cached.Relays = slices.Clone(info.Relays)
+	m.mutex.Lock()
+	m.deviceInfo = &cached
+	m.mutex.Unlock()
+	return info, nil
+}
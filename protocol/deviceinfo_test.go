@@ -0,0 +1,69 @@
+package protocol_test
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeviceInfo(t *testing.T) {
+	system := []string{
+		"@001AD?;FF", "@001ACK001;FF",
+		"@001BR?;FF", "@001ACK9600;FF",
+		"@001PAR?;FF", "@001ACKNONE;FF",
+		"@001DLY?;FF", "@001ACK8;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001SN?;FF", "@001ACK1234;FF",
+		"@001MT?;FF", "@001ACKCC,NC,NC,NA;FF",
+		"@001FV1?;FF", "@001ACK1.01;FF",
+		"@001FV2?;FF", "@001ACK1.02;FF",
+		"@001FV3?;FF", "@001ACK1.03;FF",
+		"@001FV4?;FF", "@001ACK1.04;FF",
+		"@001FV5?;FF", "@001ACK1.05;FF",
+		"@001FV6?;FF", "@001ACK1.06;FF",
+	}
+	relays := []string{
+		"@001SD1?;FF", "@001ACKBELOW;FF",
+		"@001EN1?;FF", "@001ACKSET;FF",
+		"@001SD2?;FF", "@001ACKBELOW;FF",
+		"@001EN2?;FF", "@001ACKENABLE;FF",
+		"@001SD3?;FF", "@001ACKBELOW;FF",
+		"@001EN3?;FF", "@001ACKCLEAR;FF",
+		"@001SD4?;FF", "@001ACKBELOW;FF",
+		"@001EN4?;FF", "@001ACKCLEAR;FF",
+	}
+	// Read once, then refreshed
+	pairs := slices.Concat(system, relays, system, relays)
+	pairs[len(system)+len(relays)+9] = "@001ACKmbar;FF"
+	device := replayDevice(t, pairs...)
+
+	info, err := device.DeviceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.System.SerialNumber != "1234" || len(info.Relays) != 4 || info.Relays[1].Enable != "ENABLE" {
+		t.Errorf("unexpected device info %+v", info)
+	}
+
+	// Cached copies are independent and read nothing
+	info.System.Modules[0] = "HC"
+	info.Relays[0].Enable = "CLEAR"
+	cached, err := device.DeviceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.System.Modules[0] != "CC" || cached.Relays[0].Enable != "SET" || !cached.ReadAt.Equal(info.ReadAt) {
+		t.Errorf("expected the cached device info, got %+v", cached)
+	}
+
+	refreshed, err := device.RefreshDeviceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.System.Unit != "mbar" {
+		t.Errorf("expected the refreshed unit mbar, got %q", refreshed.System.Unit)
+	}
+	if cached, _ := device.DeviceInfo(); cached.System.Unit != "mbar" {
+		t.Errorf("expected the refreshed device info to be cached, got %q", cached.System.Unit)
+	}
+	expectReplayed(t, device)
+}
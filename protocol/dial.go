@@ -0,0 +1,83 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+// Dial timeout of TCP connections without deadline, the one used
+// by unicomm
+const defaultDialTimeout = 500 * time.Millisecond
+
+// Bounds every connection attempt, including the ones made by
+// Connect, to the given duration
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(m *MKS937B) {
+		m.dialTimeout = timeout
+	}
+}
+
+/*
+Connects the communication, giving up when the context is done.
+The caller must hold the mutex
+*/
+func (m *MKS937B) dial(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if tcp, ok := m.Communication.(*unicommtcp.UnicommTCP); ok {
+		return dialTCP(ctx, tcp)
+	}
+	if port, ok := m.Communication.(*TLSPort); ok {
+		return port.dial(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- m.Communication.Connect() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Closes the connection if the abandoned attempt succeeds later
+		communication := m.Communication
+		go func() {
+			if <-done == nil {
+				communication.Disconnect()
+			}
+		}()
+		return ctx.Err()
+	}
+}
+
+/*
+Dials a TCP communication with the context instead of its own
+fixed timeout
+*/
+func dialTCP(ctx context.Context, tcp *unicommtcp.UnicommTCP) error {
+	if tcp.IsConnected() {
+		return errors.New("there is a connection already established")
+	}
+	var dialer net.Dialer
+	if _, ok := ctx.Deadline(); !ok {
+		dialer.Timeout = defaultDialTimeout
+	}
+	address := net.JoinHostPort(tcp.Options.Host, strconv.FormatUint(uint64(tcp.Options.Port), 10))
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		tcp.Connection = nil
+		return err
+	}
+	tcp.Connection = connection
+	return nil
+}
@@ -0,0 +1,120 @@
+package protocol_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestConnectContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback listener:", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	tcp := unicommtcp.NewTCP(unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(port)})
+	device := &protocol.MKS937B{Communication: tcp, Address: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := device.ConnectContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a canceled error, got %v", err)
+	}
+
+	if err := device.ConnectContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer device.Disconnect()
+	if !device.IsConnected() {
+		t.Error("expected the device to be connected")
+	}
+}
+
+func TestLazyConnect(t *testing.T) {
+	replay := newReplay(t, "@001PR1?;FF", "@001ACK1.00E-07;FF")
+	device := &protocol.MKS937B{Communication: replay, Address: 1}
+	if _, err := device.GetPressure(1); !errors.Is(err, protocol.ErrNotConnected) {
+		t.Errorf("expected a not connected error, got %v", err)
+	}
+
+	device.Apply(protocol.WithLazyConnect())
+	if _, err := device.GetPressure(1); err != nil {
+		t.Fatal(err)
+	}
+	if !device.IsConnected() {
+		t.Error("expected the device to be connected")
+	}
+}
+
+func TestClose(t *testing.T) {
+	device := replayDevice(t)
+	events, _ := device.Events().Subscribe(4)
+	watchdog := protocol.NewWatchdog(device, time.Minute)
+	watchdog.Interval = time.Hour
+	watchdog.Start()
+
+	if err := device.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if device.IsConnected() {
+		t.Error("expected the device to be disconnected")
+	}
+	var kinds []protocol.EventKind
+	for event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	if len(kinds) != 1 || kinds[0] != protocol.EventDisconnected {
+		t.Errorf("expected a single disconnected event, got %v", kinds)
+	}
+	if _, err := device.GetPressure(1); !errors.Is(err, protocol.ErrClosed) {
+		t.Errorf("expected a closed error, got %v", err)
+	}
+}
+
+/*
+Bus where a single controller answers the serial number query
+*/
+type singleControllerBus struct {
+	address string
+	request string
+}
+
+func (b *singleControllerBus) Connect() error              { return nil }
+func (b *singleControllerBus) Disconnect() error           { return nil }
+func (b *singleControllerBus) IsConnected() bool           { return true }
+func (b *singleControllerBus) Read(n uint) ([]byte, error) { return nil, errors.New("not supported") }
+func (b *singleControllerBus) Write(message []byte) error {
+	b.request = string(message)
+	return nil
+}
+func (b *singleControllerBus) ReadUntil(delimiter string) ([]byte, error) {
+	if !strings.HasPrefix(b.request, "@"+b.address) {
+		return nil, errors.New("read until timeout")
+	}
+	return []byte("@" + b.address + "ACK1234;FF"), nil
+}
+
+func TestAddressProbe(t *testing.T) {
+	device := &protocol.MKS937B{Communication: &singleControllerBus{address: "003"}, Address: 1}
+	device.Apply(protocol.WithAddressProbe(4, 20*time.Millisecond))
+
+	_, err := device.GetSerialNumber()
+	var mismatch *protocol.ErrAddressMismatch
+	if !errors.As(err, &mismatch) || mismatch.Answered != 3 {
+		t.Fatalf("expected a mismatch with address 3, got %v", err)
+	}
+	if device.Address != 1 {
+		t.Errorf("expected the address to be kept, got %d", device.Address)
+	}
+	if _, err := device.GetSerialNumber(); errors.As(err, &mismatch) {
+		t.Error("expected a single probe per connection")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"time"
+
+	"github.com/devicehub-go/mks-937b/core"
+	"github.com/devicehub-go/unicomm"
+)
+
+type DiscoveredDevice struct {
+	Address      int      `json:"address" yaml:"address"`
+	Firmware     string   `json:"firmware" yaml:"firmware"`
+	SerialNumber string   `json:"serial_number" yaml:"serial_number"`
+	Modules      []string `json:"modules" yaml:"modules"`
+}
+
+/*
+Changes the read timeout of a serial or TCP communication and
+returns a function restoring the previous one. Other
+communications are left unchanged
+*/
+func setReadTimeout(communication unicomm.Unicomm, timeout time.Duration) func() {
+	if port, ok := serialPort(communication); ok {
+		previous := port.Options.ReadTimeout
+		port.Options.ReadTimeout = timeout
+		return func() { port.Options.ReadTimeout = previous }
+	}
+	if tcp, ok := tcpPort(communication); ok {
+		previous := tcp.Options.ReadTimeout
+		tcp.Options.ReadTimeout = timeout
+		return func() { tcp.Options.ReadTimeout = previous }
+	}
+	if rfc2217, ok := communication.(*RFC2217); ok {
+		previous := rfc2217.Options.ReadTimeout
+		rfc2217.Options.ReadTimeout = timeout
+		return func() { rfc2217.Options.ReadTimeout = previous }
+	}
+	return func() {}
+}
+
+/*
+Probes every address from first to last on a RS-485 bus and
+returns the devices that answered, with their identity. Each
+address is probed with the serial number query and the given
+timeout, and the identity of responding devices is read with
+the regular timeout of the communication. Identity fields that
+cannot be read are left empty.
+
+The bus is connected if needed, and disconnected at the end
+only in that case
+*/
+func Discover(bus unicomm.Unicomm, first int, last int, timeout time.Duration) ([]DiscoveredDevice, error) {
+	if first < 1 || 254 < first {
+		return nil, NewErrInvalidAddress(first)
+	}
+	if last < first || 254 < last {
+		return nil, NewErrInvalidAddress(last)
+	}
+	if !bus.IsConnected() {
+		if err := bus.Connect(); err != nil {
+			return nil, err
+		}
+		defer bus.Disconnect()
+	}
+
+	var devices []DiscoveredDevice
+	for address := first; address <= last; address++ {
+		if found, ok := probeIdentity(bus, address, timeout); ok {
+			devices = append(devices, found)
+		}
+	}
+	return devices, nil
+}
+
+/*
+Probes an address of a bus and reads the identity of the device
+answering, holding the bus so the devices using it wait
+*/
+func probeIdentity(bus unicomm.Unicomm, address int, timeout time.Duration) (DiscoveredDevice, bool) {
+	unlock := lockBus(bus)
+	defer unlock()
+
+	device := heldBusDevice(bus, address)
+	restore := setReadTimeout(bus, timeout)
+	serialNumber, err := device.GetSerialNumber()
+	restore()
+	if err != nil {
+		return DiscoveredDevice{}, false
+	}
+
+	found := DiscoveredDevice{Address: address, SerialNumber: serialNumber}
+	found.Firmware, _ = device.GetFirmwareVersion()
+	found.Modules, _ = device.GetModuleTypes()
+	return found, true
+}
+
+// Settings of the address probe made when the device does not
+// answer
+type addressProbe struct {
+	radius  int
+	timeout time.Duration
+}
+
+// When the device does not answer, probes the addresses within
+// radius of the configured one (all addresses for 0), nearest
+// first, waiting at most timeout for each answer. If another
+// controller answers, the error is returned wrapped in an
+// ErrAddressMismatch with its address; the configured address is
+// never changed. The probe is made once per connection
+func WithAddressProbe(radius int, timeout time.Duration) Option {
+	return func(m *MKS937B) {
+		m.addressProbe = &addressProbe{radius: radius, timeout: timeout}
+	}
+}
+
+/*
+Returns the addresses to probe around an address, nearest first
+*/
+func (p addressProbe) candidates(address int) []int {
+	radius := p.radius
+	if radius <= 0 {
+		radius = 253
+	}
+	var addresses []int
+	for distance := 1; distance <= radius; distance++ {
+		for _, candidate := range []int{address - distance, address + distance} {
+			if 1 <= candidate && candidate <= 254 {
+				addresses = append(addresses, candidate)
+			}
+		}
+	}
+	return addresses
+}
+
+/*
+Probes the addresses around the configured one after a command
+got no answer, and reports the first one that answered in the
+error. The caller must hold the mutex
+*/
+func (m *MKS937B) probeAddress(err error) error {
+	if m.addressProbe == nil || m.addressProbed {
+		return err
+	}
+	m.addressProbed = true
+
+	unlock := m.lockBus()
+	defer unlock()
+	restore := setReadTimeout(m.Communication, m.addressProbe.timeout)
+	defer restore()
+	for _, address := range m.addressProbe.candidates(m.Address) {
+		probe := heldBusDevice(m.Communication, address)
+		exchange, probeErr := probe.transaction("SN", core.QueryFrame(address, "SN"))
+		if probeErr == nil && !exchange.nak {
+			return NewErrAddressMismatch(m.Address, address, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package protocol_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Bus of controllers answering fixed replies by address and
+request, e.g. "SN?", and staying silent otherwise
+*/
+type multiControllerBus struct {
+	controllers map[string]map[string]string
+	connected   bool
+	connects    int
+	disconnects int
+	requests    []string
+}
+
+func (b *multiControllerBus) Connect() error {
+	b.connected = true
+	b.connects++
+	return nil
+}
+
+func (b *multiControllerBus) Disconnect() error {
+	b.connected = false
+	b.disconnects++
+	return nil
+}
+
+func (b *multiControllerBus) IsConnected() bool           { return b.connected }
+func (b *multiControllerBus) Read(n uint) ([]byte, error) { return nil, errors.New("not supported") }
+
+func (b *multiControllerBus) Write(message []byte) error {
+	b.requests = append(b.requests, string(message))
+	return nil
+}
+
+func (b *multiControllerBus) ReadUntil(delimiter string) ([]byte, error) {
+	request := strings.TrimSuffix(b.requests[len(b.requests)-1], ";FF")
+	address := request[1:4]
+	if reply, ok := b.controllers[address][request[4:]]; ok {
+		return []byte("@" + address + "ACK" + reply + ";FF"), nil
+	}
+	return nil, errors.New("read until timeout")
+}
+
+func TestDiscover(t *testing.T) {
+	bus := &multiControllerBus{controllers: map[string]map[string]string{
+		"002": {
+			"SN?": "1234", "MT?": "CC,PR,NC,NA",
+			"FV1?": "1.0", "FV2?": "1.0", "FV3?": "1.0", "FV4?": "1.0", "FV5?": "1.0", "FV6?": "1.1",
+		},
+		// Answers the serial number and modules but not the firmware
+		"004": {"SN?": "5678", "MT?": "HC,NC,NC,NA"},
+	}}
+	devices, err := protocol.Discover(bus, 1, 5, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 2 || devices[0].Address != 2 || devices[1].Address != 4 {
+		t.Fatalf("expected addresses 2 and 4, got %+v", devices)
+	}
+	if devices[0].SerialNumber != "1234" || !strings.HasSuffix(devices[0].Firmware, "Main: 1.1") {
+		t.Errorf("unexpected identity %+v", devices[0])
+	}
+	if found := devices[1]; found.SerialNumber != "5678" || found.Firmware != "" ||
+		!slices.Equal(found.Modules, []string{"HC", "NC", "NC", "NA"}) {
+		t.Errorf("expected a partial identity, got %+v", found)
+	}
+	// Silent addresses are only probed with the serial number
+	for _, request := range bus.requests {
+		if strings.HasPrefix(request, "@001") && request != "@001SN?;FF" {
+			t.Errorf("unexpected request %s to a silent address", request)
+		}
+	}
+	if bus.connects != 1 || bus.disconnects != 1 || bus.connected {
+		t.Errorf("expected the bus to be connected and disconnected once, got %d and %d", bus.connects, bus.disconnects)
+	}
+
+	// A connected bus is left connected
+	bus.Connect()
+	if _, err := protocol.Discover(bus, 2, 2, 10*time.Millisecond); err != nil || !bus.connected || bus.disconnects != 1 {
+		t.Errorf("expected the bus to stay connected, got %v", err)
+	}
+	var invalid *protocol.ErrInvalidAddress
+	if _, err := protocol.Discover(bus, 5, 4, time.Millisecond); !errors.As(err, &invalid) {
+		t.Errorf("expected ErrInvalidAddress, got %v", err)
+	}
+}
+
+func TestAddressProbeOrder(t *testing.T) {
+	bus := &multiControllerBus{controllers: map[string]map[string]string{"007": {"SN?": "1234"}}}
+	bus.Connect()
+	device := &protocol.MKS937B{Communication: bus, Address: 5}
+	device.Apply(protocol.WithAddressProbe(2, 10*time.Millisecond))
+
+	var mismatch *protocol.ErrAddressMismatch
+	if _, err := device.GetSerialNumber(); !errors.As(err, &mismatch) || mismatch.Answered != 7 {
+		t.Fatalf("expected a mismatch with address 7, got %v", err)
+	}
+	// Nearest first, below before above
+	expected := []string{"@005SN?;FF", "@004SN?;FF", "@006SN?;FF", "@003SN?;FF", "@007SN?;FF"}
+	if !slices.Equal(bus.requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, bus.requests)
+	}
+
+	// The probe stops at the first answer and at the radius
+	bus.controllers = map[string]map[string]string{"009": {"SN?": "1234"}}
+	bus.requests = nil
+	device = &protocol.MKS937B{Communication: bus, Address: 1}
+	device.Apply(protocol.WithAddressProbe(3, 10*time.Millisecond))
+	if _, err := device.GetSerialNumber(); errors.As(err, &mismatch) {
+		t.Errorf("expected no mismatch beyond the radius, got %v", err)
+	}
+	expected = []string{"@001SN?;FF", "@002SN?;FF", "@003SN?;FF", "@004SN?;FF"}
+	if !slices.Equal(bus.requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, bus.requests)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"strconv"
+	"strings"
+)
+
+var superscripts = strings.NewReplacer(
+	"-", "⁻", "0", "⁰", "1", "¹", "2", "²", "3", "³", "4", "⁴",
+	"5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹",
+)
+
+/*
+Formats a value for operators with the given number of
+significant digits, e.g. 5.2×10⁻⁷. Values between 1 and 10 have
+no power of ten
+*/
+func FormatPretty(value float64, digits int) string {
+	digits = max(digits, 1)
+	formatted := strconv.FormatFloat(value, 'e', digits-1, 64)
+	idx := strings.IndexByte(formatted, 'e')
+	if value == 0 || idx < 0 {
+		return strconv.FormatFloat(value, 'f', digits-1, 64)
+	}
+	mantissa := formatted[:idx]
+	exponent, _ := strconv.Atoi(formatted[idx+1:])
+	if exponent == 0 {
+		return mantissa
+	}
+	return mantissa + "×10" + superscripts.Replace(strconv.Itoa(exponent))
+}
+
+/*
+Formats the pressure for operators with the given number of
+significant digits, e.g. 5.2×10⁻⁷ Torr
+*/
+func (p Pressure) Pretty(digits int) string {
+	return FormatPretty(p.Value, digits) + " " + p.Unit
+}
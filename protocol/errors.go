@@ -1,7 +1,7 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 24rd, 2025
-Last update: September 24rd, 2025
+Last update: October 17th, 2026
 */
 
 package protocol
@@ -9,11 +9,21 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devicehub-go/mks-937b/core"
 )
 
 var (
 	ErrNotConnected = errors.New("device not connected")
 	ErrInvalidParameter = errors.New("invalid parameter")
+	ErrReadOnly = errors.New("device is in read-only mode")
+	ErrEmergencyStop = errors.New("emergency stop is latched")
+	ErrEmergencyStopRegistered = errors.New("an emergency stop is already registered")
+	ErrInterlockNotClear = errors.New("pressure is not below the interlock reset level")
+	ErrUnsupportedTransport = errors.New("not supported by the communication transport")
+	ErrClosed = errors.New("device is closed")
 )
 
 type ErrInvalidAddress struct {
@@ -29,54 +39,110 @@ func (e *ErrInvalidAddress) Error() string {
 	)
 }
 
-type ErrUnexpectedReply struct {
-	Sent string
-	Got string
+type ErrTransaction struct {
+	ID string
+	Command string
+	Err error
+}
+func NewErrTransaction(id string, command string, err error) *ErrTransaction {
+	return &ErrTransaction{
+		ID: id,
+		Command: command,
+		Err: err,
+	}
 }
-func NewErrUnexpectedReply(sent string, got string) *ErrUnexpectedReply {
-	return &ErrUnexpectedReply{
-		Sent: sent,
-		Got: got,
+func (e *ErrTransaction) Error() string {
+	return fmt.Sprintf(
+		"%s [%s]: %v",
+		e.Command, e.ID, e.Err,
+	)
+}
+func (e *ErrTransaction) Unwrap() error {
+	return e.Err
+}
+
+type ErrAddressMismatch struct {
+	Configured int
+	Answered int
+	Err error
+}
+func NewErrAddressMismatch(configured int, answered int, err error) *ErrAddressMismatch {
+	return &ErrAddressMismatch{
+		Configured: configured,
+		Answered: answered,
+		Err: err,
 	}
 }
-func (e *ErrUnexpectedReply) Error() string {
+func (e *ErrAddressMismatch) Error() string {
 	return fmt.Sprintf(
-		"not expected response, sent %s got %s",
-		e.Sent, e.Got,
+		"%v (no answer at address %03d, a controller answers at %03d)",
+		e.Err, e.Configured, e.Answered,
 	)
 }
+func (e *ErrAddressMismatch) Unwrap() error {
+	return e.Err
+}
 
-type ErrUnexpectedAddress struct {
+type ErrUnexpectedReply = core.ErrUnexpectedReply
+func NewErrUnexpectedReply(sent string, got string) *ErrUnexpectedReply {
+	return core.NewErrUnexpectedReply(sent, got)
+}
+
+type ErrUnexpectedAddress = core.ErrUnexpectedAddress
+func NewErrUnexpectedAddress(expected string, got string) *ErrUnexpectedAddress {
+	return core.NewErrUnexpectedAddress(expected, got)
+}
+
+type ErrUnexpectedParameter struct {
 	Expected string
 	Got string
 }
-func NewErrUnexpectedAddress(expected string, got string) *ErrUnexpectedAddress {
-	return &ErrUnexpectedAddress{
+func NewErrUnexpectedParamater(expected string, got string) *ErrUnexpectedParameter {
+	return &ErrUnexpectedParameter{
 		Expected: expected,
 		Got: got,
 	}
 }
-func (e *ErrUnexpectedAddress) Error() string {
+func (e *ErrUnexpectedParameter) Error() string {
 	return fmt.Sprintf(
-		"invalid received address, expected %s got %s",
+		"invalid received parameter, expected %s got %s",
 		e.Expected, e.Got,
 	)
 }
 
-type ErrUnexpectedParameter struct {
+type ErrReadbackMismatch struct {
+	Field string
 	Expected string
 	Got string
 }
-func NewErrUnexpectedParamater(expected string, got string) *ErrUnexpectedParameter {
-	return &ErrUnexpectedParameter{
+func NewErrReadbackMismatch(field string, expected string, got string) *ErrReadbackMismatch {
+	return &ErrReadbackMismatch{
+		Field: field,
 		Expected: expected,
 		Got: got,
 	}
 }
-func (e *ErrUnexpectedParameter) Error() string {
+func (e *ErrReadbackMismatch) Error() string {
 	return fmt.Sprintf(
-		"invalid received parameter, expected %s got %s",
-		e.Expected, e.Got,
+		"%s reads back %s after writing %s",
+		e.Field, e.Got, e.Expected,
+	)
+}
+
+type ErrUnsupportedVersion struct {
+	Supported int
+	Got int
+}
+func NewErrUnsupportedVersion(supported int, got int) *ErrUnsupportedVersion {
+	return &ErrUnsupportedVersion{
+		Supported: supported,
+		Got: got,
+	}
+}
+func (e *ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf(
+		"unsupported document version, expected up to %d got %d",
+		e.Supported, e.Got,
 	)
 }
 
@@ -99,14 +165,28 @@ func (e *ErrInvalidChannel) Error() string {
 	)
 }
 
-type ErrInvalidChannelControl struct { Channel int }
-func NewErrInvalidChannelControl(channel int) *ErrInvalidChannelControl {
-	return &ErrInvalidChannelControl{ Channel: channel }
+type ErrInvalidChannelControl struct {
+	Valid []int // Control channels of the variant
+	Channel int
+}
+func NewErrInvalidChannelControl(valid []int, channel int) *ErrInvalidChannelControl {
+	return &ErrInvalidChannelControl{
+		Valid: valid,
+		Channel: channel,
+	}
 }
 func (e *ErrInvalidChannelControl) Error() string {
+	values := make([]string, len(e.Valid))
+	for idx, channel := range e.Valid {
+		values[idx] = strconv.Itoa(channel)
+	}
+	among := strings.Join(values, ", ")
+	if last := len(values) - 1; last > 0 {
+		among = strings.Join(values[:last], ", ") + " or " + values[last]
+	}
 	return fmt.Sprintf(
-		"channel must be an integer value among 1, 3 or 5, got %d",
-		e.Channel,
+		"channel must be an integer value among %s, got %d",
+		among, e.Channel,
 	)
 }
 
@@ -145,14 +225,37 @@ func (e *ErrInvalidUnit) Error() string {
 
 /* Control commands errors */
 
-type ErrInvalidPRO struct { Got float64 }
-func NewErrInvalidPRO(got float64) *ErrInvalidPRO {
-	return &ErrInvalidPRO{ Got: got }
+type ErrInvalidPRO struct {
+	Got float64
+	Unit string
+}
+func NewErrInvalidPRO(got float64, unit string) *ErrInvalidPRO {
+	return &ErrInvalidPRO{ Got: got, Unit: unit }
 }
 func (e *ErrInvalidPRO) Error() string {
+	factor := torrConversion[e.Unit]
 	return fmt.Sprintf(
-		"The protection target must be 0 (disabled) or between 1e-5 and 1e-2, got %f",
-		e.Got,
+		"The protection target must be 0 (disabled) or between %.2E and %.2E %s, got %.2E",
+		1e-5*factor, 1e-2*factor, e.Unit, e.Got,
+	)
+}
+
+type ErrInvalidPressureRange struct {
+	MinValue, MaxValue, Got float64
+	Unit string
+}
+func NewErrInvalidPressureRange(min, max, got float64, unit string) *ErrInvalidPressureRange {
+	return &ErrInvalidPressureRange{
+		MinValue: min,
+		MaxValue: max,
+		Got: got,
+		Unit: unit,
+	}
+}
+func (e *ErrInvalidPressureRange) Error() string {
+	return fmt.Sprintf(
+		"The target value must be between %.2E and %.2E %s, got %.2E",
+		e.MinValue, e.MaxValue, e.Unit, e.Got,
 	)
 }
 
@@ -226,4 +329,94 @@ func (e *ErrInvalidGas) Error() string {
 		"The emission current must be Nitrogen, Argon, Helium or Custom, got %s",
 		e.Got,
 	)
-}
\ No newline at end of file
+}
+
+type ErrInvalidCombination struct { Got string }
+func NewErrInvalidCombination(got string) *ErrInvalidCombination {
+	return &ErrInvalidCombination{Got: got}
+}
+func (e *ErrInvalidCombination) Error() string {
+	return fmt.Sprintf(
+		"combination sensor must be A1, A2, B1, B2, C1, C2 or NA, got %s",
+		e.Got,
+	)
+}
+
+/* Relay commands errors */
+
+type ErrInvalidRelay struct {
+	MaxRelay int // Relays of the variant, from 1
+	Got int
+}
+func NewErrInvalidRelay(max int, got int) *ErrInvalidRelay {
+	return &ErrInvalidRelay{MaxRelay: max, Got: got}
+}
+func (e *ErrInvalidRelay) Error() string {
+	return fmt.Sprintf(
+		"relay must be an integer value between 1 and %d, got %d",
+		e.MaxRelay, e.Got,
+	)
+}
+
+type ErrInvalidRelayDirection struct { Got string }
+func NewErrInvalidRelayDirection(got string) *ErrInvalidRelayDirection {
+	return &ErrInvalidRelayDirection{Got: got}
+}
+func (e *ErrInvalidRelayDirection) Error() string {
+	return fmt.Sprintf(
+		"relay direction must be ABOVE or BELOW, got %s",
+		e.Got,
+	)
+}
+
+type ErrInvalidRelayEnable struct { Got string }
+func NewErrInvalidRelayEnable(got string) *ErrInvalidRelayEnable {
+	return &ErrInvalidRelayEnable{Got: got}
+}
+func (e *ErrInvalidRelayEnable) Error() string {
+	return fmt.Sprintf(
+		"relay enable must be SET, ENABLE or CLEAR, got %s",
+		e.Got,
+	)
+}
+
+type ErrIdentityChanged struct {
+	Address int
+	Previous Identity
+	Current Identity
+}
+func NewErrIdentityChanged(address int, previous Identity, current Identity) *ErrIdentityChanged {
+	return &ErrIdentityChanged{
+		Address: address,
+		Previous: previous,
+		Current: current,
+	}
+}
+func (e *ErrIdentityChanged) Error() string {
+	if e.Previous.SerialNumber == e.Current.SerialNumber {
+		return fmt.Sprintf(
+			"controller at address %03d changed firmware, %s became %s",
+			e.Address, e.Previous.Firmware, e.Current.Firmware,
+		)
+	}
+	return fmt.Sprintf(
+		"controller at address %03d changed, serial number %s became %s",
+		e.Address, e.Previous.SerialNumber, e.Current.SerialNumber,
+	)
+}
+
+/* Safety errors */
+
+type ErrDegasUnsafe struct {
+	Channel int
+	Reason  string
+}
+func NewErrDegasUnsafe(channel int, reason string) *ErrDegasUnsafe {
+	return &ErrDegasUnsafe{Channel: channel, Reason: reason}
+}
+func (e *ErrDegasUnsafe) Error() string {
+	return fmt.Sprintf(
+		"degas is unsafe on channel %d, %s",
+		e.Channel, e.Reason,
+	)
+}
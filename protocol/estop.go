@@ -0,0 +1,177 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"sync"
+
+	"github.com/devicehub-go/mks-937b/core"
+)
+
+/*
+Emergency stop latch of a device. Once triggered, the configured
+relays are cleared and every ionization gauge is turned OFF, and
+the device rejects commands that would power gauges, degas or
+activate relays until the latch is reset
+*/
+type EmergencyStop struct {
+	Device         *MKS937B
+	Relays         []int // Relays cleared when triggered
+	DisableControl bool  // Sets the control mode OFF when triggered
+	OnTrigger      func(err error)
+
+	latched bool
+	stop    chan struct{}
+	mutex   sync.Mutex
+}
+
+/*
+Registers an emergency stop on the device. Every signal received
+from source triggers it; a nil source is allowed when Trigger is
+called from a callback instead. A device has at most one
+emergency stop: registering another one returns
+ErrEmergencyStopRegistered until the current one is closed
+*/
+func (m *MKS937B) RegisterEmergencyStop(source <-chan struct{}, relays ...int) (*EmergencyStop, error) {
+	for _, relay := range relays {
+		if err := m.checkChannel(RelayCommands, relay); err != nil {
+			return nil, err
+		}
+	}
+	estop := &EmergencyStop{
+		Device: m,
+		Relays: relays,
+		stop:   make(chan struct{}),
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.emergency != nil {
+		return nil, ErrEmergencyStopRegistered
+	}
+	m.emergency = estop
+	m.attach(estopRoutine{estop})
+	if source != nil {
+		go estop.watch(source)
+	}
+	return estop, nil
+}
+
+/*
+Emergency stop as a routine of the device, closed with it
+*/
+type estopRoutine struct {
+	estop *EmergencyStop
+}
+
+/*
+Closes the emergency stop
+*/
+func (r estopRoutine) Stop() {
+	r.estop.Close()
+}
+
+/*
+Latches the emergency stop, clears the relays and brings the
+device to its safe state, ahead of the commands waiting for the
+device. The read-only mode and the authorizer do not apply to
+these settings. All actions are attempted even if one of them
+fails
+*/
+func (e *EmergencyStop) Trigger() error {
+	e.mutex.Lock()
+	e.latched = true
+	e.mutex.Unlock()
+
+	err := e.Device.safeState(e.DisableControl, e.Relays)
+
+	if e.OnTrigger != nil {
+		e.OnTrigger(err)
+	}
+	return err
+}
+
+/*
+Returns true if the emergency stop was triggered and not reset
+*/
+func (e *EmergencyStop) Latched() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.latched
+}
+
+/*
+Releases the latch. Gauges and relays are not restored
+*/
+func (e *EmergencyStop) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.latched = false
+}
+
+/*
+Stops watching the signal source and unregisters the emergency
+stop from the device, which accepts every command again. Closing
+the device closes it too
+*/
+func (e *EmergencyStop) Close() {
+	e.mutex.Lock()
+	select {
+	case <-e.stop:
+	default:
+		close(e.stop)
+	}
+	e.mutex.Unlock()
+
+	e.Device.mutex.Lock()
+	defer e.Device.mutex.Unlock()
+
+	if e.Device.emergency == e {
+		e.Device.emergency = nil
+	}
+	e.Device.detach(estopRoutine{e})
+}
+
+/*
+Returns ErrEmergencyStop if the latch is set and the command
+would power a gauge, start degas, enable control or activate
+a relay
+*/
+func (e *EmergencyStop) check(command string, parameter string) error {
+	if !e.Latched() {
+		return nil
+	}
+	mnemonic, _ := core.SplitCommand(command)
+	switch {
+	case (mnemonic == "CP" || mnemonic == "DG") && parameter == "ON":
+		return ErrEmergencyStop
+	case mnemonic == "EN" && parameter != "CLEAR":
+		return ErrEmergencyStop
+	case mnemonic == "CTL" && parameter != "OFF":
+		return ErrEmergencyStop
+	}
+	return nil
+}
+
+/*
+Triggers the emergency stop for every signal of source
+*/
+func (e *EmergencyStop) watch(source <-chan struct{}) {
+	for {
+		select {
+		case <-e.stop:
+			return
+		case _, ok := <-source:
+			if !ok {
+				return
+			}
+			e.Trigger()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package protocol_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestEmergencyStop(t *testing.T) {
+	device := replayDevice(t,
+		"@001EN2!CLEAR;FF", "@001ACKCLEAR;FF",
+		"@001MT?;FF", "@001ACKHC,PR,NC,NA;FF",
+		"@001DG1?;FF", "@001ACKON;FF",
+		"@001DG1!OFF;FF", "@001ACKOFF;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+	)
+	// The authorizer must not stop the emergency stop
+	denied := true
+	device.Apply(protocol.WithAuthorizer(func(string, int, string) error {
+		if denied {
+			return errors.New("denied")
+		}
+		return nil
+	}))
+	source := make(chan struct{})
+	estop, err := device.RegisterEmergencyStop(source, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := device.RegisterEmergencyStop(nil); !errors.Is(err, protocol.ErrEmergencyStopRegistered) {
+		t.Errorf("expected a registered error, got %v", err)
+	}
+
+	triggered := make(chan error, 1)
+	estop.OnTrigger = func(err error) { triggered <- err }
+	source <- struct{}{}
+	if err := <-triggered; err != nil {
+		t.Fatal(err)
+	}
+	if !estop.Latched() {
+		t.Error("expected the emergency stop to be latched")
+	}
+
+	denied = false
+	if err := device.SetPowerStatus(1, true); !errors.Is(err, protocol.ErrEmergencyStop) {
+		t.Errorf("expected an emergency stop error, got %v", err)
+	}
+	if err := device.SetPowerStatus(1, false); err != nil {
+		t.Errorf("expected turning OFF to be allowed, got %v", err)
+	}
+	estop.Reset()
+	if estop.Latched() {
+		t.Error("expected the latch to be released")
+	}
+
+	estop.Close()
+	if _, err := device.RegisterEmergencyStop(nil); err != nil {
+		t.Errorf("expected a new registration after close, got %v", err)
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("expected every frame to be sent, %d left", remaining)
+	}
+}
+
+func TestEmergencyStopReadOnly(t *testing.T) {
+	device := replayDevice(t,
+		"@001MT?;FF", "@001ACKCC,PR,NC,NA;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+	)
+	device.Apply(protocol.WithReadOnly())
+	estop, err := device.RegisterEmergencyStop(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := estop.Trigger(); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.SafeState(false); !errors.Is(err, protocol.ErrReadOnly) {
+		t.Errorf("expected SafeState to keep the read-only mode, got %v", err)
+	}
+
+	// Closing the device unregisters the emergency stop
+	device.Close(context.Background())
+	if _, err := device.RegisterEmergencyStop(nil); err != nil {
+		t.Errorf("expected a new registration after closing the device, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"sync"
+	"time"
+)
+
+type EventKind int
+
+const (
+	EventConnected EventKind = iota
+	EventDisconnected
+	EventReconnecting
+	EventCommandFailed
+	EventStatusChanged
+	EventAlarmRaised
+	EventSlowCommand
+	EventSessionRestored
+	EventCalibrationDue
+	EventIdentityChanged
+)
+
+func (k EventKind) String() string {
+	names := []string{
+		"connected", "disconnected", "reconnecting",
+		"command_failed", "status_changed", "alarm_raised",
+		"slow_command", "session_restored", "calibration_due",
+		"identity_changed",
+	}
+	if k < 0 || int(k) >= len(names) {
+		return "unknown"
+	}
+	return names[k]
+}
+
+type Event struct {
+	Kind          EventKind
+	Time          time.Time
+	Device        Metadata
+	Address       int
+	Command       string        // Command of command failed and slow command events
+	CorrelationID string        // Transaction of command failed and slow command events
+	Duration      time.Duration // Latency of slow command events
+	Channel       int           // Channel of status changed and alarm events
+	Gauge         Gauge         // Facility gauge of the channel
+	Status        string        // New reading status of status changed events
+	Message       string        // Description of alarm, session restored, calibration due and identity changed events
+	Err           error         // Error of command failed events, of the calibration file, or the ErrIdentityChanged
+}
+
+/*
+Distributes the lifecycle and device events of a device to
+any number of subscribers. Publishing never blocks: events
+are dropped for subscribers whose buffer is full
+*/
+type EventBus struct {
+	subscribers map[chan Event]struct{}
+	dropped     uint64
+	mutex       sync.Mutex
+}
+
+/*
+Subscribes to the events with a buffer of the given size.
+The returned function unsubscribes and closes the channel
+*/
+func (b *EventBus) Subscribe(buffer int) (<-chan Event, func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan Event]struct{})
+	}
+	events := make(chan Event, buffer)
+	b.subscribers[events] = struct{}{}
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+
+			if _, ok := b.subscribers[events]; ok {
+				delete(b.subscribers, events)
+				close(events)
+			}
+		})
+	}
+}
+
+/*
+Sends an event to every subscriber
+*/
+func (b *EventBus) Publish(event Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			b.dropped++
+		}
+	}
+}
+
+/*
+Unsubscribes every subscriber and closes their channels
+*/
+func (b *EventBus) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for events := range b.subscribers {
+		delete(b.subscribers, events)
+		close(events)
+	}
+}
+
+/*
+Returns the number of events dropped because a subscriber
+was not keeping up
+*/
+func (b *EventBus) Dropped() uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.dropped
+}
+
+/*
+Returns the event bus of the device
+*/
+func (m *MKS937B) Events() *EventBus {
+	return &m.events
+}
+
+/*
+Publishes an event of the device. The caller must hold the
+mutex
+*/
+func (m *MKS937B) publish(event Event) {
+	event.Time = time.Now()
+	event.Device = m.metadata
+	event.Address = m.Address
+	event.Gauge, _ = m.gauges.Channel(event.Channel)
+	m.events.Publish(event)
+}
+
+// Publishes a slow command event when a transaction takes longer
+// than the threshold, so creeping bus degradation is noticed
+// early. Zero disables it, which is the default
+func WithSlowCommandThreshold(threshold time.Duration) Option {
+	return func(m *MKS937B) {
+		m.slowThreshold = threshold
+	}
+}
+
+/*
+Publishes a status changed event when the status of a channel
+differs from its previous reading
+*/
+func (m *MKS937B) trackStatus(reading PressureReading) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.statuses == nil {
+		m.statuses = make(map[int]string)
+	}
+	previous, known := m.statuses[reading.Channel]
+	m.statuses[reading.Channel] = reading.Status
+	if known && previous != reading.Status {
+		m.publish(Event{Kind: EventStatusChanged, Channel: reading.Channel, Status: reading.Status})
+	}
+}
+
+/*
+Publishes an alarm raised event
+*/
+func (m *MKS937B) raiseAlarm(channel int, message string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.publish(Event{Kind: EventAlarmRaised, Channel: channel, Message: message})
+}
@@ -0,0 +1,64 @@
+package protocol_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestEventBus(t *testing.T) {
+	var bus protocol.EventBus
+	slow, unsubscribeSlow := bus.Subscribe(1)
+	fast, unsubscribeFast := bus.Subscribe(2)
+	defer unsubscribeFast()
+
+	// The full subscriber drops the second event without blocking the other
+	bus.Publish(protocol.Event{Kind: protocol.EventConnected})
+	bus.Publish(protocol.Event{Kind: protocol.EventDisconnected})
+	if dropped := bus.Dropped(); dropped != 1 {
+		t.Errorf("expected 1 dropped event, got %d", dropped)
+	}
+	if event := <-slow; event.Kind != protocol.EventConnected {
+		t.Errorf("expected the connected event, got %v", event.Kind)
+	}
+	for _, kind := range []protocol.EventKind{protocol.EventConnected, protocol.EventDisconnected} {
+		if event := <-fast; event.Kind != kind {
+			t.Errorf("expected the %v event, got %v", kind, event.Kind)
+		}
+	}
+
+	// Unsubscribing closes the channel, and can be repeated
+	unsubscribeSlow()
+	unsubscribeSlow()
+	if _, ok := <-slow; ok {
+		t.Error("expected the channel to be closed")
+	}
+	bus.Publish(protocol.Event{Kind: protocol.EventReconnecting})
+	if event := <-fast; event.Kind != protocol.EventReconnecting || bus.Dropped() != 1 {
+		t.Errorf("expected the reconnecting event only for the subscriber, got %v", event.Kind)
+	}
+}
+
+func TestEventBusClose(t *testing.T) {
+	device := replayDevice(t)
+	events, unsubscribe := device.Events().Subscribe(4)
+	if err := device.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The disconnected event is delivered before the channel is closed
+	var kinds []protocol.EventKind
+	for event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	if len(kinds) != 1 || kinds[0] != protocol.EventDisconnected {
+		t.Errorf("expected only the disconnected event, got %v", kinds)
+	}
+
+	// Nothing is published to the closed subscriptions
+	device.Events().Publish(protocol.Event{Kind: protocol.EventConnected})
+	if dropped := device.Events().Dropped(); dropped != 0 {
+		t.Errorf("expected no dropped event, got %d", dropped)
+	}
+	unsubscribe()
+}
@@ -0,0 +1,115 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+/*
+Facility identity of the gauge connected to a channel
+*/
+type Gauge struct {
+	Name      string `json:"name" yaml:"name"`                               // Facility gauge name, e.g. VGC-BC1-01
+	Equipment string `json:"equipment,omitempty" yaml:"equipment,omitempty"` // Equipment or asset ID
+}
+
+/*
+Binds the controller slots (A1, A2, B1, B2, C1 and C2) to the
+facility gauges, so readings, events, alarms and reports use
+the naming scheme of the facility, e.g. in YAML
+
+	A1: {name: VGC-BC1-01, equipment: EQ-20431}
+	B1: {name: VGP-BC1-02}
+*/
+type GaugeMap map[string]Gauge
+
+/*
+Loads and validates a gauge map from a YAML or JSON file
+*/
+func LoadGaugeMap(path string) (GaugeMap, error) {
+	var gauges GaugeMap
+	if err := LoadYAML(path, &gauges); err != nil {
+		return nil, err
+	}
+	return gauges, gauges.Validate()
+}
+
+/*
+Verifies that every slot of the map exists and every gauge has
+a name
+*/
+func (g GaugeMap) Validate() error {
+	for _, slot := range slices.Sorted(maps.Keys(g)) {
+		if !slices.Contains(channelNames, slot) {
+			return fmt.Errorf("%w: unknown slot %q, expected A1 to C2", ErrInvalidParameter, slot)
+		}
+		if g[slot].Name == "" {
+			return fmt.Errorf("%w: slot %s has no gauge name", ErrInvalidParameter, slot)
+		}
+	}
+	return nil
+}
+
+/*
+Returns the gauge of a channel (1 to 6)
+*/
+func (g GaugeMap) Channel(channel int) (Gauge, bool) {
+	if channel < 1 || channel > len(channelNames) {
+		return Gauge{}, false
+	}
+	gauge, ok := g[channelNames[channel-1]]
+	return gauge, ok
+}
+
+// Names the gauges of the channels after the facility, see
+// GaugeMap. Invalid maps are ignored, use SetGaugeMap to get
+// the error
+func WithGaugeMap(gauges GaugeMap) Option {
+	return func(m *MKS937B) {
+		m.SetGaugeMap(gauges)
+	}
+}
+
+/*
+Sets the facility gauges of the channels. A nil map removes
+them
+*/
+func (m *MKS937B) SetGaugeMap(gauges GaugeMap) error {
+	if err := gauges.Validate(); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.gauges = maps.Clone(gauges)
+	return nil
+}
+
+/*
+Returns the facility gauges of the channels
+*/
+func (m *MKS937B) GaugeMap() GaugeMap {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return maps.Clone(m.gauges)
+}
+
+/*
+Returns the facility gauge of a channel, or a zero Gauge if
+none was mapped
+*/
+func (m *MKS937B) ChannelGauge(channel int) Gauge {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	gauge, _ := m.gauges.Channel(channel)
+	return gauge
+}
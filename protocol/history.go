@@ -0,0 +1,81 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import "time"
+
+// Number of transactions kept by default
+const DefaultTransactionHistory = 32
+
+type Transaction struct {
+	ID       string        `json:"id" yaml:"id"` // Correlation ID, as in logs and errors
+	Time     time.Time     `json:"time" yaml:"time"`
+	Command  string        `json:"command" yaml:"command"`
+	Request  string        `json:"request" yaml:"request"`
+	Response string        `json:"response" yaml:"response"` // Raw reply, possibly partial on errors
+	Duration time.Duration `json:"duration" yaml:"duration"`
+	Error    string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+/*
+Ring buffer of the last transactions. It is guarded by the
+mutex of the device
+*/
+type transactionHistory struct {
+	size         int
+	disabled     bool
+	transactions []Transaction
+	next         int
+}
+
+func (h *transactionHistory) add(command string, exchange exchange, duration time.Duration, err error) {
+	if h.disabled {
+		return
+	}
+	if h.size == 0 {
+		h.size = DefaultTransactionHistory
+	}
+	transaction := Transaction{
+		ID:       exchange.id,
+		Time:     time.Now(),
+		Command:  command,
+		Request:  exchange.request,
+		Response: exchange.response,
+		Duration: duration,
+	}
+	if err != nil {
+		transaction.Error = err.Error()
+	}
+	if len(h.transactions) < h.size {
+		h.transactions = append(h.transactions, transaction)
+		return
+	}
+	h.transactions[h.next] = transaction
+	h.next = (h.next + 1) % h.size
+}
+
+// Sets the number of transactions kept for DumpTransactions,
+// DefaultTransactionHistory by default. Zero disables the history
+func WithTransactionHistory(size int) Option {
+	return func(m *MKS937B) {
+		m.history = transactionHistory{size: size, disabled: size <= 0}
+	}
+}
+
+/*
+Returns the last transactions, oldest first, e.g. to be added
+to a support bundle when something goes wrong
+*/
+func (m *MKS937B) DumpTransactions() []Transaction {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	history := &m.history
+	transactions := make([]Transaction, 0, len(history.transactions))
+	transactions = append(transactions, history.transactions[history.next:]...)
+	return append(transactions, history.transactions[:history.next]...)
+}
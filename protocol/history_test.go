@@ -0,0 +1,85 @@
+package protocol_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Creates a device replaying request and reply pairs
+*/
+func replayDevice(t *testing.T, pairs ...string) *protocol.MKS937B {
+	t.Helper()
+	device := &protocol.MKS937B{Communication: newReplay(t, pairs...), Address: 1}
+	device.Apply(protocol.WithTransactionHistory(2))
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return device
+}
+
+/*
+Creates a transport replaying request and reply pairs
+*/
+func newReplay(t *testing.T, pairs ...string) *framelog.Replay {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer, err := framelog.NewWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, data := range pairs {
+		direction := framelog.Sent
+		if idx%2 == 1 {
+			direction = framelog.Received
+		}
+		writer.Write(framelog.Frame{Time: time.Now(), Direction: direction, Data: []byte(data)})
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := framelog.NewReplay(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return replay
+}
+
+func TestTransactionHistory(t *testing.T) {
+	device := replayDevice(t,
+		"@001PR1?;FF", "@001ACK1.00E-07;FF",
+		"@001PR2?;FF", "@001ACK2.00E-07;FF",
+		"@001SN?;FF", "@002ACK123;FF",
+	)
+	device.GetPressure(1)
+	device.GetPressure(2)
+	_, err := device.GetSerialNumber()
+	var transactionErr *protocol.ErrTransaction
+	if !errors.As(err, &transactionErr) {
+		t.Fatalf("expected a transaction error, got %v", err)
+	}
+
+	transactions := device.DumpTransactions()
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+	if transactions[0].Command != "PR2" || transactions[1].Command != "SN" {
+		t.Errorf("unexpected order %s, %s", transactions[0].Command, transactions[1].Command)
+	}
+	if transactions[1].Response != "@002ACK123;FF" || transactions[1].Error == "" {
+		t.Errorf("unexpected failed transaction %+v", transactions[1])
+	}
+	if transactions[1].ID != transactionErr.ID || transactions[0].ID == transactions[1].ID {
+		t.Errorf("unexpected correlation IDs %s, %s and %s", transactions[0].ID, transactions[1].ID, transactionErr.ID)
+	}
+
+	stats := device.Stats()
+	if stats.Commands != 3 || stats.Errors != 1 || stats.ParseFailures != 1 || stats.Recent.Errors != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
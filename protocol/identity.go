@@ -0,0 +1,95 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+type Identity struct {
+	SerialNumber string `json:"serial_number" yaml:"serial_number"`
+	Firmware     string `json:"firmware" yaml:"firmware"`
+}
+
+/*
+Reads the serial number and firmware versions of the controller
+*/
+func (m *MKS937B) ReadIdentity() (Identity, error) {
+	var identity Identity
+	var err error
+
+	if identity.SerialNumber, err = m.GetSerialNumber(); err != nil {
+		return identity, err
+	}
+	if identity.Firmware, err = m.GetFirmwareVersion(); err != nil {
+		return identity, err
+	}
+	return identity, nil
+}
+
+/*
+Sets the identity the controller is expected to have, e.g.
+restored from a previous run
+*/
+func (m *MKS937B) SetKnownIdentity(identity Identity) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.identity = identity
+}
+
+/*
+Returns the last known identity of the controller, empty if it
+was never verified
+*/
+func (m *MKS937B) KnownIdentity() Identity {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.identity
+}
+
+/*
+Reads the identity of the controller and compares it with the
+last known one. If there is none, the identity read becomes
+the known identity. If it differs, e.g. a spare controller was
+swapped in at the same address, the new identity is stored,
+what was read from the previous controller is forgotten, an
+EventIdentityChanged is published and an ErrIdentityChanged is
+returned
+*/
+func (m *MKS937B) VerifyIdentity() error {
+	identity, err := m.ReadIdentity()
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	previous := m.identity
+	m.identity = identity
+	if previous == (Identity{}) || previous == identity {
+		return nil
+	}
+	m.forgetController()
+	err = NewErrIdentityChanged(m.Address, previous, identity)
+	m.publish(Event{Kind: EventIdentityChanged, Message: err.Error(), Err: err})
+	return err
+}
+
+/*
+Forgets the device information, variant and pressure unit
+read from the controller, so they are
+read again from a controller swapped in, whose session is then
+checked by RestoreSession. The ones set with options are kept.
+The caller must hold the mutex
+*/
+func (m *MKS937B) forgetController() {
+	m.rebootSuspected = true
+	m.deviceInfo = nil
+	m.unit = ""
+	if !m.variantFixed {
+		m.variant = nil
+	}
+}
@@ -0,0 +1,45 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestVerifyIdentitySwapped(t *testing.T) {
+	pairs := []string{
+		"@001SN?;FF", "@001ACK2000;FF",
+	}
+	for slot := 1; slot <= 6; slot++ {
+		pairs = append(pairs, "@001FV"+string(rune('0'+slot))+"?;FF", "@001ACK1.0;FF")
+	}
+	device := replayDevice(t, pairs...)
+	events, unsubscribe := device.Events().Subscribe(4)
+	defer unsubscribe()
+	device.SetKnownIdentity(protocol.Identity{SerialNumber: "1000", Firmware: "old"})
+
+	var changed *protocol.ErrIdentityChanged
+	if err := device.VerifyIdentity(); !errors.As(err, &changed) {
+		t.Fatalf("expected ErrIdentityChanged, got %v", err)
+	}
+	if identity := device.KnownIdentity(); identity.SerialNumber != "2000" {
+		t.Errorf("expected the new identity to be known, got %+v", identity)
+	}
+	if !device.RebootSuspected() {
+		t.Error("expected the session of the new controller to be checked")
+	}
+	found := false
+	for len(events) > 0 {
+		event := <-events
+		if event.Kind == protocol.EventAlarmRaised {
+			t.Error("expected an identity changed event, not an alarm")
+		}
+		if event.Kind == protocol.EventIdentityChanged {
+			found = errors.As(event.Err, &changed)
+		}
+	}
+	if !found {
+		t.Error("expected an identity changed event carrying the error")
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+Software overpressure interlock that mirrors the hardware
+protection set point (PRO). While running, it reads the
+sensor channel periodically and turns the gauge OFF (CP OFF)
+once the pressure exceeds the threshold.
+
+The interlock latches after tripping and must be reset
+before the gauge can be protected again. It can only be reset
+once the pressure is below the threshold minus the hysteresis
+*/
+type Interlock struct {
+	Device        *MKS937B
+	Channel       int           // HC/CC gauge channel (1, 3 or 5)
+	SensorChannel int           // Channel whose pressure is monitored
+	Threshold     float64       // Trip pressure in the unit of the readings
+	Hysteresis    float64       // Margin below the threshold required to reset
+	Interval      time.Duration // Time between readings
+	OnTrip        func(reading PressureReading)
+	OnError       func(err error)
+
+	tripped bool
+	stop    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex
+}
+
+/*
+Creates a new interlock for the gauge on a channel that must
+be 1, 3 or 5. By default the gauge's own pressure is monitored
+every second
+*/
+func NewInterlock(device *MKS937B, channel int, threshold float64) (*Interlock, error) {
+	if err := device.checkChannel(ControlCommands, channel); err != nil {
+		return nil, err
+	}
+	return &Interlock{
+		Device:        device,
+		Channel:       channel,
+		SensorChannel: channel,
+		Threshold:     threshold,
+		Interval:      time.Second,
+	}, nil
+}
+
+/*
+Starts monitoring the sensor channel in background. It is
+stopped when the device is closed
+*/
+func (i *Interlock) Start() {
+	i.Device.attach(i)
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.stop != nil {
+		return
+	}
+	i.stop = make(chan struct{})
+	i.done = make(chan struct{})
+	go i.run(i.stop, i.done)
+}
+
+/*
+Stops monitoring and waits for the background routine to end
+*/
+func (i *Interlock) Stop() {
+	i.mutex.Lock()
+	stop, done := i.stop, i.done
+	i.stop, i.done = nil, nil
+	i.mutex.Unlock()
+
+	i.Device.detach(i)
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+/*
+Returns true if the interlock has tripped since the last reset
+*/
+func (i *Interlock) Tripped() bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return i.tripped
+}
+
+/*
+Clears the tripped state after reading the sensor channel. It
+returns ErrInterlockNotClear, keeping the interlock tripped,
+while the pressure is above the threshold minus the hysteresis.
+A channel without a valid reading, e.g. the gauge itself turned
+OFF by the trip, does not prevent the reset. The gauge is not
+turned back ON
+*/
+func (i *Interlock) Reset() error {
+	if !i.Tripped() {
+		return nil
+	}
+	reading, err := i.Device.GetPressure(i.SensorChannel)
+	if err != nil {
+		return err
+	}
+	if reading.Status == stringResponse["ATM"] || reading.Status == "OK" && reading.Value >= i.Threshold-i.Hysteresis {
+		return ErrInterlockNotClear
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.tripped = false
+	return nil
+}
+
+/*
+Checks the pressure once and trips the interlock if needed
+*/
+func (i *Interlock) Check() error {
+	if i.Tripped() {
+		return nil
+	}
+	reading, err := i.Device.GetPressure(i.SensorChannel)
+	if err != nil {
+		return err
+	}
+	overpressure := reading.Status == stringResponse["ATM"]
+	if reading.Status == "OK" && reading.Value > i.Threshold {
+		overpressure = true
+	}
+	if !overpressure {
+		return nil
+	}
+
+	if err := i.Device.SetPowerStatus(i.Channel, false); err != nil {
+		return err
+	}
+	i.mutex.Lock()
+	i.tripped = true
+	i.mutex.Unlock()
+	cause := reading.Status
+	if reading.Status == "OK" {
+		cause = fmt.Sprintf("pressure %.2E above %.2E", reading.Value, i.Threshold)
+	}
+	i.Device.raiseAlarm(i.Channel, fmt.Sprintf("interlock tripped by channel %d, %s", i.SensorChannel, cause))
+
+	if i.OnTrip != nil {
+		i.OnTrip(reading)
+	}
+	return nil
+}
+
+/*
+Monitoring loop executed until stop is closed
+*/
+func (i *Interlock) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(i.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.Check(); err != nil && i.OnError != nil {
+			i.OnError(err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestInterlock(t *testing.T) {
+	device := replayDevice(t,
+		"@001PR1?;FF", "@001ACK1.00E-03;FF",
+		"@001PR1?;FF", "@001ACK1.00E-02;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+		"@001PR1?;FF", "@001ACK4.50E-03;FF",
+		"@001PR1?;FF", "@001ACK3.00E-03;FF",
+		"@001PR1?;FF", "@001ACKATM;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+	)
+	events, unsubscribe := device.Events().Subscribe(4)
+	defer unsubscribe()
+	interlock, err := protocol.NewInterlock(device, 1, 5e-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interlock.Hysteresis = 1e-3
+	var trips []protocol.PressureReading
+	interlock.OnTrip = func(reading protocol.PressureReading) { trips = append(trips, reading) }
+
+	// Below the threshold
+	if err := interlock.Check(); err != nil || interlock.Tripped() {
+		t.Fatalf("expected no trip, got %v", err)
+	}
+
+	// Above the threshold, the gauge is turned OFF and the interlock latches
+	if err := interlock.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if !interlock.Tripped() || len(trips) != 1 || trips[0].Value != 1e-2 {
+		t.Fatalf("expected a trip at 1e-2, got %v", trips)
+	}
+	if event := <-events; event.Kind != protocol.EventAlarmRaised || event.Channel != 1 {
+		t.Errorf("unexpected event %v on channel %d", event.Kind, event.Channel)
+	}
+	if err := interlock.Check(); err != nil {
+		t.Errorf("expected a latched interlock not to read again, got %v", err)
+	}
+
+	// Below the threshold but within the hysteresis
+	if err := interlock.Reset(); !errors.Is(err, protocol.ErrInterlockNotClear) {
+		t.Errorf("expected a not clear error, got %v", err)
+	}
+	if !interlock.Tripped() {
+		t.Error("expected the interlock to stay tripped")
+	}
+	if err := interlock.Reset(); err != nil || interlock.Tripped() {
+		t.Fatalf("expected the interlock to be reset, got %v", err)
+	}
+
+	// Atmosphere trips it regardless of the threshold
+	if err := interlock.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if !interlock.Tripped() || len(trips) != 2 {
+		t.Error("expected a trip at atmosphere")
+	}
+}
+
+func TestInterlockRoutine(t *testing.T) {
+	device := replayDevice(t,
+		"@001PR3?;FF", "@001ACK1.00E-02;FF",
+		"@001CP3!OFF;FF", "@001ACKOFF;FF",
+	)
+	interlock, err := protocol.NewInterlock(device, 3, 5e-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := protocol.NewInterlock(device, 2, 5e-3); err == nil {
+		t.Error("expected an error for a channel without control")
+	}
+	interlock.Interval = time.Millisecond
+	tripped := make(chan struct{})
+	interlock.OnTrip = func(protocol.PressureReading) { close(tripped) }
+	interlock.OnError = func(err error) { t.Error(err) }
+
+	interlock.Start()
+	select {
+	case <-tripped:
+	case <-time.After(time.Second):
+		t.Fatal("expected the interlock to trip")
+	}
+	interlock.Stop()
+	if !interlock.Tripped() {
+		t.Error("expected the interlock to stay tripped after stopping")
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+/*
+Keepalive that sends a harmless query when the device has been
+idle for an interval, only to keep NAT and terminal server
+sessions open. It neither reconnects nor raises events; that is
+the role of the watchdog.
+
+Each wait is randomly shortened by up to Jitter of the
+interval, so the keepalives of a fleet started together do not
+hit the network in synchronized bursts
+*/
+type Keepalive struct {
+	Device   *MKS937B
+	Interval time.Duration // Idle time before a query is sent
+	Jitter   float64       // Fraction of the interval, 0 to 1
+	Command  string        // Query sent, the serial number by default
+	OnError  func(err error)
+
+	stop  chan struct{}
+	done  chan struct{}
+	mutex sync.Mutex
+}
+
+/*
+Creates a new keepalive querying the serial number after the
+device is idle for the interval, with 10% jitter
+*/
+func NewKeepalive(device *MKS937B, interval time.Duration) *Keepalive {
+	return &Keepalive{
+		Device:   device,
+		Interval: interval,
+		Jitter:   0.1,
+		Command:  "SN",
+	}
+}
+
+/*
+Starts the keepalive in background. It is stopped when the
+device is closed
+*/
+func (k *Keepalive) Start() {
+	k.Device.attach(k)
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.stop != nil {
+		return
+	}
+	k.stop = make(chan struct{})
+	k.done = make(chan struct{})
+	go k.run(k.stop, k.done)
+}
+
+/*
+Stops the keepalive and waits for the background routine to end
+*/
+func (k *Keepalive) Stop() {
+	k.mutex.Lock()
+	stop, done := k.stop, k.done
+	k.stop, k.done = nil, nil
+	k.mutex.Unlock()
+
+	k.Device.detach(k)
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+/*
+Returns the next wait, the interval shortened by the jitter
+*/
+func (k *Keepalive) wait() time.Duration {
+	jitter := min(max(k.Jitter, 0), 1)
+	return k.Interval - time.Duration(rand.Float64()*jitter*float64(k.Interval))
+}
+
+/*
+Sends the query if the device was idle since the given time and
+returns the time of the last activity
+*/
+func (k *Keepalive) ping(since time.Time) time.Time {
+	stats := k.Device.Stats()
+	last := stats.LastSuccess
+	if stats.LastErrorTime.After(last) {
+		last = stats.LastErrorTime
+	}
+	if last.After(since) {
+		return last
+	}
+	if _, err := k.Device.Query(k.Command); err != nil && k.OnError != nil {
+		k.OnError(err)
+	}
+	return time.Now()
+}
+
+/*
+Keepalive loop executed until stop is closed
+*/
+func (k *Keepalive) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	last := time.Now()
+	for {
+		timer := time.NewTimer(time.Until(last.Add(k.wait())))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			last = k.ping(last)
+		}
+	}
+}
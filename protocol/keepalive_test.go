@@ -0,0 +1,65 @@
+package protocol_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// Controller counting the keepalive queries it receives
+type countingController struct {
+	*fakeController
+	mutex     sync.Mutex
+	keepalive int
+}
+
+func (c *countingController) Write(message []byte) error {
+	c.mutex.Lock()
+	if string(message) == "@001SN?;FF" {
+		c.keepalive++
+	}
+	c.mutex.Unlock()
+	return c.fakeController.Write(message)
+}
+
+func (c *countingController) count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.keepalive
+}
+
+func TestKeepalive(t *testing.T) {
+	controller := &countingController{fakeController: &fakeController{
+		serial: "1000", pressures: "1.00E-07 1.00E-03 1.00E-07 1.00E-03 1.00E-07 1.00E-03",
+	}}
+	device := &protocol.MKS937B{Communication: controller, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	keepalive := protocol.NewKeepalive(device, 40*time.Millisecond)
+	keepalive.Jitter = 0
+	keepalive.Start()
+	defer keepalive.Stop()
+
+	// A device in use is not pinged
+	for deadline := time.Now().Add(150 * time.Millisecond); time.Now().Before(deadline); {
+		if _, err := device.GetPressures(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if count := controller.count(); count != 0 {
+		t.Errorf("expected no keepalive while the device is busy, got %d", count)
+	}
+
+	// An idle device is
+	deadline := time.Now().Add(time.Second)
+	for controller.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if controller.count() == 0 {
+		t.Error("expected a keepalive once the device is idle")
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Logs every transaction with the device, address, command,
+// correlation ID, duration and result fields, followed by the
+// metadata of the device other than its name, e.g. location and
+// sector. Successful transactions are logged at debug level and
+// failed ones at warning level. Nothing is logged by default
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *MKS937B) {
+		m.logger = logger
+	}
+}
+
+/*
+Logs the result of a transaction. The caller must hold the mutex
+*/
+func (m *MKS937B) logTransaction(id string, command string, duration time.Duration, nak bool, err error) {
+	if m.logger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	result := "ack"
+	switch {
+	case err != nil:
+		level, result = slog.LevelWarn, "error"
+	case nak:
+		level, result = slog.LevelWarn, "nak"
+	}
+	if !m.logger.Enabled(context.Background(), level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("device", m.metadata.Name),
+		slog.Int("address", m.Address),
+		slog.String("command", command),
+		slog.String("correlation_id", id),
+		slog.Duration("duration", duration),
+		slog.String("result", result),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	labels := m.metadata.Labels()
+	delete(labels, "name")
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		attrs = append(attrs, slog.String(key, labels[key]))
+	}
+	m.logger.LogAttrs(context.Background(), level, "mks937b transaction", attrs...)
+}
@@ -0,0 +1,27 @@
+package protocol_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestLoggerMetadata(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	device := replayDevice(t, "@001PR1?;FF", "@001ACK1.00E-07;FF")
+	device.Apply(protocol.WithLogger(logger))
+	device.SetMetadata(protocol.Metadata{Name: "sector1", Location: "Building 2", Sector: "S01", Rack: "R3"})
+
+	if _, err := device.Query("PR1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"device=sector1", "location=\"Building 2\"", "rack=R3", "sector=S01"} {
+		if !strings.Contains(output.String(), field) {
+			t.Errorf("expected %s in %q", field, output.String())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+/*
+Facility information about where a device is and who is
+responsible for it, propagated into reports, events and
+exported data
+*/
+type Metadata struct {
+	Name     string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Location string            `json:"location,omitempty" yaml:"location,omitempty"`
+	Sector   string            `json:"sector,omitempty" yaml:"sector,omitempty"`
+	Rack     string            `json:"rack,omitempty" yaml:"rack,omitempty"`
+	Contact  string            `json:"contact,omitempty" yaml:"contact,omitempty"`
+	Extra    map[string]string `json:"extra,omitempty" yaml:"extra,omitempty"`
+}
+
+/*
+Returns the non-empty fields as key/value pairs, suited to
+metrics labels and structured log fields
+*/
+func (md Metadata) Labels() map[string]string {
+	labels := make(map[string]string)
+	for key, value := range md.Extra {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	fields := map[string]string{
+		"name":     md.Name,
+		"location": md.Location,
+		"sector":   md.Sector,
+		"rack":     md.Rack,
+		"contact":  md.Contact,
+	}
+	for key, value := range fields {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+/*
+Sets the facility metadata of the device
+*/
+func (m *MKS937B) SetMetadata(metadata Metadata) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.metadata = metadata
+}
+
+/*
+Returns the facility metadata of the device
+*/
+func (m *MKS937B) Metadata() Metadata {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.metadata
+}
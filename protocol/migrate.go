@@ -0,0 +1,234 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"go.bug.st/serial"
+)
+
+// Time given to a controller to apply a new baud rate
+const baudRateSettle = 200 * time.Millisecond
+
+/*
+Serial line whose baud rate is set by the driver: a local
+serial port, the port of a RFC 2217 terminal server or any
+transport implementing these methods
+*/
+type localLine interface {
+	BaudRate() int
+	SetBaudRate(baudRate int) error
+}
+
+/*
+Local serial port as a localLine
+*/
+type serialLine struct {
+	port *unicommserial.UnicommSerial
+}
+
+/*
+Returns the baud rate of the port
+*/
+func (l serialLine) BaudRate() int {
+	return l.port.Options.BaudRate
+}
+
+/*
+Changes the baud rate of the port, keeping it open
+*/
+func (l serialLine) SetBaudRate(baudRate int) error {
+	err := l.port.Connection.SetMode(&serial.Mode{
+		BaudRate: baudRate,
+		Parity:   l.port.Options.Parity,
+		DataBits: l.port.Options.DataBits,
+		StopBits: l.port.Options.StopBits,
+	})
+	if err != nil {
+		return err
+	}
+	l.port.Options.BaudRate = baudRate
+	return nil
+}
+
+/*
+Returns the serial line of a communication, if its baud rate
+can be set: a local serial port, or a transport with BaudRate
+and SetBaudRate methods such as a RFC 2217 terminal server
+*/
+func lineOf(communication unicomm.Unicomm) (localLine, bool) {
+	if port, ok := serialPort(communication); ok {
+		return serialLine{port}, true
+	}
+	line, ok := communication.(localLine)
+	return line, ok
+}
+
+/*
+Changes the baud rate of the local serial line, keeping the
+connection open
+*/
+func setLocalBaudRate(port localLine, baudRate int) error {
+	if err := port.SetBaudRate(baudRate); err != nil {
+		return err
+	}
+	time.Sleep(baudRateSettle)
+	return nil
+}
+
+/*
+Returns true if the device answers with the expected baud rate
+*/
+func answersAt(device *MKS937B, baudRate int) bool {
+	current, err := device.GetBaudRate()
+	return err == nil && current == baudRate
+}
+
+/*
+Changes the baud rate of every controller of a serial bus and
+then of the local port, which must be connected. The bus is a
+serial port, a RFC 2217 terminal server or a transport with
+BaudRate and SetBaudRate methods.
+
+Controllers are migrated one at a time: the new rate is written
+at the current rate, and the local port switches to the new
+rate to verify that the controller answers before returning to
+the current rate for the next one. If any controller fails, the
+controllers already migrated are set back to the current rate,
+so the bus is never left with mixed rates unless the rollback
+itself fails, which is reported in the returned error
+*/
+func MigrateBaudRate(bus unicomm.Unicomm, addresses []int, baudRate int) error {
+	port, ok := lineOf(bus)
+	if !ok {
+		return ErrUnsupportedTransport
+	}
+	if !slices.Contains([]int{9600, 19200, 38400, 57600, 115200}, baudRate) {
+		return NewErrInvalidBaudRate(baudRate)
+	}
+	if !bus.IsConnected() {
+		return ErrNotConnected
+	}
+	unlock := lockBus(bus)
+	defer unlock()
+	current := port.BaudRate()
+	if current == baudRate {
+		return nil
+	}
+
+	devices := make([]*MKS937B, len(addresses))
+	for idx, address := range addresses {
+		devices[idx] = heldBusDevice(bus, address)
+		if !answersAt(devices[idx], current) {
+			return fmt.Errorf("address %03d does not answer at %d baud", address, current)
+		}
+	}
+
+	for idx, device := range devices {
+		setErr := device.SetBaudRate(baudRate)
+		if err := setLocalBaudRate(port, baudRate); err != nil {
+			return errors.Join(err, rollbackBaudRate(port, devices[:idx+1], baudRate, current))
+		}
+		if !answersAt(device, baudRate) {
+			err := fmt.Errorf("address %03d does not answer at %d baud", device.Address, baudRate)
+			err = errors.Join(err, setErr)
+			// The controller may not have changed, it is set back in any case
+			return errors.Join(err, rollbackBaudRate(port, devices[:idx+1], baudRate, current))
+		}
+		if idx < len(devices)-1 {
+			if err := setLocalBaudRate(port, current); err != nil {
+				return errors.Join(err, rollbackBaudRate(port, devices[:idx+1], baudRate, current))
+			}
+		}
+	}
+	if len(devices) == 0 {
+		return setLocalBaudRate(port, baudRate)
+	}
+	return nil
+}
+
+/*
+Finds the baud rate of the controller by probing it with the
+baud rate query at every supported rate, starting with the
+current rate of the local port, and waiting at most timeout for
+each answer. The local port is left at the working rate, which
+is returned. If the controller answers at no rate, the port is
+set back to its previous rate.
+
+The device must be connected through a serial port, a RFC 2217
+terminal server or a transport with BaudRate and SetBaudRate
+methods, with the parity and data format of the controller
+*/
+func (m *MKS937B) DetectBaudRate(timeout time.Duration) (int, error) {
+	port, ok := lineOf(m.Communication)
+	if !ok {
+		return 0, ErrUnsupportedTransport
+	}
+	if !m.IsConnected() {
+		return 0, ErrNotConnected
+	}
+	unlock := lockBus(m.Communication)
+	defer unlock()
+	probe := heldBusDevice(m.Communication, m.Address)
+	previous := port.BaudRate()
+	rates := []int{previous}
+	for _, baudRate := range []int{9600, 19200, 38400, 57600, 115200} {
+		if baudRate != previous {
+			rates = append(rates, baudRate)
+		}
+	}
+
+	restore := setReadTimeout(m.Communication, timeout)
+	defer restore()
+	for _, baudRate := range rates {
+		if baudRate != port.BaudRate() {
+			if err := setLocalBaudRate(port, baudRate); err != nil {
+				return 0, err
+			}
+		}
+		if answersAt(probe, baudRate) {
+			return baudRate, nil
+		}
+	}
+	if err := setLocalBaudRate(port, previous); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("address %03d does not answer at any baud rate", m.Address)
+}
+
+/*
+Sets migrated controllers back from baudRate to the previous
+rate and leaves the local port at the previous rate
+*/
+func rollbackBaudRate(port localLine, devices []*MKS937B, baudRate int, previous int) error {
+	if err := setLocalBaudRate(port, previous); err != nil {
+		return err
+	}
+	var errs []error
+	for _, device := range devices {
+		if answersAt(device, previous) {
+			continue
+		}
+		if err := setLocalBaudRate(port, baudRate); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		device.SetBaudRate(previous)
+		if err := setLocalBaudRate(port, previous); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if !answersAt(device, previous) {
+			errs = append(errs, fmt.Errorf("rollback of address %03d failed", device.Address))
+		}
+	}
+	return errors.Join(errs...)
+}
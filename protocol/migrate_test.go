@@ -0,0 +1,162 @@
+package protocol_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Controller answering the baud rate commands on a fakeBaudBus
+*/
+type baudController struct {
+	baudRate int
+	refuse   int // Rate the controller acknowledges but never applies
+}
+
+/*
+Serial bus whose controllers only answer when the local rate
+matches theirs
+*/
+type fakeBaudBus struct {
+	baudRate    int
+	controllers map[int]*baudController
+	reply       []byte
+}
+
+func (b *fakeBaudBus) Connect() error    { return nil }
+func (b *fakeBaudBus) Disconnect() error { return nil }
+func (b *fakeBaudBus) IsConnected() bool { return true }
+func (b *fakeBaudBus) BaudRate() int     { return b.baudRate }
+
+func (b *fakeBaudBus) SetBaudRate(baudRate int) error {
+	b.baudRate = baudRate
+	return nil
+}
+
+func (b *fakeBaudBus) Write(message []byte) error {
+	b.reply = nil
+	frame := strings.TrimSuffix(string(message), ";FF")
+	address, err := strconv.Atoi(frame[1:4])
+	if err != nil {
+		return err
+	}
+	controller, ok := b.controllers[address]
+	if !ok || controller.baudRate != b.baudRate {
+		return nil
+	}
+	switch command := frame[4:]; {
+	case command == "BR?":
+		b.reply = fmt.Appendf(nil, "@%03dACK%d;FF", address, controller.baudRate)
+	case strings.HasPrefix(command, "BR!"):
+		b.reply = fmt.Appendf(nil, "@%03dACK%s;FF", address, command[3:])
+		if rate, _ := strconv.Atoi(command[3:]); rate != controller.refuse {
+			controller.baudRate = rate
+		}
+	}
+	return nil
+}
+
+func (b *fakeBaudBus) Read(size uint) ([]byte, error) {
+	return b.ReadUntil(";FF")
+}
+
+func (b *fakeBaudBus) ReadUntil(delimiter string) ([]byte, error) {
+	if b.reply == nil {
+		return nil, errors.New("read timeout")
+	}
+	reply := b.reply
+	b.reply = nil
+	return reply, nil
+}
+
+func TestMigrateBaudRate(t *testing.T) {
+	bus := &fakeBaudBus{baudRate: 9600, controllers: map[int]*baudController{
+		1: {baudRate: 9600},
+		2: {baudRate: 9600},
+	}}
+	if err := protocol.MigrateBaudRate(bus, []int{1, 2}, 19200); err != nil {
+		t.Fatal(err)
+	}
+	if bus.baudRate != 19200 || bus.controllers[1].baudRate != 19200 || bus.controllers[2].baudRate != 19200 {
+		t.Errorf("expected the bus at 19200, got %d, %d and %d",
+			bus.baudRate, bus.controllers[1].baudRate, bus.controllers[2].baudRate)
+	}
+}
+
+func TestMigrateBaudRateNoAddresses(t *testing.T) {
+	bus := &fakeBaudBus{baudRate: 9600}
+	if err := protocol.MigrateBaudRate(bus, nil, 38400); err != nil || bus.baudRate != 38400 {
+		t.Errorf("MigrateBaudRate() = %v with the port at %d, want the port at 38400", err, bus.baudRate)
+	}
+	if err := protocol.MigrateBaudRate(bus, nil, 14400); err == nil {
+		t.Error("expected an invalid baud rate error")
+	}
+	if err := protocol.MigrateBaudRate(newReplay(t), nil, 19200); !errors.Is(err, protocol.ErrUnsupportedTransport) {
+		t.Errorf("MigrateBaudRate() = %v, want ErrUnsupportedTransport", err)
+	}
+}
+
+func TestMigrateBaudRateRollback(t *testing.T) {
+	bus := &fakeBaudBus{baudRate: 9600, controllers: map[int]*baudController{
+		1: {baudRate: 9600},
+		2: {baudRate: 9600, refuse: 19200},
+	}}
+	err := protocol.MigrateBaudRate(bus, []int{1, 2}, 19200)
+	if err == nil || !strings.Contains(err.Error(), "address 002 does not answer at 19200 baud") {
+		t.Fatalf("expected address 002 to fail the verification, got %v", err)
+	}
+	if strings.Contains(err.Error(), "rollback") {
+		t.Errorf("expected a successful rollback, got %v", err)
+	}
+	// The controller already migrated is set back with the port
+	if bus.baudRate != 9600 || bus.controllers[1].baudRate != 9600 || bus.controllers[2].baudRate != 9600 {
+		t.Errorf("expected the bus back at 9600, got %d, %d and %d",
+			bus.baudRate, bus.controllers[1].baudRate, bus.controllers[2].baudRate)
+	}
+}
+
+func TestMigrateBaudRateRollbackFailure(t *testing.T) {
+	bus := &fakeBaudBus{baudRate: 9600, controllers: map[int]*baudController{
+		1: {baudRate: 9600, refuse: 9600},
+		2: {baudRate: 9600, refuse: 19200},
+	}}
+	err := protocol.MigrateBaudRate(bus, []int{1, 2}, 19200)
+	if err == nil || !strings.Contains(err.Error(), "rollback of address 001 failed") {
+		t.Fatalf("expected the rollback of address 001 to be reported, got %v", err)
+	}
+	if bus.baudRate != 9600 || bus.controllers[1].baudRate != 19200 {
+		t.Errorf("expected the port at 9600 and address 001 left at 19200, got %d and %d",
+			bus.baudRate, bus.controllers[1].baudRate)
+	}
+}
+
+func TestDetectBaudRate(t *testing.T) {
+	bus := &fakeBaudBus{baudRate: 9600, controllers: map[int]*baudController{
+		1: {baudRate: 38400},
+	}}
+	device := &protocol.MKS937B{Communication: bus, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if baudRate, err := device.DetectBaudRate(10 * time.Millisecond); err != nil || baudRate != 38400 {
+		t.Fatalf("DetectBaudRate() = %d, %v, want 38400", baudRate, err)
+	}
+	if bus.baudRate != 38400 {
+		t.Errorf("expected the port left at 38400, got %d", bus.baudRate)
+	}
+
+	// A silent controller leaves the port at its previous rate
+	delete(bus.controllers, 1)
+	if _, err := device.DetectBaudRate(10 * time.Millisecond); err == nil {
+		t.Error("expected an error without answer")
+	}
+	if bus.baudRate != 38400 {
+		t.Errorf("expected the port back at 38400, got %d", bus.baudRate)
+	}
+}
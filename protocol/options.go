@@ -0,0 +1,65 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+// Authorizer decides if a Set is allowed. It receives the command
+// mnemonic (e.g. CSP), the channel or relay number it targets (0 for
+// system commands) and the value to be written. A non-nil error
+// rejects the command and is returned by Set.
+type Authorizer func(command string, channel int, value string) error
+
+// Option customizes a MKS937B instance when it is created
+type Option func(*MKS937B)
+
+// Applies a set of options to the instance
+func (m *MKS937B) Apply(options ...Option) {
+	for _, option := range options {
+		option(m)
+	}
+}
+
+// Makes every Set return ErrReadOnly, guaranteeing that the
+// controller configuration is never modified
+func WithReadOnly() Option {
+	return func(m *MKS937B) {
+		m.readOnly = true
+	}
+}
+
+// Calls the authorizer before every Set, allowing facilities to
+// restrict which settings can be changed
+func WithAuthorizer(authorizer Authorizer) Option {
+	return func(m *MKS937B) {
+		m.authorizer = authorizer
+	}
+}
+
+// Assigns labels to channels (1 to 6), e.g. {1: "BC1 ion pump"}
+func WithChannelLabels(labels map[int]string) Option {
+	return func(m *MKS937B) {
+		for channel, label := range labels {
+			m.SetChannelLabel(channel, label)
+		}
+	}
+}
+
+// Sets the facility metadata (location, sector, rack, contact)
+// propagated into reports, events and exported data
+func WithMetadata(metadata Metadata) Option {
+	return func(m *MKS937B) {
+		m.metadata = metadata
+	}
+}
+
+// Connects the device on the first Query or Set, and again on the
+// next one after the connection is lost, so short-lived programs
+// do not need to call Connect
+func WithLazyConnect() Option {
+	return func(m *MKS937B) {
+		m.lazyConnect = true
+	}
+}
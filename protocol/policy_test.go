@@ -0,0 +1,74 @@
+package protocol_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestReadOnly(t *testing.T) {
+	device := replayDevice(t,
+		"@001SN?;FF", "@001ACK1234;FF",
+	)
+	device.Apply(protocol.WithReadOnly())
+
+	if err := device.SetPowerStatus(1, true); !errors.Is(err, protocol.ErrReadOnly) {
+		t.Errorf("expected Set to be rejected, got %v", err)
+	}
+	if err := device.ZeroSensor(2); !errors.Is(err, protocol.ErrReadOnly) {
+		t.Errorf("expected the command to be rejected, got %v", err)
+	}
+	if err := device.SafeState(true); !errors.Is(err, protocol.ErrReadOnly) {
+		t.Errorf("expected SafeState to be rejected, got %v", err)
+	}
+	// Queries still reach the device
+	if serial, err := device.GetSerialNumber(); err != nil || serial != "1234" {
+		t.Errorf("expected serial number 1234, got %q and %v", serial, err)
+	}
+	expectReplayed(t, device)
+}
+
+func TestAuthorizer(t *testing.T) {
+	type call struct {
+		command string
+		channel int
+		value   string
+	}
+	var calls []call
+	rejected := errors.New("rejected")
+	device := replayDevice(t,
+		"@001CP3!ON;FF", "@001ACKON;FF",
+	)
+	device.Apply(protocol.WithAuthorizer(func(command string, channel int, value string) error {
+		calls = append(calls, call{command, channel, value})
+		if command == "CP" && channel == 3 {
+			return nil
+		}
+		return rejected
+	}))
+
+	if err := device.SetPowerStatus(3, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.SetPowerStatus(5, false); !errors.Is(err, rejected) {
+		t.Errorf("expected the authorizer error, got %v", err)
+	}
+	if err := device.ZeroSensor(2); !errors.Is(err, rejected) {
+		t.Errorf("expected the authorizer error, got %v", err)
+	}
+	if err := device.SafeState(true); !errors.Is(err, rejected) {
+		t.Errorf("expected the authorizer error, got %v", err)
+	}
+	expected := []call{
+		{"CP", 3, "ON"},
+		{"CP", 5, "OFF"},
+		{"VAC", 2, ""},
+		{"CTL", 1, "OFF"},
+	}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+	expectReplayed(t, device)
+}
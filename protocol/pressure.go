@@ -0,0 +1,64 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import "fmt"
+
+/*
+Pressure value with its unit (Torr, MBAR, PASCAL or Micron)
+*/
+type Pressure struct {
+	Value float64 `json:"value" yaml:"value"`
+	Unit  string  `json:"unit" yaml:"unit"`
+}
+
+func (p Pressure) String() string {
+	return fmt.Sprintf("%.2E %s", p.Value, p.Unit)
+}
+
+/*
+Converts the pressure to another unit
+*/
+func (p Pressure) In(unit string) (Pressure, error) {
+	from, ok := torrConversion[p.Unit]
+	if !ok {
+		return Pressure{}, NewErrInvalidUnit(p.Unit)
+	}
+	to, ok := torrConversion[unit]
+	if !ok {
+		return Pressure{}, NewErrInvalidUnit(unit)
+	}
+	return Pressure{Value: p.Value / from * to, Unit: unit}, nil
+}
+
+/*
+Converts a pressure to the unit configured on the device
+*/
+func (m *MKS937B) inDeviceUnit(p Pressure) (Pressure, error) {
+	unit, err := m.GetPressureUnit()
+	if err != nil {
+		return Pressure{}, err
+	}
+	return p.In(unit)
+}
+
+/*
+Returns an error if a pressure is outside a range given in Torr.
+The limits are converted to the unit of the pressure before the
+comparison, so errors report them in the unit of the value
+*/
+func checkPressureRange(p Pressure, low float64, high float64) error {
+	factor, ok := torrConversion[p.Unit]
+	if !ok {
+		return NewErrInvalidUnit(p.Unit)
+	}
+	low, high = low*factor, high*factor
+	if p.Value < low || high < p.Value {
+		return NewErrInvalidPressureRange(low, high, p.Value, p.Unit)
+	}
+	return nil
+}
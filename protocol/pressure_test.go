@@ -0,0 +1,124 @@
+package protocol_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestPressureIn(t *testing.T) {
+	pascal, err := protocol.Pressure{Value: 1e-2, Unit: "Torr"}.In("PASCAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(pascal.Value-1.33322) > 1e-9 || pascal.Unit != "PASCAL" {
+		t.Errorf("unexpected conversion %v", pascal)
+	}
+	micron, _ := pascal.In("Micron")
+	if math.Abs(micron.Value-10) > 1e-9 {
+		t.Errorf("unexpected conversion %v", micron)
+	}
+	if _, err := (protocol.Pressure{Value: 1, Unit: "psi"}).In("Torr"); err == nil {
+		t.Error("expected an invalid unit error")
+	}
+}
+
+func TestSetTargetPressureRange(t *testing.T) {
+	device := replayDevice(t)
+
+	// 2 Pa is 1.5e-2 Torr, above the 1e-2 Torr limit
+	err := device.SetTargetPressure(1, protocol.Pressure{Value: 2, Unit: "PASCAL"})
+	var invalid *protocol.ErrInvalidPressureRange
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected a range error, got %v", err)
+	}
+	if invalid.Unit != "PASCAL" || math.Abs(invalid.MaxValue-1.33322) > 1e-9 {
+		t.Errorf("expected the limits in Pascal, got %v", invalid)
+	}
+
+	var pro *protocol.ErrInvalidPRO
+	err = device.SetProtectionPressure(1, protocol.Pressure{Value: 5, Unit: "Micron"})
+	if errors.As(err, &pro) {
+		t.Errorf("5 Micron is a valid protection target, got %v", err)
+	}
+	err = device.SetProtectionPressure(1, protocol.Pressure{Value: 50, Unit: "Micron"})
+	if !errors.As(err, &pro) {
+		t.Errorf("expected a protection target error, got %v", err)
+	}
+}
+
+func TestReadingWithAccuracy(t *testing.T) {
+	reading := protocol.PressureReading{Channel: 3, Value: 1e-9, Status: "OK"}.WithAccuracy("PR", "Torr")
+	if !reading.OutOfRange || math.Abs(reading.Uncertainty-5e-11) > 1e-20 {
+		t.Errorf("expected an out of range Pirani reading, got %+v", reading)
+	}
+	reading = protocol.PressureReading{Value: 1e-7, Status: "OK"}.WithAccuracy("HC", "PASCAL")
+	if reading.OutOfRange || reading.Sensor != "HC" {
+		t.Errorf("expected an in range HC reading, got %+v", reading)
+	}
+}
+
+func TestFormatPretty(t *testing.T) {
+	cases := []struct {
+		value  float64
+		digits int
+		want   string
+	}{
+		{5.23e-7, 2, "5.2×10⁻⁷"},
+		{1.5e3, 3, "1.50×10³"},
+		{2.5, 2, "2.5"},
+		{9.96e-11, 2, "1.0×10⁻¹⁰"},
+		{0, 2, "0.0"},
+	}
+	for _, c := range cases {
+		if got := protocol.FormatPretty(c.value, c.digits); got != c.want {
+			t.Errorf("FormatPretty(%g, %d) = %s, want %s", c.value, c.digits, got, c.want)
+		}
+	}
+	if got := (protocol.Pressure{Value: 5.2e-7, Unit: "Torr"}).Pretty(2); got != "5.2×10⁻⁷ Torr" {
+		t.Errorf("unexpected pretty pressure %s", got)
+	}
+}
+
+func TestSIUnits(t *testing.T) {
+	device := replayDevice(t,
+		"@001PR1?;FF", "@001ACK1.00E-02;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001PR2?;FF", "@001ACKLO<E-03;FF",
+	)
+	device.Apply(protocol.WithSIUnits())
+
+	reading, err := device.GetPressure(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(reading.Value-1.33322) > 1e-9 {
+		t.Errorf("expected 1.33322 Pa, got %v", reading.Value)
+	}
+	if reading, err := device.GetPressure(2); err != nil || reading.Status == "OK" {
+		t.Errorf("expected a reading below range, got %+v (%v)", reading, err)
+	}
+}
+
+func TestGaugeMap(t *testing.T) {
+	if err := (protocol.GaugeMap{"D1": {Name: "VGC-01"}}).Validate(); err == nil {
+		t.Error("expected an error for an unknown slot")
+	}
+	if err := (protocol.GaugeMap{"A1": {}}).Validate(); err == nil {
+		t.Error("expected an error for a gauge without name")
+	}
+
+	device := replayDevice(t, "@001PR3?;FF", "@001ACK1.00E-07;FF")
+	if err := device.SetGaugeMap(protocol.GaugeMap{"B1": {Name: "VGC-BC1-01", Equipment: "EQ-20431"}}); err != nil {
+		t.Fatal(err)
+	}
+	reading, err := device.GetPressure(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reading.Gauge != "VGC-BC1-01" || reading.Equipment != "EQ-20431" {
+		t.Errorf("expected the facility gauge on the reading, got %+v", reading)
+	}
+}
@@ -1,23 +1,68 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 23rd, 2025
-Last update: September 23rd, 2025
+Last update: October 17th, 2026
 */
 
 package protocol
 
 import (
+	"context"
 	"fmt"
-	"regexp"
+	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/devicehub-go/mks-937b/core"
 	"github.com/devicehub-go/unicomm"
 )
 
 type MKS937B struct {
 	Communication unicomm.Unicomm
 	Address int
+	DegasGuard bool // Verifies pressure and filament before degas
 
+	readOnly bool
+	authorizer Authorizer
+	emergency *EmergencyStop
+	labels map[int]string
+	gauges GaugeMap
+	calibrations Calibrations
+	calibrationFile string
+	calibrationDue map[int]bool // Channels whose due event was published
+	calibrationErr error // Error loading the file of WithCalibrationFile
+	calibrationErrReported bool
+	metadata Metadata
+	identity Identity
+	logger *slog.Logger
+	tracer Tracer
+	adaptive *AdaptiveTimeout
+	slowThreshold time.Duration
+	numberFormat *NumberFormat
+	variant *Variant
+	variantFixed bool // Set with WithVariant, kept when the controller changes
+	siUnits bool
+	unit string // Device unit cached with WithSIUnits
+	dialTimeout time.Duration
+	lazyConnect bool
+	reconnect *ReconnectPolicy
+	addressProbe *addressProbe
+	addressProbed bool
+	session []VolatileSetting
+	rebootSuspected bool // The controller stopped answering, was reconnected or swapped since the session was checked
+	deviceInfo *DeviceInfo
+	closed atomic.Bool
+	routines map[routine]struct{}
+	routinesMutex sync.Mutex
+	events EventBus
+	history transactionHistory
+	statuses map[int]string
+	stats statsCollector
+	busHeld bool // The lock of the communication is held by the caller, see lockBus
+	urgent int // Urgent transactions waiting for or holding the mutex
+	urgentDone chan struct{} // Closed when no urgent transaction is left
+	urgentMutex sync.Mutex
 	mutex sync.Mutex
 }
 
@@ -25,13 +70,41 @@ type MKS937B struct {
 Establishes a connection with the device
 */
 func (m *MKS937B) Connect() error {
+	return m.ConnectContext(context.Background())
+}
+
+/*
+Establishes a connection with the device, giving up when the
+context is done, e.g. when a terminal server is unreachable.
+TCP connections are dialed with the context, other transports
+are abandoned when it is done
+*/
+func (m *MKS937B) ConnectContext(ctx context.Context) error {
 	if m.Address < 1 || 254 < m.Address {
 		return NewErrInvalidAddress(m.Address)
 	}
+	if m.dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.dialTimeout)
+		defer cancel()
+	}
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	return m.Communication.Connect()
+	m.closed.Store(false)
+	if m.stats.attempt() {
+		m.rebootSuspected = true
+		m.publish(Event{Kind: EventReconnecting})
+	}
+	m.unit = ""
+	m.addressProbed = false
+	err := m.dial(ctx)
+	m.stats.connected(err)
+	if err != nil {
+		return err
+	}
+	m.publish(Event{Kind: EventConnected})
+	return nil
 }
 
 /*
@@ -41,7 +114,36 @@ func (m *MKS937B) Disconnect() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	return m.Communication.Disconnect()
+	return m.disconnect()
+}
+
+/*
+Closes the connection with the device. The caller must hold
+the mutex
+*/
+func (m *MKS937B) disconnect() error {
+	if err := m.Communication.Disconnect(); err != nil {
+		return err
+	}
+	m.stats.disconnected()
+	m.publish(Event{Kind: EventDisconnected})
+	return nil
+}
+
+/*
+Connects the device for a command with WithLazyConnect, with
+the reconnect policy if one is set. A concurrent command may
+have connected it in the meantime
+*/
+func (m *MKS937B) connectOnDemand() error {
+	connect := m.Connect
+	if m.reconnect != nil {
+		connect = func() error { return m.Reconnect(context.Background()) }
+	}
+	if err := connect(); err != nil && !m.IsConnected() {
+		return err
+	}
+	return nil
 }
 
 /*
@@ -54,69 +156,306 @@ func (m *MKS937B) IsConnected() bool {
 	return m.Communication.IsConnected()
 }
 
+/*
+Returns an error unless the device can send queries, connecting
+it with WithLazyConnect
+*/
+func (m *MKS937B) ready() error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	if !m.IsConnected() {
+		if !m.lazyConnect {
+			return ErrNotConnected
+		}
+		return m.connectOnDemand()
+	}
+	return nil
+}
+
 /*
 Queries a value from the device
 */
 func (m *MKS937B) Query(command string) (string, error) {
-	if !m.IsConnected() {
-		return "", ErrNotConnected
+	if err := m.ready(); err != nil {
+		return "", err
 	}
 
-	m.mutex.Lock()
+	m.lock()
 	defer m.mutex.Unlock()
 
-	addressStr := fmt.Sprintf("%03d", m.Address)
-	message := fmt.Sprintf("@%s%s?;FF", addressStr, command)
-	m.Communication.Write([]byte(message))
+	return m.query(command)
+}
 
-	response, err := m.Communication.ReadUntil(";FF")
-	if err != nil {
-		return "", err
+/*
+Queries several values in a row without other commands in
+between, e.g. from concurrent pollers, so they describe the
+same instant. Stops at the first error
+*/
+func (m *MKS937B) queryBatch(commands ...string) ([]string, error) {
+	if err := m.ready(); err != nil {
+		return nil, err
 	}
-	responseStr := string(response)
-	regex := regexp.MustCompile(`@([0-9]+)(?:ACK|NAK)(.*?);FF`)
-	matches := regex.FindStringSubmatch(responseStr)
 
-	if len(matches) < 3 {
-		return "", NewErrUnexpectedReply(message, responseStr)
+	m.lock()
+	defer m.mutex.Unlock()
+
+	values := make([]string, len(commands))
+	for idx, command := range commands {
+		value, err := m.query(command)
+		if err != nil {
+			return nil, err
+		}
+		values[idx] = value
 	}
-	if matches[1] != addressStr {
-		return "", NewErrUnexpectedAddress(addressStr, matches[1])
+	return values, nil
+}
+
+/*
+Queries a value from the device. The caller must hold the
+mutex
+*/
+func (m *MKS937B) query(command string) (string, error) {
+	start := time.Now()
+	message := core.QueryFrame(m.Address, command)
+	exchange, err := m.transaction(command, message)
+	if err := m.observe(command, exchange, time.Since(start), err); err != nil {
+		if exchange.response == "" {
+			err = m.probeAddress(err)
+		}
+		return "", err
 	}
-	return matches[2], nil
+	return exchange.payload, nil
 }
 
 /*
 Sets a value to the device
 */
 func (m *MKS937B) Set(command string, parameter string) error {
-	if !m.IsConnected() {
-		return fmt.Errorf("no MKS937B is connected")
+	if err := m.authorize(command, parameter); err != nil {
+		return err
 	}
+	return m.write(command, parameter, parameter)
+}
 
+/*
+Runs a command without parameter answered with OK, e.g. the
+zero of a sensor
+*/
+func (m *MKS937B) execute(command string) error {
+	if err := m.authorize(command, ""); err != nil {
+		return err
+	}
+	return m.write(command, "", "OK")
+}
+
+/*
+Returns an error unless the policies of the device allow a
+setting
+*/
+func (m *MKS937B) authorize(command string, parameter string) error {
+	if err := m.checkPolicy(command, parameter); err != nil {
+		return err
+	}
 	m.mutex.Lock()
+	emergency := m.emergency
+	m.mutex.Unlock()
+	if emergency != nil {
+		return emergency.check(command, parameter)
+	}
+	return nil
+}
+
+/*
+Returns an error if the device is closed, read-only or the
+authorizer rejects a setting. Unlike authorize, it does not
+wait for the mutex
+*/
+func (m *MKS937B) checkPolicy(command string, parameter string) error {
+	if m.closed.Load() {
+		return ErrClosed
+	}
+	if m.readOnly {
+		return ErrReadOnly
+	}
+	if m.authorizer != nil {
+		mnemonic, channel := core.SplitCommand(command)
+		if err := m.authorizer(mnemonic, channel, parameter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Sends a setting and verifies that the device answers with the
+expected reply
+*/
+func (m *MKS937B) write(command string, parameter string, expected string) error {
+	if !m.IsConnected() {
+		if !m.lazyConnect {
+			return fmt.Errorf("no MKS937B is connected")
+		}
+		if err := m.connectOnDemand(); err != nil {
+			return err
+		}
+	}
+
+	m.lock()
 	defer m.mutex.Unlock()
 
-	addressStr := fmt.Sprintf("%03d", m.Address)
-	message := fmt.Sprintf("@%s%s!%s;FF", addressStr, command, parameter)
-	m.Communication.Write([]byte(message))
+	return m.set(command, parameter, expected)
+}
 
-	response, err := m.Communication.ReadUntil(";FF")
-	if err != nil {
-		return err
+/*
+Sends a setting and verifies that the device answers with the
+expected reply. The caller must hold the mutex
+*/
+func (m *MKS937B) set(command string, parameter string, expected string) error {
+	start := time.Now()
+	message := core.SetFrame(m.Address, command, parameter)
+	exchange, err := m.transaction(command, message)
+	if err == nil && exchange.payload != expected {
+		err = NewErrUnexpectedParamater(expected, exchange.payload)
+	}
+	err = m.observe(command, exchange, time.Since(start), err)
+	if err != nil && exchange.response == "" {
+		err = m.probeAddress(err)
+	}
+	return err
+}
+
+/*
+Takes the mutex for a transaction, letting urgent transactions
+waiting for it go first
+*/
+func (m *MKS937B) lock() {
+	for {
+		m.urgentMutex.Lock()
+		urgent := m.urgentDone
+		m.urgentMutex.Unlock()
+		if urgent != nil {
+			<-urgent
+			continue
+		}
+
+		m.mutex.Lock()
+		m.urgentMutex.Lock()
+		urgent = m.urgentDone
+		m.urgentMutex.Unlock()
+		if urgent == nil {
+			return
+		}
+		m.mutex.Unlock()
+	}
+}
+
+/*
+Takes the mutex ahead of the transactions waiting for it, e.g.
+to reach the safe state while pollers are queued. A transaction
+in progress is completed first, since interrupting it would
+leave the bus with a partial frame. The returned function
+releases the mutex
+*/
+func (m *MKS937B) lockUrgent() func() {
+	m.urgentMutex.Lock()
+	if m.urgent == 0 {
+		m.urgentDone = make(chan struct{})
 	}
-	responseStr := string(response)
-	regex := regexp.MustCompile(`@([0-9]+)(?:ACK|NAK)(.*?);FF`)
-	matches := regex.FindStringSubmatch(responseStr)
+	m.urgent++
+	m.urgentMutex.Unlock()
+	m.mutex.Lock()
 
-	if len(matches) < 3 {
-		return NewErrUnexpectedReply(message, responseStr)
+	return func() {
+		m.mutex.Unlock()
+		m.urgentMutex.Lock()
+		defer m.urgentMutex.Unlock()
+
+		m.urgent--
+		if m.urgent == 0 {
+			close(m.urgentDone)
+			m.urgentDone = nil
+		}
 	}
-	if matches[1] != addressStr {
-		return NewErrUnexpectedAddress(addressStr, matches[1])
+}
+
+/*
+Records the result of a transaction in the stats, the log and
+the history, and traces and publishes failures and slow
+transactions. Errors are returned wrapped with the command and
+correlation ID. The caller must hold the mutex
+*/
+func (m *MKS937B) observe(command string, exchange exchange, duration time.Duration, err error) error {
+	m.stats.record(command, duration, exchange.nak, err)
+	m.logTransaction(exchange.id, command, duration, exchange.nak, err)
+	m.history.add(command, exchange, duration, err)
+	if m.slowThreshold > 0 && duration > m.slowThreshold {
+		m.publish(Event{Kind: EventSlowCommand, Command: command, CorrelationID: exchange.id, Duration: duration})
 	}
-	if matches[2] != parameter {
-		return NewErrUnexpectedParamater(parameter, matches[2])
+	if err == nil {
+		return nil
 	}
-	return nil
-}
\ No newline at end of file
+	if isTimeout(err) {
+		m.rebootSuspected = true
+	}
+	if m.tracer != nil {
+		m.tracer.OnError(m.traceContext(exchange.id, command), err, duration)
+	}
+	err = NewErrTransaction(exchange.id, command, err)
+	m.publish(Event{Kind: EventCommandFailed, Command: command, CorrelationID: exchange.id, Err: err})
+	return err
+}
+
+// Sequence of the correlation IDs, shared by all devices so IDs
+// are unique within the process
+var correlationSequence atomic.Uint64
+
+/*
+Returns a new correlation ID for a transaction
+*/
+func nextCorrelationID() string {
+	return fmt.Sprintf("%08x", correlationSequence.Add(1))
+}
+
+/*
+Request and reply of a transaction
+*/
+type exchange struct {
+	id       string // Correlation ID
+	request  string
+	response string
+	payload  string // Parameter or value of the reply
+	nak      bool
+}
+
+/*
+Sends a message and parses the reply of the device. The
+caller must hold the mutex
+*/
+func (m *MKS937B) transaction(command string, message string) (exchange, error) {
+	result := exchange{id: nextCorrelationID(), request: message}
+	start := time.Now()
+	if m.tracer != nil {
+		m.tracer.OnSend(m.traceContext(result.id, command), message)
+	}
+
+	unlock := m.lockBus()
+	restore := m.adaptTimeout(command)
+	response, err := core.Exchange(m.Communication, message)
+	restore()
+	unlock()
+	result.response = response
+	if err != nil {
+		return result, err
+	}
+	if m.tracer != nil {
+		m.tracer.OnReceive(m.traceContext(result.id, command), result.response, time.Since(start))
+	}
+	reply, err := core.ParseReply(message, m.Address, result.response)
+	if err != nil {
+		return result, err
+	}
+	result.payload = reply.Payload
+	result.nak = reply.Nak
+	return result, nil
+}
@@ -1,7 +1,7 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 24rd, 2025
-Last update: September 24rd, 2025
+Last update: October 17th, 2026
 */
 
 package protocol
@@ -13,8 +13,15 @@ import (
 )
 
 type PressureReading struct {
-	Value  float64
-	Status string
+	Channel     int     `json:"channel" yaml:"channel"`
+	Label       string  `json:"label" yaml:"label"`
+	Gauge       string  `json:"gauge,omitempty" yaml:"gauge,omitempty"`         // Facility gauge name, see GaugeMap
+	Equipment   string  `json:"equipment,omitempty" yaml:"equipment,omitempty"` // Equipment ID of the gauge
+	Value       float64 `json:"value" yaml:"value"`
+	Status      string  `json:"status" yaml:"status"`
+	Sensor      string  `json:"sensor,omitempty" yaml:"sensor,omitempty"`           // Sensor type, see WithAccuracy
+	Uncertainty float64 `json:"uncertainty,omitempty" yaml:"uncertainty,omitempty"` // Absolute, in the unit of the value
+	OutOfRange  bool    `json:"out_of_range,omitempty" yaml:"out_of_range,omitempty"`
 }
 
 var stringResponse = map[string]string{
@@ -31,6 +38,14 @@ var stringResponse = map[string]string{
 	"COMB_DISABLED": "Combination disabled",
 }
 
+// Factors to convert a pressure in Torr to each device unit
+var torrConversion = map[string]float64{
+	"Torr":   1,
+	"MBAR":   1.33322,
+	"PASCAL": 133.322,
+	"Micron": 1000,
+}
+
 /*
 Parses a pressure reading from device
 */
@@ -59,19 +74,29 @@ Reads the pressure of a target channel
 func (m *MKS937B) GetPressure(channel int) (PressureReading, error) {
 	var pressure PressureReading
 
-	if 1 < channel || channel > 6 {
-		return pressure, NewErrInvalidChannel(1, 6, channel)
+	if err := m.checkChannel(ReadingCommands, channel); err != nil {
+		return pressure, err
 	}
 	command := fmt.Sprintf("PR%d", channel)
 	response, err := m.Query(command)
 	if err != nil {
 		return pressure, err
 	}
-	return parsePressure(response)
+	pressure, err = parsePressure(response)
+	if err == nil {
+		pressure, err = m.normalizeReading(pressure)
+	}
+	pressure.Channel = channel
+	m.nameReading(&pressure)
+	if err == nil {
+		m.trackStatus(pressure)
+	}
+	return pressure, err
 }
 
 /*
-Reads the pressures from all device channels
+Reads the pressures from all device channels. Until a variant
+is set or detected, it is detected from the number of pressures
 */
 func (m *MKS937B) GetPressures() ([]PressureReading, error) {
 	response, err := m.Query("PRZ")
@@ -79,14 +104,38 @@ func (m *MKS937B) GetPressures() ([]PressureReading, error) {
 		return nil, err
 	}
 
-	pressures := make([]PressureReading, 6)
-	for idx, value := range strings.Split(response, " ") {
+	values := strings.Split(response, " ")
+	m.mutex.Lock()
+	if m.variant == nil {
+		if variant, ok := variantsByFields[len(values)]; ok {
+			m.variant = &variant
+		}
+	}
+	fields := SixChannelVariant.PressureFields()
+	if m.variant != nil {
+		fields = m.variant.PressureFields()
+	}
+	m.mutex.Unlock()
+	if len(values) != fields {
+		return nil, NewErrUnexpectedReply("PRZ", response)
+	}
+
+	pressures := make([]PressureReading, fields)
+	for idx, value := range values {
 		pressure, err := parsePressure(value)
 		if err != nil {
 			return nil, err
 		}
+		if pressure, err = m.normalizeReading(pressure); err != nil {
+			return nil, err
+		}
+		pressure.Channel = idx + 1
+		m.nameReading(&pressure)
 		pressures[idx] = pressure
 	}
+	for _, pressure := range pressures {
+		m.trackStatus(pressure)
+	}
 
 	return pressures, nil
 }
@@ -98,13 +147,63 @@ sensor
 func (m *MKS937B) GetPressureCombination(channel int) (PressureReading, error) {
 	var pressure PressureReading
 
-	if channel < 1 || 2 < channel {
-		return pressure, NewErrInvalidChannel(1, 2, channel)
+	if err := m.checkChannel(CombinationCommands, channel); err != nil {
+		return pressure, err
 	}
 	command := fmt.Sprintf("PC%d", channel)
 	response, err := m.Query(command)
 	if err != nil {
 		return pressure, err
 	}
-	return parsePressure(response)
+	if pressure, err = parsePressure(response); err != nil {
+		return pressure, err
+	}
+	return m.normalizeReading(pressure)
+}
+
+/*
+Assigns a human readable label to a channel (1 to 6), e.g.
+"BC1 ion pump section". Labels are attached to the readings
+of the channel. An empty label removes it
+*/
+func (m *MKS937B) SetChannelLabel(channel int, label string) error {
+	if err := m.checkChannel(ReadingCommands, channel); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.labels == nil {
+		m.labels = make(map[int]string)
+	}
+	if label == "" {
+		delete(m.labels, channel)
+	} else {
+		m.labels[channel] = label
+	}
+	return nil
+}
+
+/*
+Attaches the label and facility gauge of its channel to a
+reading
+*/
+func (m *MKS937B) nameReading(reading *PressureReading) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	reading.Label = m.labels[reading.Channel]
+	gauge, _ := m.gauges.Channel(reading.Channel)
+	reading.Gauge, reading.Equipment = gauge.Name, gauge.Equipment
+}
+
+/*
+Returns the label of a channel, or an empty string if none
+was assigned
+*/
+func (m *MKS937B) ChannelLabel(channel int) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.labels[channel]
 }
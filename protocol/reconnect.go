@@ -0,0 +1,99 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+/*
+Reconnection strategy with exponential backoff: the delay after
+the n-th failed attempt is InitialDelay multiplied n-1 times by
+Multiplier, bounded by MaxDelay, and randomly varied by up to
+Jitter of itself so devices sharing a network do not retry in
+lockstep. MaxAttempts of 0 retries until the context is done
+*/
+type ReconnectPolicy struct {
+	InitialDelay time.Duration `json:"initial_delay" yaml:"initial_delay"`
+	MaxDelay     time.Duration `json:"max_delay" yaml:"max_delay"`
+	Multiplier   float64       `json:"multiplier" yaml:"multiplier"`
+	Jitter       float64       `json:"jitter" yaml:"jitter"` // Fraction of the delay, 0 to 1
+	MaxAttempts  int           `json:"max_attempts" yaml:"max_attempts"`
+}
+
+// Five attempts, waiting 100 ms doubled after every failure up to
+// 5 s, with 20% jitter
+var DefaultReconnectPolicy = ReconnectPolicy{
+	InitialDelay: 100 * time.Millisecond,
+	MaxDelay:     5 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+	MaxAttempts:  5,
+}
+
+/*
+Returns the delay after the given failed attempt, from 1
+*/
+func (p ReconnectPolicy) Delay(attempt int) time.Duration {
+	delay := float64(p.InitialDelay) * math.Pow(max(p.Multiplier, 1), float64(max(attempt-1, 0)))
+	if p.MaxDelay > 0 {
+		delay = min(delay, float64(p.MaxDelay))
+	}
+	jitter := min(max(p.Jitter, 0), 1)
+	delay *= 1 + jitter*(2*rand.Float64()-1)
+	return time.Duration(delay)
+}
+
+// Reconnects with the given policy, see Reconnect. With
+// WithLazyConnect, commands then connect with it instead of a
+// single attempt
+func WithReconnectPolicy(policy ReconnectPolicy) Option {
+	return func(m *MKS937B) {
+		m.reconnect = &policy
+	}
+}
+
+/*
+Connects the device, retrying failed attempts with the
+reconnect policy of the device (DefaultReconnectPolicy unless
+set with WithReconnectPolicy) until it succeeds, the attempts
+are exhausted or the context is done. Returns the error of the
+last attempt, or the context error
+*/
+func (m *MKS937B) Reconnect(ctx context.Context) error {
+	m.mutex.Lock()
+	policy := DefaultReconnectPolicy
+	if m.reconnect != nil {
+		policy = *m.reconnect
+	}
+	m.mutex.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		if m.IsConnected() {
+			return nil
+		}
+		err := m.ConnectContext(ctx)
+		var invalid *ErrInvalidAddress
+		if err == nil || errors.As(err, &invalid) || ctx.Err() != nil {
+			return err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return err
+		}
+		timer := time.NewTimer(policy.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Relay of the interlock matrix with the channel driving it and
+its switching configuration
+*/
+type RelayMapping struct {
+	Relay      int     `json:"relay" yaml:"relay"`
+	Module     string  `json:"module" yaml:"module"`
+	Channel    int     `json:"channel" yaml:"channel"`
+	Name       string  `json:"name" yaml:"name"` // Slot of the channel, e.g. A1
+	Label      string  `json:"label,omitempty" yaml:"label,omitempty"`
+	Gauge      string  `json:"gauge,omitempty" yaml:"gauge,omitempty"` // Facility gauge name, see GaugeMap
+	Equipment  string  `json:"equipment,omitempty" yaml:"equipment,omitempty"`
+	SetPoint   float64 `json:"set_point" yaml:"set_point"`
+	Hysteresis float64 `json:"hysteresis" yaml:"hysteresis"`
+	Direction  string  `json:"direction" yaml:"direction"` // ABOVE or BELOW
+	Enable     string  `json:"enable" yaml:"enable"`       // SET, ENABLE or CLEAR
+}
+
+/*
+Which relay is driven by which channel at which set point,
+hysteresis and direction, meant for safety reviews. Values are
+in the unit of the matrix
+*/
+type InterlockMatrix struct {
+	Time         time.Time      `json:"time" yaml:"time"`
+	SerialNumber string         `json:"serial_number" yaml:"serial_number"`
+	Unit         string         `json:"unit" yaml:"unit"`
+	Relays       []RelayMapping `json:"relays" yaml:"relays"`
+}
+
+/*
+Reads the interlock matrix of the relays of the installed
+modules, with the values in the unit of the readings
+*/
+func (m *MKS937B) InterlockMatrix() (InterlockMatrix, error) {
+	snapshot, err := m.Snapshot()
+	if err != nil {
+		return InterlockMatrix{}, err
+	}
+	matrix := InterlockMatrixOf(snapshot)
+	if matrix.Unit, err = m.readingUnit(); err != nil {
+		return matrix, err
+	}
+	return matrix, nil
+}
+
+/*
+Builds the interlock matrix of a snapshot, e.g. one loaded with
+ParseSnapshot for an offline review. Relays outside 1 to 12, or
+driven by a channel outside A1 to C2, are left out, since the
+snapshot may come from a file or list the communication option
+after the sensor modules
+*/
+func InterlockMatrixOf(snapshot Snapshot) InterlockMatrix {
+	matrix := InterlockMatrix{
+		Time:         snapshot.Time,
+		SerialNumber: snapshot.System.SerialNumber,
+		Unit:         snapshot.System.Unit,
+	}
+	for _, relay := range snapshot.Relays {
+		slot := (relay.Relay - 1) / 4
+		if relay.Relay < 1 || 12 < relay.Relay || slot >= len(snapshot.System.Modules) {
+			continue
+		}
+		module := snapshot.System.Modules[slot]
+		channel := RelayChannel(relay.Relay, module)
+		if channel < 1 || len(channelNames) < channel {
+			continue
+		}
+		mapping := RelayMapping{
+			Relay:      relay.Relay,
+			Module:     module,
+			Channel:    channel,
+			Name:       channelNames[channel-1],
+			SetPoint:   relay.SetPoint,
+			Hysteresis: relay.Hysteresis,
+			Direction:  relay.Direction,
+			Enable:     relay.Enable,
+		}
+		if channel <= len(snapshot.Pressures) {
+			reading := snapshot.Pressures[channel-1]
+			mapping.Label, mapping.Gauge, mapping.Equipment = reading.Label, reading.Gauge, reading.Equipment
+		}
+		matrix.Relays = append(matrix.Relays, mapping)
+	}
+	return matrix
+}
+
+/*
+Returns the matrix as indented JSON
+*/
+func (im InterlockMatrix) JSON() ([]byte, error) {
+	return json.MarshalIndent(im, "", "  ")
+}
+
+/*
+Writes the matrix as CSV, one row per relay. Numbers are
+written in scientific notation with a dot as decimal separator
+*/
+func (im InterlockMatrix) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"relay", "module", "channel", "name", "gauge", "equipment", "label", "set_point", "hysteresis", "unit", "direction", "enable",
+	})
+	for _, relay := range im.Relays {
+		writer.Write([]string{
+			strconv.Itoa(relay.Relay),
+			relay.Module,
+			strconv.Itoa(relay.Channel),
+			relay.Name,
+			relay.Gauge,
+			relay.Equipment,
+			relay.Label,
+			strconv.FormatFloat(relay.SetPoint, 'E', 3, 64),
+			strconv.FormatFloat(relay.Hysteresis, 'E', 3, 64),
+			im.Unit,
+			relay.Direction,
+			relay.Enable,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+/*
+Returns the matrix as a Markdown table
+*/
+func (im InterlockMatrix) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Interlock Matrix\n\n")
+	if im.SerialNumber != "" {
+		fmt.Fprintf(&b, "- Serial number: %s\n", im.SerialNumber)
+	}
+	fmt.Fprintf(&b, "- Read at: %s\n\n", im.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Relay | Module | Channel | Gauge | Equipment | Label | Set point (%s) | Hysteresis (%s) | Direction | Enable |\n", im.Unit, im.Unit)
+	fmt.Fprintf(&b, "|---|---|---|---|---|---|---|---|---|---|\n")
+	for _, relay := range im.Relays {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			relay.Relay, relay.Module, relay.Name, relay.Gauge, relay.Equipment, relay.Label,
+			FormatPretty(relay.SetPoint, reportDigits), FormatPretty(relay.Hysteresis, reportDigits),
+			relay.Direction, relay.Enable)
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package protocol_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestInterlockMatrix(t *testing.T) {
+	snapshot := protocol.Snapshot{
+		System: protocol.SystemInfo{Unit: "TORR", SerialNumber: "1234", Modules: []string{"CC", "PR", "NC"}},
+		Pressures: []protocol.PressureReading{
+			{Channel: 1, Label: "Chamber", Gauge: "VGC-01", Equipment: "EQ-1"}, {Channel: 2}, {Channel: 3, Label: "Foreline"}, {Channel: 4},
+		},
+		Relays: []protocol.RelayConfig{
+			{Relay: 2, SetPoint: 1e-6, Hysteresis: 2e-6, Direction: "BELOW", Enable: "ENABLE"},
+			{Relay: 7, SetPoint: 1e-1, Hysteresis: 2e-1, Direction: "ABOVE", Enable: "CLEAR"},
+		},
+	}
+
+	matrix := protocol.InterlockMatrixOf(snapshot)
+	if len(matrix.Relays) != 2 {
+		t.Fatalf("expected 2 relays, got %d", len(matrix.Relays))
+	}
+	if relay := matrix.Relays[0]; relay.Channel != 1 || relay.Name != "A1" || relay.Label != "Chamber" {
+		t.Errorf("unexpected mapping of relay 2: %+v", relay)
+	}
+	if relay := matrix.Relays[1]; relay.Channel != 4 || relay.Name != "B2" || relay.Module != "PR" {
+		t.Errorf("unexpected mapping of relay 7: %+v", relay)
+	}
+
+	var csv bytes.Buffer
+	if err := matrix.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(csv.String(), "7,PR,4,B2,,,,1.000E-01,2.000E-01,TORR,ABOVE,CLEAR") {
+		t.Errorf("unexpected CSV:\n%s", csv.String())
+	}
+	if markdown := matrix.Markdown(); !strings.Contains(markdown, "| 2 | CC | A1 | VGC-01 | EQ-1 | Chamber |") {
+		t.Errorf("unexpected Markdown:\n%s", markdown)
+	}
+}
+
+func TestInterlockMatrixOutOfRange(t *testing.T) {
+	// The communication option follows the sensor modules in MT
+	snapshot := protocol.Snapshot{
+		System: protocol.SystemInfo{Modules: []string{"CC", "PR", "NC", "PF"}},
+		Relays: []protocol.RelayConfig{{Relay: 0}, {Relay: 1}, {Relay: 13}, {Relay: 16}},
+	}
+	matrix := protocol.InterlockMatrixOf(snapshot)
+	if len(matrix.Relays) != 1 || matrix.Relays[0].Relay != 1 {
+		t.Errorf("expected only relay 1, got %+v", matrix.Relays)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type RelayConfig struct {
+	Version    int     `json:"version,omitempty" yaml:"version,omitempty"` // ConfigVersion
+	Relay      int     `json:"relay" yaml:"relay"`
+	SetPoint   float64 `json:"set_point" yaml:"set_point"`
+	Hysteresis float64 `json:"hysteresis" yaml:"hysteresis"`
+	Direction  string  `json:"direction" yaml:"direction"` // ABOVE or BELOW
+	Enable     string  `json:"enable" yaml:"enable"`       // SET, ENABLE or CLEAR
+}
+
+/*
+Returns the channel (1 to 6) whose sensor drives a relay (1 to 12).
+
+Each slot has four relays: they are all assigned to the single
+sensor of HC and CC modules, and split in pairs between the two
+channels of the other modules
+*/
+func RelayChannel(relay int, module string) int {
+	slot := (relay - 1) / 4
+	if module == "HC" || module == "CC" {
+		return 2*slot + 1
+	}
+	return 2*slot + 1 + ((relay-1)%4)/2
+}
+
+/*
+Gets the set point value of a relay (1 to 12)
+*/
+func (m *MKS937B) GetRelaySetPoint(relay int) (float64, error) {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return 0, err
+	}
+	response, err := m.Query(fmt.Sprintf("SP%d", relay))
+	if err != nil {
+		return 0, err
+	}
+	return m.parseSetPoint(response)
+}
+
+/*
+Sets the set point value of a relay (1 to 12). If 0 is used,
+the set point will be set as its low limit value
+*/
+func (m *MKS937B) SetRelaySetPoint(relay int, target float64) error {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return err
+	}
+	value, err := m.toDevice(target)
+	if err != nil {
+		return err
+	}
+	return m.setNumber(fmt.Sprintf("SP%d", relay), value)
+}
+
+/*
+Gets the hysteresis value of a relay (1 to 12)
+*/
+func (m *MKS937B) GetRelayHysteresis(relay int) (float64, error) {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return 0, err
+	}
+	response, err := m.Query(fmt.Sprintf("SH%d", relay))
+	if err != nil {
+		return 0, err
+	}
+	return m.parseSetPoint(response)
+}
+
+/*
+Sets the hysteresis value of a relay (1 to 12)
+*/
+func (m *MKS937B) SetRelayHysteresis(relay int, target float64) error {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return err
+	}
+	value, err := m.toDevice(target)
+	if err != nil {
+		return err
+	}
+	return m.setNumber(fmt.Sprintf("SH%d", relay), value)
+}
+
+/*
+Gets the direction of a relay (1 to 12), either ABOVE or BELOW
+*/
+func (m *MKS937B) GetRelayDirection(relay int) (string, error) {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return "", err
+	}
+	return m.Query(fmt.Sprintf("SD%d", relay))
+}
+
+/*
+Sets the direction of a relay (1 to 12).
+
+Valid directions are ABOVE and BELOW. Relays driven by CC
+and HC sensors are fixed to BELOW
+*/
+func (m *MKS937B) SetRelayDirection(relay int, direction string) error {
+	valid := []string{"ABOVE", "BELOW"}
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return err
+	}
+	if !slices.Contains(valid, direction) {
+		return NewErrInvalidRelayDirection(direction)
+	}
+	return m.Set(fmt.Sprintf("SD%d", relay), direction)
+}
+
+/*
+Gets the enable status of a relay (1 to 12)
+*/
+func (m *MKS937B) GetRelayEnable(relay int) (string, error) {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return "", err
+	}
+	return m.Query(fmt.Sprintf("EN%d", relay))
+}
+
+/*
+Sets the enable status of a relay (1 to 12)
+
+Valid values are:
+  - SET: forces the relay activation regardless of pressure
+  - ENABLE: relay follows the pressure, set point and direction
+  - CLEAR: disables the relay
+*/
+func (m *MKS937B) SetRelayEnable(relay int, enable string) error {
+	valid := []string{"SET", "ENABLE", "CLEAR"}
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return err
+	}
+	if !slices.Contains(valid, enable) {
+		return NewErrInvalidRelayEnable(enable)
+	}
+	return m.Set(fmt.Sprintf("EN%d", relay), enable)
+}
+
+/*
+Returns true if a relay (1 to 12) is currently activated
+*/
+func (m *MKS937B) GetRelayStatus(relay int) (bool, error) {
+	if err := m.checkChannel(RelayCommands, relay); err != nil {
+		return false, err
+	}
+	response, err := m.Query(fmt.Sprintf("SS%d", relay))
+	if err != nil {
+		return false, err
+	}
+	return response == "SET", nil
+}
+
+/*
+Returns the activation status of all 12 relays with a single
+query, indexed from relay 1
+*/
+func (m *MKS937B) GetRelayStatuses() ([]bool, error) {
+	response, err := m.Query("SSA")
+	if err != nil {
+		return nil, err
+	}
+	if len(response) != 12 || strings.Trim(response, "01") != "" {
+		return nil, NewErrUnexpectedReply("SSA", response)
+	}
+	statuses := make([]bool, 12)
+	for idx, status := range response {
+		statuses[idx] = status == '1'
+	}
+	return statuses, nil
+}
+
+/*
+Gets the set point, hysteresis, direction and enable status
+of a relay (1 to 12)
+*/
+func (m *MKS937B) GetRelayConfig(relay int) (RelayConfig, error) {
+	config := RelayConfig{Version: ConfigVersion, Relay: relay}
+	var err error
+
+	if config.SetPoint, err = m.GetRelaySetPoint(relay); err != nil {
+		return config, err
+	}
+	if config.Hysteresis, err = m.GetRelayHysteresis(relay); err != nil {
+		return config, err
+	}
+	if config.Direction, err = m.GetRelayDirection(relay); err != nil {
+		return config, err
+	}
+	if config.Enable, err = m.GetRelayEnable(relay); err != nil {
+		return config, err
+	}
+	return config, nil
+}
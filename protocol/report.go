@@ -0,0 +1,219 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Significant digits of the values in reports
+const reportDigits = 3
+
+type gaugeControl struct {
+	Channel    int
+	Module     string
+	Power      bool
+	Mode       string
+	Source     string
+	SetPoint   float64
+	Hysteresis float64
+	Protection float64
+}
+
+/*
+Produces a human readable Markdown summary of the controller
+with its identity, modules, communication settings, channel
+configuration, relay set points, current readings and
+calibration due dates, meant for commissioning documentation
+*/
+func (m *MKS937B) Report() (string, error) {
+	snapshot, err := m.Snapshot()
+	if err != nil {
+		return "", err
+	}
+	sensors, err := m.GetSensorTypes()
+	if err != nil {
+		return "", err
+	}
+	ionGauges, err := m.getIonGaugeChannels()
+	if err != nil {
+		return "", err
+	}
+
+	var controls []gaugeControl
+	for _, channel := range []int{1, 3, 5} {
+		module, ok := ionGauges[channel]
+		if !ok {
+			continue
+		}
+		control := gaugeControl{Channel: channel, Module: module}
+		steps := []func() error{
+			func() error { control.Power, err = m.GetPowerStatus(channel); return err },
+			func() error { control.Mode, err = m.GetControlMode(channel); return err },
+			func() error { control.Source, err = m.GetControlChannelStatus(channel); return err },
+			func() error { control.SetPoint, err = m.GetTarget(channel); return err },
+			func() error { control.Hysteresis, err = m.GetHysterisesTarget(channel); return err },
+			func() error { control.Protection, err = m.GetProtectionTarget(channel); return err },
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return "", err
+			}
+		}
+		controls = append(controls, control)
+	}
+	unit, err := m.readingUnit()
+	if err != nil {
+		return "", err
+	}
+	return renderReport(m.Metadata(), snapshot, unit, sensors, controls, m.Calibrations()), nil
+}
+
+/*
+Renders the report as Markdown, with the values in the unit of
+the readings
+*/
+func renderReport(metadata Metadata, snapshot Snapshot, unit string, sensors []string, controls []gaugeControl, calibrations Calibrations) string {
+	var b strings.Builder
+	system := snapshot.System
+
+	if metadata.Name != "" {
+		fmt.Fprintf(&b, "# MKS 937B Report: %s\n\n", metadata.Name)
+	} else {
+		fmt.Fprintf(&b, "# MKS 937B Report\n\n")
+	}
+	fmt.Fprintf(&b, "Generated at %s\n\n", snapshot.Time.Format(time.RFC3339))
+
+	location := []struct{ name, value string }{
+		{"Location", metadata.Location},
+		{"Sector", metadata.Sector},
+		{"Rack", metadata.Rack},
+		{"Contact", metadata.Contact},
+	}
+	for _, key := range slices.Sorted(maps.Keys(metadata.Extra)) {
+		location = append(location, struct{ name, value string }{key, metadata.Extra[key]})
+	}
+	header := false
+	for _, field := range location {
+		if field.value == "" {
+			continue
+		}
+		if !header {
+			fmt.Fprintf(&b, "## Location\n\n")
+			header = true
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", field.name, field.value)
+	}
+	if header {
+		fmt.Fprintf(&b, "\n")
+	}
+
+	fmt.Fprintf(&b, "## Identity\n\n")
+	fmt.Fprintf(&b, "- Firmware: %s\n", system.Firmware)
+	fmt.Fprintf(&b, "- Serial number: %s\n", system.SerialNumber)
+	// The fourth module type is the communication option
+	for slot, module := range system.Modules[:min(len(system.Modules), 3)] {
+		fmt.Fprintf(&b, "- Slot %c: %s\n", 'A'+slot, module)
+	}
+	if option := NewCapabilities(system.Modules).Option; option != "" {
+		fmt.Fprintf(&b, "- Communication option: %s\n", option)
+	}
+
+	fmt.Fprintf(&b, "\n## Communication\n\n")
+	fmt.Fprintf(&b, "- Address: %03d\n", system.Address)
+	fmt.Fprintf(&b, "- Baud rate: %d\n", system.BaudRate)
+	fmt.Fprintf(&b, "- Parity: %s\n", system.Parity)
+	fmt.Fprintf(&b, "- Delay time: %d ms\n", system.DelayTime)
+	fmt.Fprintf(&b, "- Pressure unit: %s\n", system.Unit)
+
+	gauges := slices.ContainsFunc(snapshot.Pressures, func(reading PressureReading) bool { return reading.Gauge != "" })
+	fmt.Fprintf(&b, "\n## Channels\n\n")
+	if gauges {
+		fmt.Fprintf(&b, "| Channel | Gauge | Equipment | Label | Sensor | Pressure (%s) | Status |\n", unit)
+		fmt.Fprintf(&b, "|---|---|---|---|---|---|---|\n")
+	} else {
+		fmt.Fprintf(&b, "| Channel | Label | Sensor | Pressure (%s) | Status |\n", unit)
+		fmt.Fprintf(&b, "|---|---|---|---|---|\n")
+	}
+	for idx, reading := range snapshot.Pressures {
+		sensor := ""
+		if idx < len(sensors) {
+			sensor = sensors[idx]
+		}
+		pressure := "-"
+		if reading.Status == "OK" {
+			pressure = FormatPretty(reading.Value, reportDigits)
+		}
+		name := channelNames[idx]
+		if gauges {
+			name = fmt.Sprintf("%s | %s | %s", name, reading.Gauge, reading.Equipment)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			name, reading.Label, sensor, pressure, reading.Status)
+	}
+
+	if len(controls) > 0 {
+		fmt.Fprintf(&b, "\n## Gauge Control\n\n")
+		fmt.Fprintf(&b, "| Channel | Module | Power | Mode | Control channel | Set point | Hysteresis | Protection |\n")
+		fmt.Fprintf(&b, "|---|---|---|---|---|---|---|---|\n")
+		for _, control := range controls {
+			protection := "Disabled"
+			if control.Protection > 0 {
+				protection = FormatPretty(control.Protection, reportDigits)
+			}
+			power := "OFF"
+			if control.Power {
+				power = "ON"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				channelNames[control.Channel-1], control.Module, power, control.Mode, control.Source,
+				FormatPretty(control.SetPoint, reportDigits), FormatPretty(control.Hysteresis, reportDigits), protection)
+		}
+	}
+
+	if len(snapshot.Relays) > 0 {
+		fmt.Fprintf(&b, "\n## Relays\n\n")
+		fmt.Fprintf(&b, "| Relay | Channel | Set point (%s) | Hysteresis (%s) | Direction | Enable |\n", unit, unit)
+		fmt.Fprintf(&b, "|---|---|---|---|---|---|\n")
+		for _, relay := range snapshot.Relays {
+			slot := (relay.Relay - 1) / 4
+			channel := RelayChannel(relay.Relay, system.Modules[slot])
+			name := channelNames[channel-1]
+			if channel <= len(snapshot.Pressures) && snapshot.Pressures[channel-1].Gauge != "" {
+				name = fmt.Sprintf("%s (%s)", name, snapshot.Pressures[channel-1].Gauge)
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n",
+				relay.Relay, name, FormatPretty(relay.SetPoint, reportDigits),
+				FormatPretty(relay.Hysteresis, reportDigits), relay.Direction, relay.Enable)
+		}
+	}
+
+	if len(calibrations) > 0 {
+		fmt.Fprintf(&b, "\n## Calibration\n\n")
+		fmt.Fprintf(&b, "| Channel | Last calibrated | Method | Due |\n")
+		fmt.Fprintf(&b, "|---|---|---|---|\n")
+		for _, channel := range slices.Sorted(maps.Keys(calibrations)) {
+			record := calibrations[channel]
+			last, due := "-", "-"
+			if !record.LastCalibrated.IsZero() {
+				last = record.LastCalibrated.Format(time.DateOnly)
+			}
+			if !record.Due().IsZero() {
+				due = record.Due().Format(time.DateOnly)
+			}
+			if record.IsDue(snapshot.Time) {
+				due = "**OVERDUE** since " + due
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", channelNames[channel-1], last, record.Method, due)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,389 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Telnet commands and options used by RFC 2217
+const (
+	telnetSE       = 240
+	telnetSB       = 250
+	telnetWILL     = 251
+	telnetWONT     = 252
+	telnetDO       = 253
+	telnetDONT     = 254
+	telnetIAC      = 255
+	telnetBinary   = 0
+	telnetSGA      = 3
+	telnetComPort  = 44
+	comSetBaudRate = 1
+	comSetDataSize = 2
+	comSetParity   = 3
+	comSetStopSize = 4
+)
+
+// Parity values of the SET-PARITY command
+var rfc2217Parity = map[string]byte{"NONE": 1, "ODD": 2, "EVEN": 3}
+
+/*
+Address of the terminal server and format of its serial port
+*/
+type RFC2217Options struct {
+	Host         string
+	Port         uint
+	BaudRate     int    // Default is 9600
+	Parity       string // NONE, EVEN or ODD, default is NONE
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+/*
+Communication through a terminal server supporting the telnet
+COM port control option (RFC 2217), which sets the baud rate
+and format of its serial port to match the controller, 8 data
+bits and 1 stop bit, on every connection. It can be used as
+the communication of a device like the unicomm ones
+*/
+type RFC2217 struct {
+	Options    RFC2217Options
+	Connection net.Conn
+
+	pending   []byte        // Data received and not read yet
+	state     int           // Telnet decoding state of the received bytes
+	command   byte          // Telnet command being decoded
+	announced map[byte]bool // Options offered or requested by the client
+	mutex     sync.Mutex
+}
+
+// Telnet decoding states
+const (
+	telnetData = iota
+	telnetCommand
+	telnetOption
+	telnetSubnegotiation
+	telnetSubnegotiationIAC
+)
+
+/*
+Creates a new RFC 2217 communication, with the default read
+and write timeouts of unicomm
+*/
+func NewRFC2217(options RFC2217Options) *RFC2217 {
+	if options.BaudRate == 0 {
+		options.BaudRate = 9600
+	}
+	if options.Parity == "" {
+		options.Parity = "NONE"
+	}
+	if options.ReadTimeout == 0 {
+		options.ReadTimeout = 100 * time.Millisecond
+	}
+	if options.WriteTimeout == 0 {
+		options.WriteTimeout = 100 * time.Millisecond
+	}
+	return &RFC2217{Options: options}
+}
+
+/*
+Returns true if the connection is established
+*/
+func (r *RFC2217) IsConnected() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.Connection != nil
+}
+
+/*
+Connects to the terminal server, negotiates the binary mode
+and the COM port control option, and configures the serial
+port
+*/
+func (r *RFC2217) Connect() error {
+	if _, ok := rfc2217Parity[r.Options.Parity]; !ok {
+		return NewErrInvalidParity(r.Options.Parity)
+	}
+	if r.IsConnected() {
+		return errors.New("there is a connection already established")
+	}
+	address := net.JoinHostPort(r.Options.Host, strconv.FormatUint(uint64(r.Options.Port), 10))
+	connection, err := net.DialTimeout("tcp", address, defaultDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Connection = connection
+	r.pending, r.state = nil, telnetData
+	r.announced = map[byte]bool{}
+	var negotiation []byte
+	for _, option := range []byte{telnetBinary, telnetSGA, telnetComPort} {
+		negotiation = append(negotiation, telnetIAC, telnetWILL, option)
+		r.announced[option] = true
+	}
+	for _, option := range []byte{telnetBinary, telnetSGA} {
+		negotiation = append(negotiation, telnetIAC, telnetDO, option)
+	}
+	err = r.send(append(negotiation, r.configuration()...))
+	if err != nil {
+		r.Connection.Close()
+		r.Connection = nil
+	}
+	return err
+}
+
+/*
+Returns the subnegotiations setting the serial port of the
+terminal server
+*/
+func (r *RFC2217) configuration() []byte {
+	baudRate := binary.BigEndian.AppendUint32(nil, uint32(r.Options.BaudRate))
+	var message []byte
+	for _, command := range [][]byte{
+		append([]byte{comSetBaudRate}, baudRate...),
+		{comSetDataSize, 8},
+		{comSetParity, rfc2217Parity[r.Options.Parity]},
+		{comSetStopSize, 1},
+	} {
+		message = append(message, telnetIAC, telnetSB, telnetComPort)
+		message = append(message, escapeTelnet(command)...)
+		message = append(message, telnetIAC, telnetSE)
+	}
+	return message
+}
+
+/*
+Returns the baud rate of the serial port of the terminal server
+*/
+func (r *RFC2217) BaudRate() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.Options.BaudRate
+}
+
+/*
+Changes the baud rate of the serial port of the terminal
+server, keeping the connection open
+*/
+func (r *RFC2217) SetBaudRate(baudRate int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Options.BaudRate = baudRate
+	if r.Connection == nil {
+		return nil
+	}
+	return r.send(r.configuration())
+}
+
+/*
+Changes the parity (NONE, EVEN or ODD) of the serial port of
+the terminal server, keeping the connection open
+*/
+func (r *RFC2217) SetParity(parity string) error {
+	if _, ok := rfc2217Parity[parity]; !ok {
+		return NewErrInvalidParity(parity)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Options.Parity = parity
+	if r.Connection == nil {
+		return nil
+	}
+	return r.send(r.configuration())
+}
+
+/*
+Closes the connection with the terminal server
+*/
+func (r *RFC2217) Disconnect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Connection == nil {
+		return errors.New("there is no connection established")
+	}
+	err := r.Connection.Close()
+	r.Connection = nil
+	return err
+}
+
+/*
+Writes raw bytes to the connection. The caller must hold the
+mutex
+*/
+func (r *RFC2217) send(message []byte) error {
+	r.Connection.SetWriteDeadline(time.Now().Add(r.Options.WriteTimeout))
+	_, err := r.Connection.Write(message)
+	return err
+}
+
+/*
+Returns data with the IAC bytes doubled, as telnet requires
+*/
+func escapeTelnet(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{telnetIAC}, []byte{telnetIAC, telnetIAC})
+}
+
+/*
+Writes data to the serial port of the terminal server
+*/
+func (r *RFC2217) Write(message []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Connection == nil {
+		return errors.New("there is no connection established")
+	}
+	return r.send(escapeTelnet(message))
+}
+
+/*
+Receives bytes until the deadline, keeping the data and
+answering the telnet negotiation. The caller must hold the
+mutex
+*/
+func (r *RFC2217) receive(deadline time.Time) error {
+	buffer := make([]byte, 256)
+	r.Connection.SetReadDeadline(deadline)
+	n, err := r.Connection.Read(buffer)
+	var replies []byte
+	for _, b := range buffer[:n] {
+		switch r.state {
+		case telnetData:
+			if b == telnetIAC {
+				r.state = telnetCommand
+			} else {
+				r.pending = append(r.pending, b)
+			}
+		case telnetCommand:
+			switch b {
+			case telnetIAC:
+				r.pending = append(r.pending, b)
+				r.state = telnetData
+			case telnetSB:
+				r.state = telnetSubnegotiation
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				r.command = b
+				r.state = telnetOption
+			default:
+				r.state = telnetData
+			}
+		case telnetOption:
+			replies = append(replies, r.negotiate(r.command, b)...)
+			r.state = telnetData
+		case telnetSubnegotiation:
+			// Replies and notifications of the server are ignored
+			if b == telnetIAC {
+				r.state = telnetSubnegotiationIAC
+			}
+		case telnetSubnegotiationIAC:
+			r.state = telnetSubnegotiation
+			if b == telnetSE {
+				r.state = telnetData
+			}
+		}
+	}
+	if len(replies) > 0 {
+		if sendErr := r.send(replies); err == nil {
+			err = sendErr
+		}
+	}
+	return err
+}
+
+/*
+Returns the answer to a negotiation of the server. Binary mode,
+suppress go ahead and COM port control are accepted, other
+options are refused, and requests already announced are not
+answered again
+*/
+func (r *RFC2217) negotiate(command byte, option byte) []byte {
+	supported := option == telnetBinary || option == telnetSGA || option == telnetComPort
+	switch command {
+	case telnetDO:
+		if supported && r.announced[option] {
+			return nil
+		}
+		if supported {
+			r.announced[option] = true
+			return []byte{telnetIAC, telnetWILL, option}
+		}
+		return []byte{telnetIAC, telnetWONT, option}
+	case telnetWILL:
+		if option == telnetBinary || option == telnetSGA {
+			return nil
+		}
+		return []byte{telnetIAC, telnetDONT, option}
+	}
+	return nil
+}
+
+/*
+Reads at most a number of bytes received from the serial port
+*/
+func (r *RFC2217) Read(n uint) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Connection == nil {
+		return nil, errors.New("there is no connection established")
+	}
+	deadline := time.Now().Add(r.Options.ReadTimeout)
+	for len(r.pending) == 0 {
+		if err := r.receive(deadline); err != nil {
+			return nil, err
+		}
+	}
+	size := min(int(n), len(r.pending))
+	data := bytes.Clone(r.pending[:size])
+	r.pending = r.pending[size:]
+	return data, nil
+}
+
+/*
+Reads data received from the serial port until a target
+delimiter is found
+*/
+func (r *RFC2217) ReadUntil(delimiter string) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Connection == nil {
+		return nil, errors.New("there is no connection established")
+	}
+	deadline := time.Now().Add(r.Options.ReadTimeout)
+	for {
+		if idx := bytes.Index(r.pending, []byte(delimiter)); idx >= 0 {
+			size := idx + len(delimiter)
+			data := bytes.Clone(r.pending[:size])
+			r.pending = r.pending[size:]
+			return data, nil
+		}
+		if err := r.receive(deadline); err != nil {
+			data := r.pending
+			r.pending = nil
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return data, errors.New("read until timeout")
+			}
+			return data, err
+		}
+	}
+}
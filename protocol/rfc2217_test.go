@@ -0,0 +1,95 @@
+package protocol_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// Terminal server side of a connection
+type telnetPeer struct {
+	conn     net.Conn
+	received []byte
+}
+
+// Reads until want is received, keeping what follows it
+func (p *telnetPeer) expect(t *testing.T, want []byte) {
+	t.Helper()
+	p.conn.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, 256)
+	for {
+		if idx := bytes.Index(p.received, want); idx >= 0 {
+			p.received = p.received[idx+len(want):]
+			return
+		}
+		n, err := p.conn.Read(buffer)
+		if err != nil {
+			t.Errorf("expected % X, received % X: %v", want, p.received, err)
+			return
+		}
+		p.received = append(p.received, buffer[:n]...)
+	}
+}
+
+func TestRFC2217(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback listener:", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		peer := &telnetPeer{conn: conn}
+		// 19200 baud, 8 data bits, even parity and 1 stop bit
+		peer.expect(t, []byte{255, 251, 44})
+		peer.expect(t, []byte{255, 250, 44, 1, 0, 0, 0x4B, 0, 255, 240})
+		peer.expect(t, []byte{255, 250, 44, 3, 3, 255, 240, 255, 250, 44, 4, 1, 255, 240})
+
+		peer.expect(t, []byte("@001PR1?;FF"))
+		// Negotiation and notifications of the server are interleaved with the reply
+		conn.Write([]byte("@001ACK1.0"))
+		conn.Write([]byte{255, 253, 44, 255, 251, 1, 255, 250, 44, 106, 0, 255, 240})
+		conn.Write([]byte("0E-07;FF"))
+		peer.expect(t, []byte{255, 254, 1})
+
+		// The baud rate 511 has an IAC byte, which must be doubled
+		peer.expect(t, []byte{255, 250, 44, 1, 0, 0, 1, 255, 255, 255, 240})
+	}()
+
+	rfc2217 := protocol.NewRFC2217(protocol.RFC2217Options{
+		Host: "127.0.0.1", Port: uint(port), BaudRate: 19200, Parity: "EVEN", ReadTimeout: time.Second,
+	})
+	device := &protocol.MKS937B{Communication: rfc2217, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		device.Disconnect()
+		<-done
+	}()
+
+	pressure, err := device.GetPressure(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pressure.Value != 1e-7 {
+		t.Errorf("expected 1e-7, got %g", pressure.Value)
+	}
+	if err := rfc2217.SetBaudRate(511); err != nil {
+		t.Fatal(err)
+	}
+	if err := rfc2217.SetParity("MARK"); err == nil {
+		t.Error("expected an error for an invalid parity")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+/*
+Transmit-enable of a RS-485 converter driven by the RTS line,
+for bare converters without automatic direction control. RTS
+is asserted while a frame is sent and released once its last
+byte has left the port, so the reply of the controller is not
+missed
+*/
+type RS485Direction struct {
+	ActiveLow bool          `yaml:"active_low,omitempty"` // Transmits with RTS released, for inverting converters
+	PreHold   time.Duration `yaml:"pre_hold,omitempty"`   // Time between enabling the transmitter and the first byte
+	PostHold  time.Duration `yaml:"post_hold,omitempty"`  // Time between the last byte and releasing the transmitter
+}
+
+/*
+Serial port toggling RTS around every frame written, see
+RS485Direction
+*/
+type RS485Port struct {
+	*unicommserial.UnicommSerial
+	Direction RS485Direction
+}
+
+/*
+Wraps a serial port so it drives the direction of a RS-485
+converter, e.g.
+
+	device.Communication = protocol.NewRS485Port(port, protocol.RS485Direction{PostHold: time.Millisecond})
+*/
+func NewRS485Port(port *unicommserial.UnicommSerial, direction RS485Direction) *RS485Port {
+	return &RS485Port{UnicommSerial: port, Direction: direction}
+}
+
+// Drives the direction of bare RS-485 converters with RTS, see
+// RS485Direction. It only applies to serial communications and
+// must come after the communication is set
+func WithRS485Direction(direction RS485Direction) Option {
+	return func(m *MKS937B) {
+		if port, ok := m.Communication.(*unicommserial.UnicommSerial); ok {
+			m.Communication = NewRS485Port(port, direction)
+		}
+	}
+}
+
+/*
+Enables or disables the transmitter
+*/
+func (p *RS485Port) transmit(enable bool) error {
+	return p.Connection.SetRTS(enable != p.Direction.ActiveLow)
+}
+
+/*
+Connects the port with the transmitter disabled
+*/
+func (p *RS485Port) Connect() error {
+	if err := p.UnicommSerial.Connect(); err != nil {
+		return err
+	}
+	return p.transmit(false)
+}
+
+/*
+Writes a frame with the transmitter enabled, waiting for its
+last byte to be sent before disabling it
+*/
+func (p *RS485Port) Write(message []byte) error {
+	if p.Connection == nil {
+		return p.UnicommSerial.Write(message)
+	}
+	if err := p.transmit(true); err != nil {
+		return err
+	}
+	time.Sleep(p.Direction.PreHold)
+	err := p.UnicommSerial.Write(message)
+	if err == nil {
+		err = p.Connection.Drain()
+	}
+	time.Sleep(p.Direction.PostHold)
+	if releaseErr := p.transmit(false); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+/*
+Returns the serial port of a communication, unwrapping the
+RS-485 direction control
+*/
+func serialPort(communication unicomm.Unicomm) (*unicommserial.UnicommSerial, bool) {
+	switch c := communication.(type) {
+	case *unicommserial.UnicommSerial:
+		return c, true
+	case *RS485Port:
+		return c.UnicommSerial, true
+	}
+	return nil, false
+}
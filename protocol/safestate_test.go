@@ -0,0 +1,88 @@
+package protocol_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// Replay holding the write of a frame until released
+type gatedReplay struct {
+	*framelog.Replay
+	frame   string
+	release chan struct{}
+	writing chan struct{}
+}
+
+func (g *gatedReplay) Write(message []byte) error {
+	if string(message) == g.frame {
+		close(g.writing)
+		<-g.release
+	}
+	return g.Replay.Write(message)
+}
+
+func TestSafeStateFallback(t *testing.T) {
+	device := replayDevice(t,
+		"@001MT?;FF", "garbled;FF",
+		"@001DG1!OFF;FF", "@001ACKOFF;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+		"@001DG3!OFF;FF", "@001ACKOFF;FF",
+		"@001CP3!OFF;FF", "@001ACKOFF;FF",
+		"@001DG5!OFF;FF", "@001ACKOFF;FF",
+		"@001CP5!OFF;FF", "@001ACKOFF;FF",
+	)
+	// The modules cannot be read, so every control channel is turned OFF
+	if err := device.SafeState(false); err == nil {
+		t.Error("expected the module query error")
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("expected every channel to be turned OFF, %d frames left", remaining)
+	}
+}
+
+func TestSafeStatePreempts(t *testing.T) {
+	replay := &gatedReplay{
+		Replay: newReplay(t,
+			"@001PR1?;FF", "@001ACK1.00E-07;FF",
+			"@001MT?;FF", "@001ACKCC,PR,NC,NA;FF",
+			"@001CP1!OFF;FF", "@001ACKOFF;FF",
+			"@001PR2?;FF", "@001ACK2.00E-07;FF",
+		),
+		frame:   "@001PR1?;FF",
+		release: make(chan struct{}),
+		writing: make(chan struct{}),
+	}
+	device := &protocol.MKS937B{Communication: replay, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	wg.Go(func() {
+		_, err := device.GetPressure(1)
+		errs <- err
+	})
+	<-replay.writing
+	// Queued behind the transaction in progress
+	wg.Go(func() {
+		_, err := device.GetPressure(2)
+		errs <- err
+	})
+	time.Sleep(20 * time.Millisecond)
+	wg.Go(func() { errs <- device.SafeState(false) })
+	time.Sleep(20 * time.Millisecond)
+	close(replay.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
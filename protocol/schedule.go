@@ -0,0 +1,150 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Cron-like schedule made of five fields: minute (0-59), hour
+(0-23), day of month (1-31), month (1-12) and day of week (0-6,
+Sunday is 0 or 7). Each field is *, a value, a range such as
+1-5, any of them followed by a step such as 8-18/2, or a comma
+separated list of them. As in cron, when both days are
+restricted a time matches either of them
+*/
+type Schedule struct {
+	spec                          string
+	minute, hour, day, month, dow uint64 // Bit set of the allowed values
+	anyDay, anyDow                bool
+}
+
+/*
+Parses a schedule, e.g. "0 2 * * 0" for Sundays at 02:00
+*/
+func ParseSchedule(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("%w: schedule %q must have 5 fields", ErrInvalidParameter, spec)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for idx, field := range fields {
+		set, err := parseScheduleField(field, bounds[idx][0], bounds[idx][1])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("%w: schedule %q: %v", ErrInvalidParameter, spec, err)
+		}
+		sets[idx] = set
+	}
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+	return Schedule{
+		spec:   spec,
+		minute: sets[0],
+		hour:   sets[1],
+		day:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDay: strings.HasPrefix(fields[2], "*"),
+		anyDow: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+/*
+Parses a field into the bit set of its values
+*/
+func parseScheduleField(field string, low int, high int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		first, last, step := low, high, 1
+		valueRange, stepStr, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+		if valueRange != "*" {
+			firstStr, lastStr, isRange := strings.Cut(valueRange, "-")
+			var err error
+			if first, err = strconv.Atoi(firstStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(lastStr); err != nil {
+					return 0, fmt.Errorf("invalid range %q", part)
+				}
+			} else if hasStep {
+				last = high
+			}
+		}
+		if first < low || last > high || first > last {
+			return 0, fmt.Errorf("%q is out of %d-%d", part, low, high)
+		}
+		for value := first; value <= last; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+/*
+Returns the schedule as parsed
+*/
+func (s Schedule) String() string {
+	return s.spec
+}
+
+/*
+Returns true if a time matches the days of the schedule
+*/
+func (s Schedule) matchesDay(t time.Time) bool {
+	day := s.day&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.anyDay && s.anyDow:
+		return true
+	case s.anyDay:
+		return dow
+	case s.anyDow:
+		return day
+	}
+	return day || dow
+}
+
+/*
+Returns the first time of the schedule strictly after a time,
+or the zero time if there is none within five years
+*/
+func (s Schedule) Next(after time.Time) time.Time {
+	if s.minute == 0 {
+		return time.Time{}
+	}
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,39 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestSchedule(t *testing.T) {
+	after := time.Date(2026, time.October, 17, 10, 30, 15, 0, time.UTC) // Saturday
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.October, 17, 10, 31, 0, 0, time.UTC)},
+		{"0 2 * * 0", time.Date(2026, time.October, 18, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 7", time.Date(2026, time.October, 18, 2, 0, 0, 0, time.UTC)},
+		{"15,45 8-18/2 * * 1-5", time.Date(2026, time.October, 19, 8, 15, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 20 * 6", time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		schedule, err := protocol.ParseSchedule(c.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) = %v", c.spec, err)
+		}
+		if got := schedule.Next(after); !got.Equal(c.want) {
+			t.Errorf("%q Next = %s, want %s", c.spec, got, c.want)
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *"} {
+		if _, err := protocol.ParseSchedule(spec); !errors.Is(err, protocol.ErrInvalidParameter) {
+			t.Errorf("ParseSchedule(%q) = %v, want ErrInvalidParameter", spec, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+State of the sensor on a channel. Power, gas type and gas
+correction are left empty when no sensor is connected, and the
+gas correction is only read for HC and CC sensors
+*/
+type SensorInfo struct {
+	Channel       int             `json:"channel" yaml:"channel"`
+	Label         string          `json:"label" yaml:"label"`
+	Type          string          `json:"type" yaml:"type"`     // CC, HC, PR, CP, CM or FC, NC/NG without sensor
+	Status        string          `json:"status" yaml:"status"` // Sensor status (Tn) of HC/CC, reading status otherwise
+	Power         bool            `json:"power" yaml:"power"`
+	Pressure      PressureReading `json:"pressure" yaml:"pressure"`
+	Unit          string          `json:"unit" yaml:"unit"` // Unit of the pressure
+	GasType       string          `json:"gas_type,omitempty" yaml:"gas_type,omitempty"`
+	GasCorrection float64         `json:"gas_correction,omitempty" yaml:"gas_correction,omitempty"`
+}
+
+/*
+Gets the sensor type connected to a channel from 1 to 6
+*/
+func (m *MKS937B) GetSensorType(channel int) (string, error) {
+	if err := m.checkChannel(ReadingCommands, channel); err != nil {
+		return "", err
+	}
+	command := "ST" + string(rune('A'+(channel-1)/2))
+	response, err := m.Query(command)
+	if err != nil {
+		return "", err
+	}
+	response = strings.NewReplacer(",", "", " ", "").Replace(response)
+	if len(response) != 4 {
+		return "", NewErrUnexpectedReply(command, response)
+	}
+	if channel%2 == 1 {
+		return response[:2], nil
+	}
+	return response[2:], nil
+}
+
+/*
+Gets the type, status, power state, pressure and gas correction
+of the sensor on a channel from 1 to 6 in a single call
+*/
+func (m *MKS937B) SensorInfo(channel int) (SensorInfo, error) {
+	info := SensorInfo{Channel: channel, Label: m.ChannelLabel(channel)}
+	var err error
+
+	if info.Type, err = m.GetSensorType(channel); err != nil {
+		return info, err
+	}
+	if info.Pressure, err = m.GetPressure(channel); err != nil {
+		return info, err
+	}
+	if info.Unit, err = m.readingUnit(); err != nil {
+		return info, err
+	}
+	info.Status = info.Pressure.Status
+	if info.Type == "NC" || info.Type == "NG" {
+		return info, nil
+	}
+
+	power, err := m.Query(fmt.Sprintf("CP%d", channel))
+	if err != nil {
+		return info, err
+	}
+	info.Power = power == "ON"
+	if info.Type == "CM" || info.Type == "FC" {
+		return info, nil
+	}
+	if info.GasType, err = m.Query(fmt.Sprintf("GT%d", channel)); err != nil {
+		return info, err
+	}
+
+	ionGauge := slices.Contains([]string{"HC", "CC"}, info.Type)
+	if !ionGauge {
+		return info, nil
+	}
+	if info.Status, err = m.GetSensorStatus(channel); err != nil {
+		return info, err
+	}
+	command := fmt.Sprintf("GC%d", channel)
+	if info.Type == "CC" {
+		command = fmt.Sprintf("UC%d", channel)
+	}
+	response, err := m.Query(command)
+	if err != nil {
+		return info, err
+	}
+	info.GasCorrection, err = strconv.ParseFloat(response, 64)
+	return info, err
+}
@@ -0,0 +1,174 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/*
+Setting lost when the controller is power cycled, such as the
+power state or control mode of a sensor, as the command and the
+value it must have
+*/
+type VolatileSetting struct {
+	Command string `json:"command" yaml:"command"`
+	Value   string `json:"value" yaml:"value"`
+}
+
+func (s VolatileSetting) String() string {
+	return s.Command + "=" + s.Value
+}
+
+/*
+Power state of the sensor on channel 1, 3 or 5
+*/
+func PowerSetting(channel int, on bool) VolatileSetting {
+	if on {
+		return VolatileSetting{Command: fmt.Sprintf("CP%d", channel), Value: "ON"}
+	}
+	return VolatileSetting{Command: fmt.Sprintf("CP%d", channel), Value: "OFF"}
+}
+
+/*
+Control mode (AUTO, SAFE or OFF) of the sensor on channel 1, 3
+or 5
+*/
+func ControlModeSetting(channel int, mode string) VolatileSetting {
+	return VolatileSetting{Command: fmt.Sprintf("CTL%d", channel), Value: mode}
+}
+
+/*
+Registers settings to restore after a power cycle, replacing
+the registered settings with the same commands
+*/
+func (m *MKS937B) RememberSettings(settings ...VolatileSetting) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, setting := range settings {
+		idx := slices.IndexFunc(m.session, func(s VolatileSetting) bool {
+			return s.Command == setting.Command
+		})
+		if idx < 0 {
+			m.session = append(m.session, setting)
+		} else {
+			m.session[idx] = setting
+		}
+	}
+}
+
+/*
+Unregisters the settings of the given commands
+*/
+func (m *MKS937B) ForgetSettings(commands ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.session = slices.DeleteFunc(m.session, func(s VolatileSetting) bool {
+		return slices.Contains(commands, s.Command)
+	})
+}
+
+/*
+Returns the settings registered to restore after a power cycle
+*/
+func (m *MKS937B) RememberedSettings() []VolatileSetting {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.session)
+}
+
+/*
+Marks the controller as possibly power cycled, e.g. after a
+facility power failure known to the application, so the next
+RestoreSession checks the registered settings
+*/
+func (m *MKS937B) SuspectReboot() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rebootSuspected = true
+}
+
+/*
+Returns true if the controller may have been power cycled
+since the session was last checked: it stopped answering, the
+connection was reestablished, another controller answered at
+the address, or SuspectReboot was called
+*/
+func (m *MKS937B) RebootSuspected() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.rebootSuspected
+}
+
+/*
+Restores the session after a power cycle. Nothing is read or
+written unless a reboot is suspected, see RebootSuspected, so
+settings changed on purpose, e.g. at the front panel, are not
+overwritten. Otherwise the registered settings are read and
+the ones that differ are written back in the order they were
+registered. A session restored event lists the settings
+written. It returns the settings restored, also when it stops
+at an error, in which case the reboot is still suspected
+*/
+func (m *MKS937B) RestoreSession() ([]VolatileSetting, error) {
+	m.mutex.Lock()
+	suspected := m.rebootSuspected
+	m.rebootSuspected = false
+	m.mutex.Unlock()
+	if !suspected {
+		return nil, nil
+	}
+
+	restored, err := m.restoreSession()
+	if err != nil {
+		m.SuspectReboot()
+	}
+	return restored, err
+}
+
+/*
+Reads the registered settings and writes back the ones that
+differ
+*/
+func (m *MKS937B) restoreSession() ([]VolatileSetting, error) {
+	var lost []VolatileSetting
+	for _, setting := range m.RememberedSettings() {
+		value, err := m.Query(setting.Command)
+		if err != nil {
+			return nil, err
+		}
+		if value != setting.Value {
+			lost = append(lost, setting)
+		}
+	}
+
+	var restored []VolatileSetting
+	var err error
+	for _, setting := range lost {
+		if err = m.Set(setting.Command, setting.Value); err != nil {
+			break
+		}
+		restored = append(restored, setting)
+	}
+	if len(restored) > 0 {
+		names := make([]string, len(restored))
+		for idx, setting := range restored {
+			names[idx] = setting.String()
+		}
+		m.mutex.Lock()
+		m.publish(Event{Kind: EventSessionRestored, Message: "restored " + strings.Join(names, ", ")})
+		m.mutex.Unlock()
+	}
+	return restored, err
+}
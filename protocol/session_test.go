@@ -0,0 +1,41 @@
+package protocol_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestRestoreSession(t *testing.T) {
+	device := replayDevice(t,
+		"@001CP1?;FF", "@001ACKOFF;FF",
+		"@001CTL1?;FF", "@001ACKAUTO;FF",
+		"@001CP1!ON;FF", "@001ACKON;FF",
+	)
+	events, unsubscribe := device.Events().Subscribe(1)
+	defer unsubscribe()
+	device.RememberSettings(protocol.PowerSetting(1, false), protocol.ControlModeSetting(1, "AUTO"))
+	device.RememberSettings(protocol.PowerSetting(1, true))
+
+	// Nothing is read while no reboot is suspected
+	if restored, err := device.RestoreSession(); err != nil || restored != nil {
+		t.Fatalf("expected nothing to be restored, got %v, %v", restored, err)
+	}
+
+	device.SuspectReboot()
+	restored, err := device.RestoreSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(restored, []protocol.VolatileSetting{protocol.PowerSetting(1, true)}) {
+		t.Errorf("expected CP1 to be restored, got %v", restored)
+	}
+	event := <-events
+	if event.Kind != protocol.EventSessionRestored || event.Message != "restored CP1=ON" {
+		t.Errorf("unexpected event %v %q", event.Kind, event.Message)
+	}
+	if device.RebootSuspected() {
+		t.Error("expected the reboot to be cleared once the session is restored")
+	}
+}
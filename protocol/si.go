@@ -0,0 +1,110 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import "strconv"
+
+// Returns every pressure in Pascal whatever the unit configured on
+// the controller, so applications can work in SI internally while
+// operators keep their unit on the front panel. It applies to
+// readings, set point and hysteresis getters, and to the relay
+// setters that take values in the unit of the readings. The
+// device unit is read once and cached until the next Connect or
+// SetPressureUnit
+func WithSIUnits() Option {
+	return func(m *MKS937B) {
+		m.siUnits = true
+	}
+}
+
+/*
+Returns the unit of the pressures returned by the driver, the
+device unit or PASCAL with WithSIUnits
+*/
+func (m *MKS937B) readingUnit() (string, error) {
+	if m.siUnits {
+		return "PASCAL", nil
+	}
+	return m.GetPressureUnit()
+}
+
+/*
+Returns the device unit, cached with WithSIUnits
+*/
+func (m *MKS937B) cachedUnit() (string, error) {
+	m.mutex.Lock()
+	unit := m.unit
+	m.mutex.Unlock()
+	if unit != "" {
+		return unit, nil
+	}
+
+	unit, err := m.GetPressureUnit()
+	if err != nil {
+		return "", err
+	}
+	m.mutex.Lock()
+	m.unit = unit
+	m.mutex.Unlock()
+	return unit, nil
+}
+
+/*
+Converts a pressure read from the device to the unit of the
+readings
+*/
+func (m *MKS937B) fromDevice(value float64) (float64, error) {
+	if !m.siUnits {
+		return value, nil
+	}
+	unit, err := m.cachedUnit()
+	if err != nil {
+		return 0, err
+	}
+	pressure, err := Pressure{Value: value, Unit: unit}.In("PASCAL")
+	return pressure.Value, err
+}
+
+/*
+Converts a pressure in the unit of the readings to the device
+unit
+*/
+func (m *MKS937B) toDevice(value float64) (float64, error) {
+	if !m.siUnits {
+		return value, nil
+	}
+	unit, err := m.cachedUnit()
+	if err != nil {
+		return 0, err
+	}
+	pressure, err := Pressure{Value: value, Unit: "PASCAL"}.In(unit)
+	return pressure.Value, err
+}
+
+/*
+Converts a reading from the device to the unit of the readings
+*/
+func (m *MKS937B) normalizeReading(reading PressureReading) (PressureReading, error) {
+	if reading.Status != "OK" {
+		return reading, nil
+	}
+	value, err := m.fromDevice(reading.Value)
+	reading.Value = value
+	return reading, err
+}
+
+/*
+Parses a set point from the device and converts it to the unit
+of the readings
+*/
+func (m *MKS937B) parseSetPoint(response string) (float64, error) {
+	value, err := strconv.ParseFloat(response, 64)
+	if err != nil {
+		return 0, err
+	}
+	return m.fromDevice(value)
+}
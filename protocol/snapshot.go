@@ -0,0 +1,67 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Version of the snapshot JSON document. It is increased whenever a
+// field is renamed or removed, while new fields keep the version.
+const SnapshotVersion = 1
+
+type Snapshot struct {
+	Version   int               `json:"version" yaml:"version"`
+	Time      time.Time         `json:"time" yaml:"time"`
+	System    SystemInfo        `json:"system" yaml:"system"`
+	Pressures []PressureReading `json:"pressures" yaml:"pressures"`
+	Relays    []RelayConfig     `json:"relays" yaml:"relays"`
+}
+
+/*
+Captures the system information, all channel pressures and the
+relays of the installed modules
+*/
+func (m *MKS937B) Snapshot() (Snapshot, error) {
+	snapshot := Snapshot{Version: SnapshotVersion, Time: time.Now()}
+	var err error
+
+	if snapshot.System, err = m.SystemInfo(); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Pressures, err = m.GetPressures(); err != nil {
+		return snapshot, err
+	}
+	for relay := 1; relay <= 12; relay++ {
+		slot := (relay - 1) / 4
+		if slot >= len(snapshot.System.Modules) || snapshot.System.Modules[slot] == "NC" {
+			continue
+		}
+		config, err := m.GetRelayConfig(relay)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.Relays = append(snapshot.Relays, config)
+	}
+	return snapshot, nil
+}
+
+/*
+Decodes a snapshot JSON document, rejecting documents written
+by a newer, incompatible version
+*/
+func ParseSnapshot(data []byte) (Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Version < 1 || SnapshotVersion < snapshot.Version {
+		return snapshot, NewErrUnsupportedVersion(SnapshotVersion, snapshot.Version)
+	}
+	return snapshot, nil
+}
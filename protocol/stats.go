@@ -0,0 +1,344 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"errors"
+	"expvar"
+	"maps"
+	"math"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/core"
+)
+
+// Upper bounds of the latency histogram buckets. The histogram
+// has one more bucket counting the latencies above the last bound
+var LatencyBuckets = [...]time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+}
+
+type CommandStats struct {
+	Count     uint64                          `json:"count" yaml:"count"`
+	Errors    uint64                          `json:"errors" yaml:"errors"`
+	Total     time.Duration                   `json:"total" yaml:"total"` // Sum of the latencies
+	Last      time.Duration                   `json:"last" yaml:"last"`
+	Max       time.Duration                   `json:"max" yaml:"max"`
+	Histogram [len(LatencyBuckets) + 1]uint64 `json:"histogram" yaml:"histogram"` // Counts per LatencyBuckets bucket
+}
+
+/*
+Returns the mean latency of the command
+*/
+func (c CommandStats) Mean() time.Duration {
+	if c.Count == 0 {
+		return 0
+	}
+	return c.Total / time.Duration(c.Count)
+}
+
+/*
+Estimates a latency percentile (0 to 100) as the upper bound
+of the histogram bucket containing it. Latencies above the
+last bucket bound are estimated as the maximum latency
+*/
+func (c CommandStats) Percentile(percentile float64) time.Duration {
+	if c.Count == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(percentile / 100 * float64(c.Count)))
+	var count uint64
+	for idx, bound := range LatencyBuckets {
+		count += c.Histogram[idx]
+		if count >= target {
+			return min(bound, c.Max)
+		}
+	}
+	return c.Max
+}
+
+// Period covered by the recent counters of Stats, made of one
+// minute buckets
+const RollingWindow = 10 * time.Minute
+
+type ErrorCounts struct {
+	Commands      uint64 `json:"commands" yaml:"commands"`
+	Errors        uint64 `json:"errors" yaml:"errors"`
+	Timeouts      uint64 `json:"timeouts" yaml:"timeouts"`
+	NAKs          uint64 `json:"naks" yaml:"naks"`
+	ParseFailures uint64 `json:"parse_failures" yaml:"parse_failures"` // Unexpected reply, address or parameter
+}
+
+/*
+Returns the ratio of errors per command
+*/
+func (e ErrorCounts) ErrorRate() float64 {
+	if e.Commands == 0 {
+		return 0
+	}
+	return float64(e.Errors) / float64(e.Commands)
+}
+
+func (e *ErrorCounts) add(other ErrorCounts) {
+	e.Commands += other.Commands
+	e.Errors += other.Errors
+	e.Timeouts += other.Timeouts
+	e.NAKs += other.NAKs
+	e.ParseFailures += other.ParseFailures
+}
+
+/*
+Internal counters of the driver. Commands that could not be
+sent because the device was disconnected are not counted
+*/
+type Stats struct {
+	Commands          uint64                  `json:"commands" yaml:"commands"`
+	Errors            uint64                  `json:"errors" yaml:"errors"`
+	Timeouts          uint64                  `json:"timeouts" yaml:"timeouts"`
+	NAKs              uint64                  `json:"naks" yaml:"naks"`
+	ParseFailures     uint64                  `json:"parse_failures" yaml:"parse_failures"`
+	Recent            ErrorCounts             `json:"recent" yaml:"recent"` // Over the last RollingWindow
+	Reconnects        uint64                  `json:"reconnects" yaml:"reconnects"`
+	ReconnectAttempts uint64                  `json:"reconnect_attempts" yaml:"reconnect_attempts"` // Including failed ones
+	ConnectedSince    time.Time               `json:"connected_since" yaml:"connected_since"`       // Zero while disconnected
+	Uptime            time.Duration           `json:"uptime" yaml:"uptime"`                         // Of the current connection
+	LastRecovery      time.Duration           `json:"last_recovery" yaml:"last_recovery"`           // Time to recover of the last reconnect
+	MaxRecovery       time.Duration           `json:"max_recovery" yaml:"max_recovery"`
+	LastSuccess       time.Time               `json:"last_success" yaml:"last_success"`
+	LastError         string                  `json:"last_error" yaml:"last_error"`
+	LastErrorTime     time.Time               `json:"last_error_time" yaml:"last_error_time"`
+	PerCommand        map[string]CommandStats `json:"per_command" yaml:"per_command"` // By mnemonic
+}
+
+type statsCollector struct {
+	stats        Stats
+	lastError    error
+	connects     uint64
+	failingSince time.Time // First failure since the last success
+	buckets      [RollingWindow / time.Minute]ErrorCounts
+	minutes      [RollingWindow / time.Minute]int64 // Minute of each bucket
+	mutex        sync.Mutex
+}
+
+/*
+Returns true if the error is a read or write timeout
+*/
+func isTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, os.ErrDeadlineExceeded) || strings.Contains(err.Error(), "timeout")
+}
+
+/*
+Returns true if the error is a reply that could not be parsed
+or did not match the request
+*/
+func isParseFailure(err error) bool {
+	var reply *ErrUnexpectedReply
+	var address *ErrUnexpectedAddress
+	var parameter *ErrUnexpectedParameter
+	return errors.As(err, &reply) || errors.As(err, &address) || errors.As(err, &parameter)
+}
+
+/*
+Records the result of a transaction
+*/
+func (s *statsCollector) record(command string, latency time.Duration, nak bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	mnemonic, _ := core.SplitCommand(command)
+	if s.stats.PerCommand == nil {
+		s.stats.PerCommand = make(map[string]CommandStats)
+	}
+	entry := s.stats.PerCommand[mnemonic]
+	entry.Count++
+	entry.Total += latency
+	entry.Last = latency
+	entry.Max = max(entry.Max, latency)
+	bucket := len(LatencyBuckets)
+	for idx, bound := range LatencyBuckets {
+		if latency <= bound {
+			bucket = idx
+			break
+		}
+	}
+	entry.Histogram[bucket]++
+
+	now := time.Now()
+	counts := ErrorCounts{Commands: 1}
+	if nak {
+		counts.NAKs = 1
+	}
+	if err == nil {
+		s.stats.LastSuccess = now
+		s.failingSince = time.Time{}
+	} else {
+		if s.failingSince.IsZero() {
+			s.failingSince = now
+		}
+		entry.Errors++
+		counts.Errors = 1
+		if isTimeout(err) {
+			counts.Timeouts = 1
+		}
+		if isParseFailure(err) {
+			counts.ParseFailures = 1
+		}
+		s.lastError = err
+		s.stats.LastError = err.Error()
+		s.stats.LastErrorTime = now
+	}
+	s.stats.PerCommand[mnemonic] = entry
+
+	s.stats.Commands += counts.Commands
+	s.stats.Errors += counts.Errors
+	s.stats.Timeouts += counts.Timeouts
+	s.stats.NAKs += counts.NAKs
+	s.stats.ParseFailures += counts.ParseFailures
+
+	minute := now.Unix() / 60
+	idx := minute % int64(len(s.buckets))
+	if s.minutes[idx] != minute {
+		s.minutes[idx] = minute
+		s.buckets[idx] = ErrorCounts{}
+	}
+	s.buckets[idx].add(counts)
+}
+
+/*
+Returns the stats of the mnemonic of a command
+*/
+func (s *statsCollector) command(command string) CommandStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	mnemonic, _ := core.SplitCommand(command)
+	return s.stats.PerCommand[mnemonic]
+}
+
+/*
+Sums the buckets of the rolling window. The caller must hold
+the mutex
+*/
+func (s *statsCollector) recent() ErrorCounts {
+	var counts ErrorCounts
+	minute := time.Now().Unix() / 60
+	for idx, bucket := range s.buckets {
+		if minute-s.minutes[idx] < int64(len(s.buckets)) {
+			counts.add(bucket)
+		}
+	}
+	return counts
+}
+
+/*
+Records a connection attempt and returns true if the device was
+connected before, in which case it is a reconnect attempt
+*/
+func (s *statsCollector) attempt() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stats.ConnectedSince = time.Time{}
+	if s.connects == 0 {
+		return false
+	}
+	s.stats.ReconnectAttempts++
+	return true
+}
+
+/*
+Records the result of a connection attempt. Any connection after
+the first one is counted as a reconnect, and its time to recover
+is measured from the first failure since the last success
+*/
+func (s *statsCollector) connected(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if err != nil {
+		if s.failingSince.IsZero() {
+			s.failingSince = now
+		}
+		return
+	}
+	if s.connects > 0 {
+		s.stats.Reconnects++
+		if !s.failingSince.IsZero() {
+			s.stats.LastRecovery = now.Sub(s.failingSince)
+			s.stats.MaxRecovery = max(s.stats.MaxRecovery, s.stats.LastRecovery)
+		}
+	}
+	s.failingSince = time.Time{}
+	s.stats.ConnectedSince = now
+	s.connects++
+}
+
+/*
+Records a disconnection
+*/
+func (s *statsCollector) disconnected() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stats.ConnectedSince = time.Time{}
+}
+
+/*
+Returns a copy of the internal counters of the driver
+*/
+func (m *MKS937B) Stats() Stats {
+	m.stats.mutex.Lock()
+	defer m.stats.mutex.Unlock()
+
+	stats := m.stats.stats
+	stats.PerCommand = maps.Clone(stats.PerCommand)
+	stats.Recent = m.stats.recent()
+	if !stats.ConnectedSince.IsZero() {
+		stats.Uptime = time.Since(stats.ConnectedSince)
+	}
+	return stats
+}
+
+/*
+Returns the last transaction error and when it happened, or
+nil if no transaction failed
+*/
+func (m *MKS937B) LastError() (error, time.Time) {
+	m.stats.mutex.Lock()
+	defer m.stats.mutex.Unlock()
+
+	return m.stats.lastError, m.stats.stats.LastErrorTime
+}
+
+/*
+Publishes the driver counters as an expvar variable, served
+as JSON on /debug/vars by the default HTTP mux.
+
+Like expvar.Publish, it panics if the name is already in use
+*/
+func (m *MKS937B) PublishStats(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return m.Stats()
+	}))
+}
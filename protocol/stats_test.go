@@ -0,0 +1,145 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStatsRecord(t *testing.T) {
+	var device MKS937B
+	device.stats.record("PR1", 5*time.Millisecond, false, nil)
+	device.stats.record("PR2", 6*time.Millisecond, true, nil)
+	device.stats.record("PR1", 3*time.Second, false, os.ErrDeadlineExceeded)
+	device.stats.record("CP1", time.Millisecond, false, NewErrUnexpectedReply("CP1", "garbage"))
+
+	stats := device.Stats()
+	if stats.Commands != 4 || stats.Errors != 2 || stats.NAKs != 1 || stats.Timeouts != 1 || stats.ParseFailures != 1 {
+		t.Errorf("unexpected counters %+v", stats)
+	}
+	if stats.Recent != (ErrorCounts{Commands: 4, Errors: 2, Timeouts: 1, NAKs: 1, ParseFailures: 1}) {
+		t.Errorf("unexpected recent counters %+v", stats.Recent)
+	}
+	if err, at := device.LastError(); err == nil || at.IsZero() || stats.LastError != err.Error() {
+		t.Errorf("LastError() = %v at %v, stats %q", err, at, stats.LastError)
+	}
+
+	// Readings of all channels share the PR mnemonic
+	pr := stats.PerCommand["PR"]
+	if pr.Count != 3 || pr.Errors != 1 || pr.Last != 3*time.Second || pr.Max != 3*time.Second {
+		t.Errorf("unexpected PR stats %+v", pr)
+	}
+	// A latency equal to a bound is counted in its bucket
+	expected := [len(LatencyBuckets) + 1]uint64{0: 1, 1: 1, len(LatencyBuckets): 1}
+	if pr.Histogram != expected {
+		t.Errorf("expected histogram %v, got %v", expected, pr.Histogram)
+	}
+	if mean := pr.Mean(); mean != (5*time.Millisecond+6*time.Millisecond+3*time.Second)/3 {
+		t.Errorf("unexpected mean %v", mean)
+	}
+}
+
+func TestStatsRollingWindow(t *testing.T) {
+	var collector statsCollector
+	collector.record("PR1", time.Millisecond, false, errors.New("read timeout"))
+	if recent := collector.recent(); recent.Errors != 1 || recent.Timeouts != 1 {
+		t.Fatalf("unexpected recent counters %+v", recent)
+	}
+
+	// The bucket of the error leaves the window
+	for idx, bucket := range collector.buckets {
+		if bucket.Commands > 0 {
+			collector.minutes[idx] -= int64(len(collector.buckets))
+		}
+	}
+	if recent := collector.recent(); recent != (ErrorCounts{}) {
+		t.Errorf("expected the expired bucket to be left out, got %+v", recent)
+	}
+
+	// An expired bucket is reset before it is reused
+	collector.record("PR1", time.Millisecond, false, nil)
+	if recent := collector.recent(); recent != (ErrorCounts{Commands: 1}) {
+		t.Errorf("expected only the new command, got %+v", recent)
+	}
+	if collector.stats.Errors != 1 || collector.stats.Commands != 2 {
+		t.Errorf("expected the totals to be kept, got %+v", collector.stats)
+	}
+}
+
+func TestStatsReconnect(t *testing.T) {
+	var device MKS937B
+	if device.stats.attempt() {
+		t.Error("expected the first connection not to be a reconnect")
+	}
+	device.stats.connected(nil)
+	if stats := device.Stats(); stats.ConnectedSince.IsZero() || stats.Reconnects != 0 {
+		t.Errorf("unexpected stats after the first connection %+v", stats)
+	}
+
+	device.stats.record("PR1", time.Millisecond, false, errors.New("read timeout"))
+	device.stats.failingSince = time.Now().Add(-2 * time.Second)
+	device.stats.disconnected()
+	if !device.stats.attempt() {
+		t.Error("expected a reconnect attempt")
+	}
+	device.stats.connected(errors.New("connection refused"))
+	if !device.stats.attempt() {
+		t.Error("expected a reconnect attempt")
+	}
+	device.stats.connected(nil)
+
+	stats := device.Stats()
+	if stats.ReconnectAttempts != 2 || stats.Reconnects != 1 {
+		t.Errorf("expected 2 attempts and 1 reconnect, got %+v", stats)
+	}
+	if stats.LastRecovery < 2*time.Second || stats.MaxRecovery != stats.LastRecovery {
+		t.Errorf("expected a recovery time of 2s, got %v and %v", stats.LastRecovery, stats.MaxRecovery)
+	}
+	if stats.ConnectedSince.IsZero() || stats.Uptime < 0 {
+		t.Errorf("expected the device to be connected, got %+v", stats)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var stats CommandStats
+	if stats.Percentile(99) != 0 {
+		t.Error("expected 0 without commands")
+	}
+	stats.Count = 20
+	stats.Histogram[0] = 10
+	stats.Histogram[3] = 10
+	stats.Max = 40 * time.Millisecond
+	if p := stats.Percentile(50); p != 5*time.Millisecond {
+		t.Errorf("expected P50 of 5ms, got %v", p)
+	}
+	// The bucket bound is capped by the maximum latency
+	if p := stats.Percentile(90); p != 40*time.Millisecond {
+		t.Errorf("expected P90 of 40ms, got %v", p)
+	}
+	stats.Histogram[3] = 0
+	stats.Histogram[len(LatencyBuckets)] = 10
+	stats.Max = 4 * time.Second
+	if p := stats.Percentile(99); p != 4*time.Second {
+		t.Errorf("expected P99 of 4s above the last bucket, got %v", p)
+	}
+}
+
+func TestPublishStats(t *testing.T) {
+	// Names are published once per process, also with -count
+	name := fmt.Sprintf("mks937b_test_stats_%d", time.Now().UnixNano())
+	var device MKS937B
+	device.PublishStats(name)
+	device.stats.record("PR1", time.Millisecond, false, nil)
+
+	var stats Stats
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Commands != 1 || stats.PerCommand["PR"].Count != 1 {
+		t.Errorf("expected the current counters, got %+v", stats)
+	}
+}
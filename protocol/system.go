@@ -1,7 +1,7 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 24rd, 2025
-Last update: September 24rd, 2025
+Last update: October 17th, 2026
 */
 
 package protocol
@@ -13,6 +13,17 @@ import (
 	"strings"
 )
 
+type SystemInfo struct {
+	Address      int      `json:"address" yaml:"address"`
+	BaudRate     int      `json:"baud_rate" yaml:"baud_rate"`
+	Parity       string   `json:"parity" yaml:"parity"`
+	DelayTime    int      `json:"delay_time" yaml:"delay_time"`
+	Unit         string   `json:"unit" yaml:"unit"`
+	Firmware     string   `json:"firmware" yaml:"firmware"`
+	SerialNumber string   `json:"serial_number" yaml:"serial_number"`
+	Modules      []string `json:"modules" yaml:"modules"`
+}
+
 // Gets the controller address (1 to 254)
 func (m *MKS937B) GetAddress() (int, error) {
 	response, err := m.Query("AD")
@@ -50,7 +61,12 @@ func (m *MKS937B) SetBaudRate(baudrate int) error {
 	return m.Set("BR", fmt.Sprint(baudrate))
 }
 
-// Gets the controller parity
+// Gets the controller parity (NONE, EVEN or ODD)
+func (m *MKS937B) GetParity() (string, error) {
+	return m.Query("PAR")
+}
+
+// Sets the controller parity
 func (m *MKS937B) SetParity(parity string) error {
 	valid := []string{"NONE", "EVEN", "ODD"}
 	if !slices.Contains(valid, parity) {
@@ -75,7 +91,8 @@ func (m *MKS937B) SetDelayTime(delay int) error {
 	return m.Set("DLY", fmt.Sprint(delay))
 }
 
-// Gets the pressure unit
+// Gets the pressure unit configured on the device, which is not
+// the unit of the readings with WithSIUnits
 func (m *MKS937B) GetPressureUnit() (string, error) {
 	return m.Query("U")
 }
@@ -86,28 +103,194 @@ func (m *MKS937B) SetPressureUnit(unit string) error {
 	if !slices.Contains(valid, unit) {
 		return NewErrInvalidUnit(unit)
 	}
-	return m.Set("U", unit)
+	if err := m.Set("U", unit); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	m.unit = unit
+	m.mutex.Unlock()
+	return nil
 }
 
 // Gets the firmware version
 func (m *MKS937B) GetFirmwareVersion() (string, error) {
-	var sb strings.Builder
-
-	slots := []string{"Slot A", "Slot B", "Slot C", "AIO", "COMM", "Main"}
-	for i := range 6 {
-		response, err := m.Query(fmt.Sprintf("FV%d", i+1))
+	versions := make([]string, len(firmwareSlots))
+	for idx := range firmwareSlots {
+		response, err := m.Query(fmt.Sprintf("FV%d", idx+1))
 		if err != nil {
 			return "", err
 		}
-		sb.WriteString(slots[i] + ": " + response)
-		if i < 5 {
+		versions[idx] = response
+	}
+	return formatFirmwareVersion(versions), nil
+}
+
+// Boards reported by FV1 to FV6
+var firmwareSlots = []string{"Slot A", "Slot B", "Slot C", "AIO", "COMM", "Main"}
+
+// Joins the FV1 to FV6 replies with the name of each board
+func formatFirmwareVersion(versions []string) string {
+	var sb strings.Builder
+	for idx, version := range versions {
+		sb.WriteString(firmwareSlots[idx] + ": " + version)
+		if idx < len(versions)-1 {
 			sb.WriteString(" | ")
 		}
 	}
-	return sb.String(), nil
+	return sb.String()
 }
 
 // Gets the serial number of the unit
 func (m *MKS937B) GetSerialNumber() (string, error) {
 	return m.Query("SN")
 }
+
+// Gets the user calibration status. When disabled, zero and ATM
+// calibrations cannot be executed through the front panel or serial
+// commands. The setting applies to the whole controller.
+func (m *MKS937B) GetUserCalibration() (bool, error) {
+	response, err := m.Query("CAL")
+	if err != nil {
+		return false, err
+	}
+	return response == "Enable", nil
+}
+
+// Enables or disables the user calibration. Default is enabled.
+func (m *MKS937B) SetUserCalibration(status bool) error {
+	if status {
+		return m.Set("CAL", "Enable")
+	}
+	return m.Set("CAL", "Disable")
+}
+
+// Runs a calibration routine with the user calibration enabled and
+// restores the original status afterwards, even if the routine fails.
+func (m *MKS937B) RunWithUserCalibration(routine func() error) error {
+	original, err := m.GetUserCalibration()
+	if err != nil {
+		return err
+	}
+	if !original {
+		if err := m.SetUserCalibration(true); err != nil {
+			return err
+		}
+	}
+
+	routineErr := routine()
+	if !original {
+		if err := m.SetUserCalibration(false); err != nil && routineErr == nil {
+			return err
+		}
+	}
+	return routineErr
+}
+
+// Gets the sensor module types installed on slots A, B and C followed
+// by the communication option (NA, PF for PROFIBUS, or PC). Sensor
+// modules are one of CC, HC, CM, PR or FC, and NC means no connection.
+func (m *MKS937B) GetModuleTypes() ([]string, error) {
+	response, err := m.Query("MT")
+	if err != nil {
+		return nil, err
+	}
+	return parseModuleTypes(response), nil
+}
+
+// Splits the MT reply into the module type of each slot
+func parseModuleTypes(response string) []string {
+	modules := strings.Split(response, ",")
+	for idx := range modules {
+		modules[idx] = strings.TrimSpace(modules[idx])
+	}
+	return modules
+}
+
+// Gets the sensor type connected to each channel of the variant.
+// Types are CC, HC, PR, CP, CM or FC, and NC/NG when no sensor is
+// connected.
+func (m *MKS937B) GetSensorTypes() ([]string, error) {
+	variant := m.Variant()
+	types := make([]string, 0, 6)
+	for _, slot := range []string{"A", "B", "C"}[:variant.slots()] {
+		response, err := m.Query("ST" + slot)
+		if err != nil {
+			return nil, err
+		}
+		response = strings.NewReplacer(",", "", " ", "").Replace(response)
+		if len(response) != 4 {
+			return nil, NewErrUnexpectedReply("ST"+slot, response)
+		}
+		types = append(types, response[:2], response[2:])
+	}
+	return types[:variant.PressureFields()], nil
+}
+
+// Gathers the controller identity, communication settings, pressure
+// unit and module layout in a single call. The values are queried in
+// a row, without other commands in between
+func (m *MKS937B) SystemInfo() (SystemInfo, error) {
+	var info SystemInfo
+
+	commands := []string{"AD", "BR", "PAR", "DLY", "U", "SN", "MT"}
+	for idx := range firmwareSlots {
+		commands = append(commands, fmt.Sprintf("FV%d", idx+1))
+	}
+	values, err := m.queryBatch(commands...)
+	if err != nil {
+		return info, err
+	}
+
+	if info.Address, err = strconv.Atoi(values[0]); err != nil {
+		return info, err
+	}
+	if info.BaudRate, err = strconv.Atoi(values[1]); err != nil {
+		return info, err
+	}
+	info.Parity = values[2]
+	if info.DelayTime, err = strconv.Atoi(values[3]); err != nil {
+		return info, err
+	}
+	info.Unit = values[4]
+	info.SerialNumber = values[5]
+	info.Modules = parseModuleTypes(values[6])
+	info.Firmware = formatFirmwareVersion(values[7:])
+	return info, nil
+}
+
+// Gets the parameter setting status. When disabled, none of the system
+// or channel setup commands can be executed, but values can still be
+// queried.
+func (m *MKS937B) GetParameterSetting() (bool, error) {
+	response, err := m.Query("SPM")
+	if err != nil {
+		return false, err
+	}
+	return response == "Enable", nil
+}
+
+// Enables or disables parameter setting. Disabling it protects the
+// controller against unwanted setup changes. Default is enabled.
+func (m *MKS937B) SetParameterSetting(status bool) error {
+	if status {
+		return m.Set("SPM", "Enable")
+	}
+	return m.Set("SPM", "Disable")
+}
+
+// Gets the front panel lock status
+func (m *MKS937B) GetFrontPanelLock() (bool, error) {
+	response, err := m.Query("LOCK")
+	if err != nil {
+		return false, err
+	}
+	return response == "ON", nil
+}
+
+// Locks or unlocks the front panel keypad
+func (m *MKS937B) SetFrontPanelLock(status bool) error {
+	if status {
+		return m.Set("LOCK", "ON")
+	}
+	return m.Set("LOCK", "OFF")
+}
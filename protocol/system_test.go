@@ -0,0 +1,168 @@
+package protocol_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+)
+
+func TestSystemInfo(t *testing.T) {
+	device := replayDevice(t,
+		"@001AD?;FF", "@001ACK001;FF",
+		"@001BR?;FF", "@001ACK9600;FF",
+		"@001PAR?;FF", "@001ACKNONE;FF",
+		"@001DLY?;FF", "@001ACK8;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001SN?;FF", "@001ACK1234;FF",
+		"@001MT?;FF", "@001ACKCC, PR,NC,NA;FF",
+		"@001FV1?;FF", "@001ACK1.01;FF",
+		"@001FV2?;FF", "@001ACK1.02;FF",
+		"@001FV3?;FF", "@001ACK1.03;FF",
+		"@001FV4?;FF", "@001ACK1.04;FF",
+		"@001FV5?;FF", "@001ACK1.05;FF",
+		"@001FV6?;FF", "@001ACK1.06;FF",
+	)
+	info, err := device.SystemInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Address != 1 || info.BaudRate != 9600 || info.Parity != "NONE" || info.DelayTime != 8 ||
+		info.Unit != "Torr" || info.SerialNumber != "1234" {
+		t.Errorf("unexpected system info %+v", info)
+	}
+	if !slices.Equal(info.Modules, []string{"CC", "PR", "NC", "NA"}) {
+		t.Errorf("expected modules CC, PR, NC and NA, got %v", info.Modules)
+	}
+	want := "Slot A: 1.01 | Slot B: 1.02 | Slot C: 1.03 | AIO: 1.04 | COMM: 1.05 | Main: 1.06"
+	if info.Firmware != want {
+		t.Errorf("expected firmware %q, got %q", want, info.Firmware)
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("expected every frame to be replayed, %d left", remaining)
+	}
+}
+
+func TestSystemInfoInvalidReply(t *testing.T) {
+	device := replayDevice(t,
+		"@001AD?;FF", "@001ACK001;FF",
+		"@001BR?;FF", "@001ACKFAST;FF",
+		"@001PAR?;FF", "@001ACKNONE;FF",
+		"@001DLY?;FF", "@001ACK8;FF",
+		"@001U?;FF", "@001ACKTorr;FF",
+		"@001SN?;FF", "@001ACK1234;FF",
+		"@001MT?;FF", "@001ACKCC,PR,NC,NA;FF",
+		"@001FV1?;FF", "@001ACK1.01;FF",
+		"@001FV2?;FF", "@001ACK1.02;FF",
+		"@001FV3?;FF", "@001ACK1.03;FF",
+		"@001FV4?;FF", "@001ACK1.04;FF",
+		"@001FV5?;FF", "@001ACK1.05;FF",
+		"@001FV6?;FF", "@001ACK1.06;FF",
+	)
+	if info, err := device.SystemInfo(); err == nil {
+		t.Errorf("expected an error for the baud rate, got %+v", info)
+	}
+}
+
+func TestUserCalibration(t *testing.T) {
+	device := replayDevice(t,
+		"@001CAL?;FF", "@001ACKDisable;FF",
+		"@001CAL!Enable;FF", "@001ACKEnable;FF",
+		"@001CAL!Disable;FF", "@001ACKDisable;FF",
+	)
+	if enabled, err := device.GetUserCalibration(); err != nil || enabled {
+		t.Errorf("expected the user calibration to be disabled, got %v and %v", enabled, err)
+	}
+	if err := device.SetUserCalibration(true); err != nil {
+		t.Error(err)
+	}
+	if err := device.SetUserCalibration(false); err != nil {
+		t.Error(err)
+	}
+	expectReplayed(t, device)
+}
+
+func TestRunWithUserCalibration(t *testing.T) {
+	failed := errors.New("calibration failed")
+	for _, test := range []struct {
+		name   string
+		result error
+		pairs  []string
+	}{
+		{"restored", nil, []string{
+			"@001CAL?;FF", "@001ACKDisable;FF",
+			"@001CAL!Enable;FF", "@001ACKEnable;FF",
+			"@001PR2?;FF", "@001ACK1.00E-03;FF",
+			"@001CAL!Disable;FF", "@001ACKDisable;FF",
+		}},
+		{"restored on error", failed, []string{
+			"@001CAL?;FF", "@001ACKDisable;FF",
+			"@001CAL!Enable;FF", "@001ACKEnable;FF",
+			"@001PR2?;FF", "@001ACK1.00E-03;FF",
+			"@001CAL!Disable;FF", "@001ACKDisable;FF",
+		}},
+		{"already enabled", nil, []string{
+			"@001CAL?;FF", "@001ACKEnable;FF",
+			"@001PR2?;FF", "@001ACK1.00E-03;FF",
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			device := replayDevice(t, test.pairs...)
+			err := device.RunWithUserCalibration(func() error {
+				if _, err := device.GetPressure(2); err != nil {
+					return err
+				}
+				return test.result
+			})
+			if !errors.Is(err, test.result) || (test.result == nil && err != nil) {
+				t.Errorf("expected %v, got %v", test.result, err)
+			}
+			expectReplayed(t, device)
+		})
+	}
+}
+
+func TestParameterSetting(t *testing.T) {
+	device := replayDevice(t,
+		"@001SPM?;FF", "@001ACKEnable;FF",
+		"@001SPM!Disable;FF", "@001ACKDisable;FF",
+		"@001SPM?;FF", "@001ACKDisable;FF",
+		"@001SPM!Enable;FF", "@001ACKEnable;FF",
+	)
+	if enabled, err := device.GetParameterSetting(); err != nil || !enabled {
+		t.Errorf("expected parameter setting to be enabled, got %v and %v", enabled, err)
+	}
+	if err := device.SetParameterSetting(false); err != nil {
+		t.Error(err)
+	}
+	if enabled, err := device.GetParameterSetting(); err != nil || enabled {
+		t.Errorf("expected parameter setting to be disabled, got %v and %v", enabled, err)
+	}
+	if err := device.SetParameterSetting(true); err != nil {
+		t.Error(err)
+	}
+	expectReplayed(t, device)
+}
+
+func TestFrontPanelLock(t *testing.T) {
+	device := replayDevice(t,
+		"@001LOCK?;FF", "@001ACKOFF;FF",
+		"@001LOCK!ON;FF", "@001ACKON;FF",
+		"@001LOCK?;FF", "@001ACKON;FF",
+		"@001LOCK!OFF;FF", "@001ACKOFF;FF",
+	)
+	if locked, err := device.GetFrontPanelLock(); err != nil || locked {
+		t.Errorf("expected the front panel to be unlocked, got %v and %v", locked, err)
+	}
+	if err := device.SetFrontPanelLock(true); err != nil {
+		t.Error(err)
+	}
+	if locked, err := device.GetFrontPanelLock(); err != nil || !locked {
+		t.Errorf("expected the front panel to be locked, got %v and %v", locked, err)
+	}
+	if err := device.SetFrontPanelLock(false); err != nil {
+		t.Error(err)
+	}
+	expectReplayed(t, device)
+}
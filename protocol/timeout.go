@@ -0,0 +1,70 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import "time"
+
+/*
+Derives the read timeout of each command from its observed
+latency: the given percentile multiplied by the factor, bounded
+by the floor and the ceiling. Commands with fewer samples than
+MinSamples keep the timeout of the communication
+*/
+type AdaptiveTimeout struct {
+	Percentile float64 // 0 to 100
+	Factor     float64
+	Floor      time.Duration
+	Ceiling    time.Duration
+	MinSamples uint64
+}
+
+// Adaptive timeout of three times the 99th percentile, between
+// 50 ms and 2 s, after 20 samples
+var DefaultAdaptiveTimeout = AdaptiveTimeout{
+	Percentile: 99,
+	Factor:     3,
+	Floor:      50 * time.Millisecond,
+	Ceiling:    2 * time.Second,
+	MinSamples: 20,
+}
+
+/*
+Returns the read timeout for a command with the given stats, or
+false if it has not enough samples yet
+*/
+func (a AdaptiveTimeout) Timeout(stats CommandStats) (time.Duration, bool) {
+	if stats.Count == 0 || stats.Count < a.MinSamples {
+		return 0, false
+	}
+	timeout := time.Duration(float64(stats.Percentile(a.Percentile)) * a.Factor)
+	return min(max(timeout, a.Floor), a.Ceiling), true
+}
+
+// Tunes the read timeout of each command from its observed
+// latency, see AdaptiveTimeout. Only serial and TCP communications
+// are tuned
+func WithAdaptiveTimeout(adaptive AdaptiveTimeout) Option {
+	return func(m *MKS937B) {
+		m.adaptive = &adaptive
+	}
+}
+
+/*
+Applies the adaptive timeout of a command to the communication
+and returns a function restoring the previous timeout. The
+caller must hold the mutex
+*/
+func (m *MKS937B) adaptTimeout(command string) func() {
+	if m.adaptive == nil {
+		return func() {}
+	}
+	timeout, ok := m.adaptive.Timeout(m.stats.command(command))
+	if !ok {
+		return func() {}
+	}
+	return setReadTimeout(m.Communication, timeout)
+}
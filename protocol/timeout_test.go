@@ -0,0 +1,59 @@
+package protocol_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+func TestAdaptiveTimeout(t *testing.T) {
+	adaptive := protocol.DefaultAdaptiveTimeout
+
+	var stats protocol.CommandStats
+	if _, ok := adaptive.Timeout(stats); ok {
+		t.Error("expected no timeout without samples")
+	}
+
+	// 20 samples within 10 ms, p99 estimated as the 10 ms bound
+	stats = protocol.CommandStats{Count: 20, Max: 8 * time.Millisecond}
+	stats.Histogram[1] = 20
+	if timeout, ok := adaptive.Timeout(stats); !ok || timeout != adaptive.Floor {
+		t.Errorf("expected the floor, got %v", timeout)
+	}
+
+	stats.Max = 10 * time.Second
+	stats.Histogram[1] = 0
+	stats.Histogram[len(protocol.LatencyBuckets)] = 20
+	if timeout, _ := adaptive.Timeout(stats); timeout != adaptive.Ceiling {
+		t.Errorf("expected the ceiling, got %v", timeout)
+	}
+
+	stats.Max = 250 * time.Millisecond
+	stats.Histogram[len(protocol.LatencyBuckets)] = 0
+	stats.Histogram[5] = 20
+	if timeout, _ := adaptive.Timeout(stats); timeout != 750*time.Millisecond {
+		t.Errorf("expected 750ms, got %v", timeout)
+	}
+}
+
+func TestReconnectPolicyDelay(t *testing.T) {
+	policy := protocol.ReconnectPolicy{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2,
+	}
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for idx, delay := range expected {
+		if got := policy.Delay(idx + 1); got != delay*time.Millisecond {
+			t.Errorf("attempt %d: expected %v, got %v", idx+1, delay*time.Millisecond, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for range 100 {
+		if got := policy.Delay(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("expected a delay within the jitter, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+TLS settings of a TCP communication, with files in PEM format.
+Without CAFile the system roots verify the server, and the
+client certificate is only sent when CertFile and KeyFile are
+set
+*/
+type TLSOptions struct {
+	CAFile             string `yaml:"ca_file,omitempty"`
+	CertFile           string `yaml:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty"`
+	ServerName         string `yaml:"server_name,omitempty"`          // Default is the host
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"` // For test benches only
+}
+
+/*
+Loads the certificates of the options into a TLS configuration
+*/
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificate in %s", ErrInvalidParameter, o.CAFile)
+		}
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, fmt.Errorf("%w: client certificate needs both cert_file and key_file", ErrInvalidParameter)
+	}
+	if o.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+/*
+TCP communication wrapped in TLS, for secure serial servers and
+stunnel-style bridges. Reads and writes are the ones of the TCP
+communication, over the TLS connection
+*/
+type TLSPort struct {
+	*unicommtcp.UnicommTCP
+	Config *tls.Config
+}
+
+/*
+Wraps a TCP communication in TLS, e.g.
+
+	config, err := protocol.TLSOptions{CAFile: "ca.pem", CertFile: "client.pem", KeyFile: "client.key"}.Config()
+	device.Communication = protocol.NewTLSPort(tcp, config)
+*/
+func NewTLSPort(tcp *unicommtcp.UnicommTCP, config *tls.Config) *TLSPort {
+	return &TLSPort{UnicommTCP: tcp, Config: config}
+}
+
+// Wraps the TCP communication in TLS with a configuration, see
+// TLSOptions. It only applies to TCP communications and must
+// come after the communication is set
+func WithTLS(config *tls.Config) Option {
+	return func(m *MKS937B) {
+		if tcp, ok := m.Communication.(*unicommtcp.UnicommTCP); ok {
+			m.Communication = NewTLSPort(tcp, config)
+		}
+	}
+}
+
+/*
+Connects to the server and completes the TLS handshake
+*/
+func (p *TLSPort) Connect() error {
+	return p.dial(context.Background())
+}
+
+/*
+Connects to the server and completes the TLS handshake, giving
+up when the context is done
+*/
+func (p *TLSPort) dial(ctx context.Context) error {
+	if p.IsConnected() {
+		return errors.New("there is a connection already established")
+	}
+	dialer := tls.Dialer{Config: p.Config}
+	if _, ok := ctx.Deadline(); !ok {
+		dialer.NetDialer = &net.Dialer{Timeout: defaultDialTimeout}
+	}
+	address := net.JoinHostPort(p.Options.Host, strconv.FormatUint(uint64(p.Options.Port), 10))
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		p.Connection = nil
+		return err
+	}
+	p.Connection = connection
+	return nil
+}
+
+/*
+Returns the TCP communication of a communication, unwrapping
+the TLS
+*/
+func tcpPort(communication unicomm.Unicomm) (*unicommtcp.UnicommTCP, bool) {
+	switch c := communication.(type) {
+	case *unicommtcp.UnicommTCP:
+		return c, true
+	case *TLSPort:
+		return c.UnicommTCP, true
+	}
+	return nil, false
+}
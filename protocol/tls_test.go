@@ -0,0 +1,109 @@
+package protocol_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+// Writes a self-signed certificate for 127.0.0.1, usable by both
+// ends, and returns the paths of the certificate and its key
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+func TestTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+	options := protocol.TLSOptions{CAFile: certFile, CertFile: certFile, KeyFile: keyFile}
+	config, err := options.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConfig := config.Clone()
+	serverConfig.ClientCAs = config.RootCAs
+	serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		t.Skip("no loopback listener:", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		frame, err := reader.ReadString('F')
+		if err == nil && frame == "@001PR1?;F" {
+			conn.Write([]byte("@001ACK1.00E-07;FF"))
+		}
+		// Keeps the connection until the client closes it
+		reader.ReadString(0)
+	}()
+
+	tcp := unicommtcp.NewTCP(unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(port), ReadTimeout: time.Second})
+	device := &protocol.MKS937B{Communication: tcp, Address: 1}
+	device.Apply(protocol.WithTLS(config))
+	if _, ok := device.Communication.(*protocol.TLSPort); !ok {
+		t.Fatalf("expected a TLS communication, got %T", device.Communication)
+	}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer device.Disconnect()
+	pressure, err := device.GetPressure(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pressure.Value != 1e-7 {
+		t.Errorf("expected 1e-7, got %g", pressure.Value)
+	}
+
+	options.KeyFile = ""
+	if _, err := options.Config(); err == nil {
+		t.Error("expected an error for a client certificate without key")
+	}
+}
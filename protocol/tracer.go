@@ -0,0 +1,44 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import "time"
+
+/*
+Identifies the transaction a tracer hook is called for
+*/
+type TraceContext struct {
+	ID      string // Correlation ID, as in logs and errors
+	Device  string // Name of the device metadata
+	Address int
+	Command string
+}
+
+/*
+Receives the transactions of a device, so facilities can hook
+their own observability stack (e.g. OpenTelemetry spans) without
+this package depending on it. Hooks are called with the device
+mutex held, in the order OnSend, then OnReceive when a reply is
+read, then OnError if the transaction failed, and must not call
+the device back
+*/
+type Tracer interface {
+	OnSend(trace TraceContext, request string)
+	OnReceive(trace TraceContext, response string, duration time.Duration)
+	OnError(trace TraceContext, err error, duration time.Duration)
+}
+
+// Calls the tracer on every transaction
+func WithTracer(tracer Tracer) Option {
+	return func(m *MKS937B) {
+		m.tracer = tracer
+	}
+}
+
+func (m *MKS937B) traceContext(id string, command string) TraceContext {
+	return TraceContext{ID: id, Device: m.metadata.Name, Address: m.Address, Command: command}
+}
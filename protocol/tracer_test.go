@@ -0,0 +1,66 @@
+package protocol_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// Tracer recording the hooks it receives
+type recordingTracer struct {
+	calls []string
+	ids   []string
+}
+
+func (r *recordingTracer) OnSend(trace protocol.TraceContext, request string) {
+	r.record(trace, "send "+request)
+}
+
+func (r *recordingTracer) OnReceive(trace protocol.TraceContext, response string, duration time.Duration) {
+	r.record(trace, "receive "+response)
+}
+
+func (r *recordingTracer) OnError(trace protocol.TraceContext, err error, duration time.Duration) {
+	r.record(trace, "error")
+}
+
+func (r *recordingTracer) record(trace protocol.TraceContext, call string) {
+	r.calls = append(r.calls, fmt.Sprintf("%03d %s %s", trace.Address, trace.Command, call))
+	r.ids = append(r.ids, trace.ID)
+}
+
+func TestTracer(t *testing.T) {
+	device := replayDevice(t,
+		"@001SN?;FF", "@001ACK1234;FF",
+		"@001SN?;FF", "@002ACK1234;FF",
+	)
+	tracer := &recordingTracer{}
+	device.Apply(protocol.WithTracer(tracer))
+
+	if _, err := device.GetSerialNumber(); err != nil {
+		t.Fatal(err)
+	}
+	// The reply of another address fails after it is received
+	if _, err := device.GetSerialNumber(); err == nil {
+		t.Fatal("expected the address mismatch error")
+	}
+	expected := []string{
+		"001 SN send @001SN?;FF",
+		"001 SN receive @001ACK1234;FF",
+		"001 SN send @001SN?;FF",
+		"001 SN receive @002ACK1234;FF",
+		"001 SN error",
+	}
+	if !slices.Equal(tracer.calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, tracer.calls)
+	}
+	// Hooks of a transaction share its correlation ID
+	if tracer.ids[0] != tracer.ids[1] || tracer.ids[2] != tracer.ids[3] || tracer.ids[3] != tracer.ids[4] ||
+		tracer.ids[0] == tracer.ids[2] {
+		t.Errorf("unexpected correlation IDs %v", tracer.ids)
+	}
+	expectReplayed(t, device)
+}
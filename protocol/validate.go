@@ -0,0 +1,87 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+type ValidationCheck struct {
+	Name     string        `json:"name" yaml:"name"` // e.g. identity, unit or pressure A1
+	Passed   bool          `json:"passed" yaml:"passed"`
+	Detail   string        `json:"detail,omitempty" yaml:"detail,omitempty"` // Value read when passed
+	Error    string        `json:"error,omitempty" yaml:"error,omitempty"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+type ValidationReport struct {
+	Time   time.Time         `json:"time" yaml:"time"`
+	Passed bool              `json:"passed" yaml:"passed"` // True if every check passed
+	Checks []ValidationCheck `json:"checks" yaml:"checks"`
+}
+
+/*
+Runs a quick suite of queries that never modify the controller:
+the identity, the pressure unit, the hardware variant, the
+sensor types and one pressure read per channel with a sensor.
+It is meant for smoke tests after a deployment, so failures are
+reported in the returned report instead of stopping the suite.
+Pressures are only read when the sensor types are known
+*/
+func (m *MKS937B) Validate() ValidationReport {
+	report := ValidationReport{Time: time.Now(), Passed: true}
+	check := func(name string, run func() (string, error)) {
+		start := time.Now()
+		detail, err := run()
+		result := ValidationCheck{Name: name, Passed: err == nil, Duration: time.Since(start)}
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+		} else {
+			result.Detail = detail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	check("identity", func() (string, error) {
+		identity, err := m.ReadIdentity()
+		return fmt.Sprintf("serial number %s, firmware %s", identity.SerialNumber, identity.Firmware), err
+	})
+	check("unit", m.GetPressureUnit)
+	check("variant", func() (string, error) {
+		m.mutex.Lock()
+		known := m.variant != nil
+		m.mutex.Unlock()
+		if known {
+			return m.Variant().Name, nil
+		}
+		variant, err := m.DetectVariant()
+		return variant.Name, err
+	})
+
+	var sensors []string
+	check("sensor types", func() (string, error) {
+		var err error
+		sensors, err = m.GetSensorTypes()
+		return fmt.Sprint(sensors), err
+	})
+	for idx, sensor := range sensors {
+		if sensor == "NC" || sensor == "NG" {
+			continue
+		}
+		channel := idx + 1
+		check("pressure "+channelNames[idx], func() (string, error) {
+			reading, err := m.GetPressure(channel)
+			if reading.Status != "OK" {
+				return reading.Status, err
+			}
+			return fmt.Sprint(reading.Value), err
+		})
+	}
+	return report
+}
@@ -0,0 +1,142 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+/*
+Hardware variant of the controller, which sets the channels
+accepted by each class of commands and the number of pressures
+returned by PRZ
+*/
+type Variant struct {
+	Name     string                 `json:"name" yaml:"name"`
+	Channels map[CommandClass][]int `json:"channels" yaml:"channels"` // Channels, or relays, of each class of commands
+}
+
+// Controller with three dual slots, channels 1 to 6
+var SixChannelVariant = Variant{Name: "6-channel", Channels: channelCapabilities}
+
+// Controller with a dual slot A and a single slot B, channels 1 to 3.
+// The 937B manual (p/n 100016467 Rev F) only documents the three
+// slot controller, so this table is not taken from it: it applies
+// the slot layout of the manual (channels 2n-1 and 2n on slot n,
+// sensor control on the first channel of a slot, four relays per
+// slot) to a unit without slot C and a single sensor module in
+// slot B, keeping a single combination channel. Units
+// with another layout can be described with WithVariant
+var ThreeChannelVariant = Variant{
+	Name: "3-channel",
+	Channels: map[CommandClass][]int{
+		ReadingCommands:     {1, 2, 3},
+		ControlCommands:     {1, 3},
+		CombinationCommands: {1},
+		RelayCommands:       {1, 2, 3, 4, 5, 6, 7, 8},
+	},
+}
+
+// Variants by number of pressures returned by PRZ. Only the
+// documented layout is detected: other layouts, such as
+// ThreeChannelVariant, must be set with WithVariant
+var variantsByFields = map[int]Variant{
+	6: SixChannelVariant,
+}
+
+/*
+Returns the channels, or relays, of the variant accepted by a
+class of commands, in ascending order
+*/
+func (v Variant) ValidChannels(class CommandClass) []int {
+	return slices.Clone(v.Channels[class])
+}
+
+/*
+Iterates over the channels, or relays, of the variant accepted
+by a class of commands
+*/
+func (v Variant) ChannelsOf(class CommandClass) iter.Seq[int] {
+	return slices.Values(v.Channels[class])
+}
+
+/*
+Returns the number of pressures returned by PRZ
+*/
+func (v Variant) PressureFields() int {
+	return len(v.Channels[ReadingCommands])
+}
+
+/*
+Returns the number of module slots used by the channels
+*/
+func (v Variant) slots() int {
+	return (slices.Max(v.Channels[ReadingCommands]) + 1) / 2
+}
+
+// Uses a fixed hardware variant instead of the 6-channel one, e.g.
+// ThreeChannelVariant, which is never detected from the PRZ reply
+func WithVariant(variant Variant) Option {
+	return func(m *MKS937B) {
+		m.variant = &variant
+		m.variantFixed = true
+	}
+}
+
+/*
+Returns the hardware variant of the device: the one set with
+WithVariant or detected, and the 6-channel one until then
+*/
+func (m *MKS937B) Variant() Variant {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.variant == nil {
+		return SixChannelVariant
+	}
+	return *m.variant
+}
+
+/*
+Detects the hardware variant from the number of pressures
+returned by PRZ and uses it from then on. Only the 6-channel
+variant is detected, other counts return ErrUnexpectedReply
+*/
+func (m *MKS937B) DetectVariant() (Variant, error) {
+	response, err := m.Query("PRZ")
+	if err != nil {
+		return Variant{}, err
+	}
+	variant, ok := variantsByFields[len(strings.Fields(response))]
+	if !ok {
+		return Variant{}, NewErrUnexpectedReply("PRZ", response)
+	}
+	m.mutex.Lock()
+	m.variant = &variant
+	m.mutex.Unlock()
+	return variant, nil
+}
+
+/*
+Returns an error unless the channel, or relay, is accepted by
+a class of commands on the variant of the device
+*/
+func (m *MKS937B) checkChannel(class CommandClass, channel int) error {
+	valid := m.Variant().Channels[class]
+	if slices.Contains(valid, channel) {
+		return nil
+	}
+	switch class {
+	case ControlCommands:
+		return NewErrInvalidChannelControl(slices.Clone(valid), channel)
+	case RelayCommands:
+		return NewErrInvalidRelay(valid[len(valid)-1], channel)
+	}
+	return NewErrInvalidChannel(valid[0], valid[len(valid)-1], channel)
+}
@@ -0,0 +1,260 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type WatchdogEventKind int
+
+const (
+	WatchdogBusDead WatchdogEventKind = iota
+	WatchdogBusRecovered
+	WatchdogGaugeFault
+	WatchdogGaugeRecovered
+	WatchdogIdentityChanged
+	WatchdogSafeState
+)
+
+type WatchdogEvent struct {
+	Kind    WatchdogEventKind
+	Time    time.Time
+	Device  Metadata // Metadata of the device
+	Channel int      // Channel of gauge events, 0 for bus events
+	Label   string   // Label of the channel of gauge events
+	Gauge   Gauge    // Facility gauge of the channel of gauge events
+	Status  string   // Reading status of gauge events
+	Err     error    // Last communication error of bus events, the ErrIdentityChanged or the SafeState error
+}
+
+var gaugeFaultStatus = []string{
+	stringResponse["MISCONN"],
+	stringResponse["NOGAUGE"],
+	stringResponse["LowEmis"],
+}
+
+/*
+Watchdog that periodically reads all channels and raises an
+event when the device stops answering for longer than the
+window (bus dead), or when the device answers but a sensor
+reports a connection or emission fault (gauge fault).
+
+The identity of the controller is verified when the watchdog
+starts and after every bus recovery, raising an identity
+changed event if another controller answers at the address.
+
+Actions on the device are opt-in: restoring the registered
+volatile settings after the identity is verified, checking the
+calibration due dates at every probe, and bringing the device
+to a safe state, with the control modes set to OFF, when the
+bus is dead or a gauge faults. A safe state raises a safe state
+event carrying its error, if any. On a dead bus its commands
+are still attempted, since only the controller may be silent
+*/
+type Watchdog struct {
+	Device   *MKS937B
+	Window   time.Duration // Silence tolerated before bus dead
+	Interval time.Duration // Time between probes
+	OnEvent  func(event WatchdogEvent)
+
+	RestoreSession        bool // Calls RestoreSession after the identity is verified
+	CheckCalibrations     bool // Calls CheckCalibrations at every probe
+	SafeStateOnBusDead    bool // Calls SafeState when the bus is dead
+	SafeStateOnGaugeFault bool // Calls SafeState when a gauge faults
+
+	lastAnswer time.Time
+	busDead    bool
+	verified   bool // Identity verified since start or last recovery
+	faults     map[int]bool
+	stop       chan struct{}
+	done       chan struct{}
+	mutex      sync.Mutex
+}
+
+/*
+Creates a new watchdog that probes the device every second
+*/
+func NewWatchdog(device *MKS937B, window time.Duration) *Watchdog {
+	return &Watchdog{
+		Device:   device,
+		Window:   window,
+		Interval: time.Second,
+	}
+}
+
+/*
+Starts the watchdog in background. It is stopped when the
+device is closed
+*/
+func (w *Watchdog) Start() {
+	w.Device.attach(w)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stop != nil {
+		return
+	}
+	w.lastAnswer = time.Now()
+	w.busDead = false
+	w.verified = false
+	w.faults = make(map[int]bool)
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+}
+
+/*
+Stops the watchdog and waits for the background routine to end
+*/
+func (w *Watchdog) Stop() {
+	w.mutex.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mutex.Unlock()
+
+	w.Device.detach(w)
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+/*
+Returns true if the device is currently considered silent
+*/
+func (w *Watchdog) BusDead() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.busDead
+}
+
+/*
+Probes the device once and raises the events due
+*/
+func (w *Watchdog) probe(now time.Time) {
+	readings, err := w.Device.GetPressures()
+
+	w.mutex.Lock()
+	var events []WatchdogEvent
+	if err != nil {
+		if !w.busDead && now.Sub(w.lastAnswer) >= w.Window {
+			w.busDead = true
+			events = append(events, WatchdogEvent{Kind: WatchdogBusDead, Time: now, Err: err})
+		}
+	} else {
+		w.lastAnswer = now
+		if w.busDead {
+			w.busDead = false
+			w.verified = false
+			events = append(events, WatchdogEvent{Kind: WatchdogBusRecovered, Time: now})
+		}
+		for idx, reading := range readings {
+			channel := idx + 1
+			fault := slices.Contains(gaugeFaultStatus, reading.Status)
+			if fault == w.faults[channel] {
+				continue
+			}
+			w.faults[channel] = fault
+			kind := WatchdogGaugeRecovered
+			if fault {
+				kind = WatchdogGaugeFault
+			}
+			events = append(events, WatchdogEvent{
+				Kind:    kind,
+				Time:    now,
+				Channel: channel,
+				Label:   reading.Label,
+				Gauge:   Gauge{Name: reading.Gauge, Equipment: reading.Equipment},
+				Status:  reading.Status,
+			})
+		}
+	}
+	verify := err == nil && !w.verified
+	w.mutex.Unlock()
+
+	if verify {
+		err := w.Device.VerifyIdentity()
+		var changed *ErrIdentityChanged
+		if errors.As(err, &changed) {
+			events = append(events, WatchdogEvent{Kind: WatchdogIdentityChanged, Time: now, Err: err})
+		}
+		if err == nil && w.RestoreSession {
+			// A power cycle is only noticed after a bus recovery
+			// when it lasted longer than the window
+			_, err = w.Device.RestoreSession()
+		}
+		if err == nil || changed != nil {
+			w.mutex.Lock()
+			w.verified = true
+			w.mutex.Unlock()
+		}
+	}
+
+	if w.CheckCalibrations {
+		w.Device.CheckCalibrations(now)
+	}
+	if w.safeStateDue(events) {
+		err := w.Device.SafeState(true)
+		events = append(events, WatchdogEvent{Kind: WatchdogSafeState, Time: now, Err: err})
+	}
+	for _, event := range events {
+		switch event.Kind {
+		case WatchdogBusDead:
+			w.Device.raiseAlarm(0, fmt.Sprintf("device silent: %v", event.Err))
+		case WatchdogGaugeFault:
+			w.Device.raiseAlarm(event.Channel, event.Status)
+		}
+	}
+	if w.OnEvent != nil {
+		metadata := w.Device.Metadata()
+		for _, event := range events {
+			event.Device = metadata
+			w.OnEvent(event)
+		}
+	}
+}
+
+/*
+Returns true if one of the events requires a safe state
+*/
+func (w *Watchdog) safeStateDue(events []WatchdogEvent) bool {
+	for _, event := range events {
+		if event.Kind == WatchdogBusDead && w.SafeStateOnBusDead {
+			return true
+		}
+		if event.Kind == WatchdogGaugeFault && w.SafeStateOnGaugeFault {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Probing loop executed until stop is closed
+*/
+func (w *Watchdog) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			w.probe(now)
+		}
+	}
+}
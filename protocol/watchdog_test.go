@@ -0,0 +1,237 @@
+package protocol_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+// Controller answering PRZ, SN, FV, MT and CP1 and acknowledging
+// every setting, which can go silent, lose CP1 as in a power cycle,
+// or be swapped for another one
+type fakeController struct {
+	mutex     sync.Mutex
+	connected bool
+	silent    bool
+	serial    string
+	pressures string
+	modules   string
+	power     string
+	control   string
+	pending   string
+}
+
+func (c *fakeController) Connect() error    { c.connected = true; return nil }
+func (c *fakeController) Disconnect() error { c.connected = false; return nil }
+func (c *fakeController) IsConnected() bool { return c.connected }
+
+func (c *fakeController) Read(uint) ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeController) Write(message []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.silent {
+		return errors.New("read timeout")
+	}
+	c.pending = strings.TrimSuffix(strings.TrimPrefix(string(message), "@001"), ";FF")
+	return nil
+}
+
+func (c *fakeController) ReadUntil(string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	reply := "NAK"
+	switch command := c.pending; {
+	case strings.Contains(command, "!"):
+		command, reply, _ = strings.Cut(command, "!")
+		switch command {
+		case "CP1":
+			c.power = reply
+		case "CTL1":
+			c.control = reply
+		}
+	case command == "MT?":
+		reply = c.modules
+	case command == "CP1?":
+		reply = c.power
+	case command == "PRZ?":
+		reply = c.pressures
+	case command == "SN?":
+		reply = c.serial
+	case strings.HasPrefix(command, "FV"):
+		reply = "1.0"
+	}
+	return []byte("@001ACK" + reply + ";FF"), nil
+}
+
+func (c *fakeController) powered() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.power == "ON"
+}
+
+func (c *fakeController) set(change func(c *fakeController)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	change(c)
+}
+
+func TestWatchdog(t *testing.T) {
+	const healthy = "1.00E-07 1.00E-03 1.00E-07 1.00E-03 1.00E-07 1.00E-03"
+	controller := &fakeController{serial: "1000", pressures: healthy, power: "ON"}
+	device := &protocol.MKS937B{Communication: controller, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	device.RememberSettings(protocol.PowerSetting(1, true))
+
+	events := make(chan protocol.WatchdogEvent, 16)
+	watchdog := protocol.NewWatchdog(device, 20*time.Millisecond)
+	watchdog.Interval = 5 * time.Millisecond
+	watchdog.RestoreSession = true
+	watchdog.OnEvent = func(event protocol.WatchdogEvent) { events <- event }
+	expect := func(kind protocol.WatchdogEventKind) protocol.WatchdogEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			if event.Kind != kind {
+				t.Fatalf("expected event %v, got %v", kind, event.Kind)
+			}
+			return event
+		case <-time.After(time.Second):
+			t.Fatalf("expected event %v", kind)
+		}
+		return protocol.WatchdogEvent{}
+	}
+	watchdog.Start()
+	defer watchdog.Stop()
+
+	// Gauge fault while the device answers
+	controller.set(func(c *fakeController) { c.pressures = "1.00E-07 MISCONN 1.00E-07 1.00E-03 1.00E-07 1.00E-03" })
+	if event := expect(protocol.WatchdogGaugeFault); event.Channel != 2 {
+		t.Errorf("expected a fault on channel 2, got %d", event.Channel)
+	}
+	controller.set(func(c *fakeController) { c.pressures = healthy })
+	expect(protocol.WatchdogGaugeRecovered)
+
+	// Power cycle longer than the window: the session is restored
+	controller.set(func(c *fakeController) { c.silent, c.power = true, "OFF" })
+	expect(protocol.WatchdogBusDead)
+	if !watchdog.BusDead() {
+		t.Error("expected the bus to be dead")
+	}
+	controller.set(func(c *fakeController) { c.silent = false })
+	expect(protocol.WatchdogBusRecovered)
+	deadline := time.Now().Add(time.Second)
+	for !controller.powered() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !controller.powered() {
+		t.Error("expected CP1 to be restored after the power cycle")
+	}
+
+	// Another controller is swapped in during an outage
+	controller.set(func(c *fakeController) { c.silent = true })
+	expect(protocol.WatchdogBusDead)
+	controller.set(func(c *fakeController) { c.silent, c.serial = false, "2000" })
+	expect(protocol.WatchdogBusRecovered)
+	var changed *protocol.ErrIdentityChanged
+	if event := expect(protocol.WatchdogIdentityChanged); !errors.As(event.Err, &changed) {
+		t.Errorf("expected ErrIdentityChanged, got %v", event.Err)
+	}
+}
+
+func TestWatchdogSafeState(t *testing.T) {
+	const healthy = "1.00E-07 1.00E-03 1.00E-07 1.00E-03 1.00E-07 1.00E-03"
+	controller := &fakeController{serial: "1000", pressures: healthy, modules: "CC,PR,NC,NA", power: "ON", control: "AUTO"}
+	device := &protocol.MKS937B{Communication: controller, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	device.RememberSettings(protocol.PowerSetting(1, true))
+
+	events := make(chan protocol.WatchdogEvent, 16)
+	watchdog := protocol.NewWatchdog(device, 20*time.Millisecond)
+	watchdog.Interval = 5 * time.Millisecond
+	watchdog.SafeStateOnGaugeFault = true
+	watchdog.OnEvent = func(event protocol.WatchdogEvent) { events <- event }
+	next := func() protocol.WatchdogEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("expected an event")
+		}
+		return protocol.WatchdogEvent{}
+	}
+	watchdog.Start()
+	defer watchdog.Stop()
+
+	// Without RestoreSession, a power cycle is not repaired
+	controller.set(func(c *fakeController) { c.silent, c.power = true, "OFF" })
+	if event := next(); event.Kind != protocol.WatchdogBusDead {
+		t.Fatalf("expected a bus dead event, got %v", event.Kind)
+	}
+	controller.set(func(c *fakeController) { c.silent = false })
+	if event := next(); event.Kind != protocol.WatchdogBusRecovered {
+		t.Fatalf("expected a bus recovered event, got %v", event.Kind)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if controller.powered() {
+		t.Error("expected CP1 to be left OFF without RestoreSession")
+	}
+
+	// A gauge fault turns the ion gauge and its control OFF
+	controller.set(func(c *fakeController) { c.power = "ON" })
+	controller.set(func(c *fakeController) { c.pressures = "1.00E-07 MISCONN 1.00E-07 1.00E-03 1.00E-07 1.00E-03" })
+	if event := next(); event.Kind != protocol.WatchdogGaugeFault {
+		t.Fatalf("expected a gauge fault event, got %v", event.Kind)
+	}
+	if event := next(); event.Kind != protocol.WatchdogSafeState || event.Err != nil {
+		t.Fatalf("expected a safe state event without error, got %v, %v", event.Kind, event.Err)
+	}
+	controller.set(func(c *fakeController) {
+		if c.power != "OFF" || c.control != "OFF" {
+			t.Errorf("expected CP1 and CTL1 OFF, got %s and %s", c.power, c.control)
+		}
+	})
+}
+
+func TestWatchdogSafeStateOnBusDead(t *testing.T) {
+	controller := &fakeController{serial: "1000", pressures: "1.00E-07 1.00E-03 1.00E-07 1.00E-03 1.00E-07 1.00E-03"}
+	device := &protocol.MKS937B{Communication: controller, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan protocol.WatchdogEvent, 16)
+	watchdog := protocol.NewWatchdog(device, 20*time.Millisecond)
+	watchdog.Interval = 5 * time.Millisecond
+	watchdog.SafeStateOnBusDead = true
+	watchdog.OnEvent = func(event protocol.WatchdogEvent) { events <- event }
+	watchdog.Start()
+	defer watchdog.Stop()
+
+	// The safe state is attempted on the silent controller and fails
+	controller.set(func(c *fakeController) { c.silent = true })
+	for _, kind := range []protocol.WatchdogEventKind{protocol.WatchdogBusDead, protocol.WatchdogSafeState} {
+		select {
+		case event := <-events:
+			if event.Kind != kind {
+				t.Fatalf("expected event %v, got %v", kind, event.Kind)
+			}
+			if kind == protocol.WatchdogSafeState && event.Err == nil {
+				t.Error("expected the safe state to fail on a silent controller")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected event %v", kind)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package protocol
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+Loads a YAML file into a configuration struct, e.g. a
+RelayConfig list or a Snapshot
+*/
+func LoadYAML(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+/*
+Saves a configuration struct to a YAML file
+*/
+func SaveYAML(path string, in any) error {
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,164 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package sequencer
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+HV ON and OFF times of a conditioning cycle while the pressure
+is below a bound
+*/
+type ConditioningDwell struct {
+	Below float64       // Pressure bound, in the device unit
+	On    time.Duration // HV ON time of the cycle
+	Off   time.Duration // HV OFF time of the cycle
+}
+
+/*
+Conditioning of a contaminated Cold Cathode: the HV is cycled
+ON and OFF, with the ON and OFF times selected by the pressure
+read at the end of each ON period. The manual gives no such
+procedure, so the schedule must come from the gauge vendor or
+the facility's own practice
+*/
+type ColdCathodeConditioning struct {
+	Cycles      int                 // Number of HV ON/OFF cycles
+	Dwells      []ConditioningDwell // In ascending Below order, the last one is used above every bound
+	MaxPressure float64             // Fails the conditioning above this pressure, 0 for none
+	Interval    time.Duration       // Time between pressure checks while the HV is ON, one second by default
+	Logger      *slog.Logger        // Progress of each cycle, nothing is logged when nil
+}
+
+/*
+Verifies that the schedule has cycles and dwells with an ON
+time
+*/
+func (c ColdCathodeConditioning) validate() error {
+	if c.Cycles < 1 {
+		return fmt.Errorf("cold cathode conditioning needs at least one cycle, got %d", c.Cycles)
+	}
+	if len(c.Dwells) == 0 {
+		return fmt.Errorf("cold cathode conditioning without dwells")
+	}
+	for _, dwell := range c.Dwells {
+		if dwell.On <= 0 || dwell.Off < 0 {
+			return fmt.Errorf("cold cathode conditioning dwell below %.2E must have a positive ON time", dwell.Below)
+		}
+	}
+	return nil
+}
+
+/*
+Returns the dwell of a pressure, the last one when the pressure
+is unknown or above every bound
+*/
+func (c ColdCathodeConditioning) dwell(pressure float64, known bool) ConditioningDwell {
+	if known {
+		for _, dwell := range c.Dwells {
+			if pressure < dwell.Below {
+				return dwell
+			}
+		}
+	}
+	return c.Dwells[len(c.Dwells)-1]
+}
+
+/*
+Step that conditions the Cold Cathode of a channel. The first
+cycle uses the last dwell, and the HV is left ON once every
+cycle is done. The pressure is checked against MaxPressure
+throughout the ON periods, and the HV is turned OFF when the
+step fails or the recipe is aborted
+*/
+func ConditionColdCathode(channel int, conditioning ColdCathodeConditioning) Step {
+	return Step{
+		Name: fmt.Sprintf("Condition channel %d cold cathode, %d cycles", channel, conditioning.Cycles),
+		Run: func(run *Run) error {
+			if err := conditioning.validate(); err != nil {
+				return err
+			}
+			err := conditioning.run(run, channel)
+			if err != nil {
+				run.Device.SetPowerStatus(channel, false)
+			}
+			return err
+		},
+	}
+}
+
+/*
+Runs the HV cycles of a channel
+*/
+func (c ColdCathodeConditioning) run(run *Run, channel int) error {
+	dwell := c.dwell(0, false)
+	for cycle := 1; cycle <= c.Cycles; cycle++ {
+		if err := run.Device.SetPowerStatus(channel, true); err != nil {
+			return err
+		}
+		reading, err := c.dwellOn(run, channel, dwell.On)
+		if err != nil {
+			return err
+		}
+		known := reading.Status == "OK"
+		dwell = c.dwell(reading.Value, known)
+		if c.Logger != nil {
+			c.Logger.Info("cold cathode conditioning",
+				"channel", channel, "cycle", cycle, "cycles", c.Cycles,
+				"pressure", reading.Value, "status", reading.Status,
+				"on", dwell.On, "off", dwell.Off,
+			)
+		}
+		if cycle == c.Cycles {
+			break
+		}
+		if err := run.Device.SetPowerStatus(channel, false); err != nil {
+			return err
+		}
+		if err := run.Wait(dwell.Off); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Keeps the HV of a channel ON for a duration, reading the
+pressure at every interval and at the end. Fails as soon as a
+reading is above MaxPressure, and returns the last reading
+*/
+func (c ColdCathodeConditioning) dwellOn(run *Run, channel int, duration time.Duration) (protocol.PressureReading, error) {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		wait := min(interval, duration)
+		if err := run.Wait(wait); err != nil {
+			return protocol.PressureReading{}, err
+		}
+		duration -= wait
+		reading, err := run.Device.GetPressure(channel)
+		if err != nil {
+			return reading, err
+		}
+		if reading.Status == "OK" && c.MaxPressure > 0 && reading.Value > c.MaxPressure {
+			return reading, fmt.Errorf(
+				"channel %d pressure %.2E is above the conditioning limit %.2E",
+				channel, reading.Value, c.MaxPressure,
+			)
+		}
+		if duration <= 0 {
+			return reading, nil
+		}
+	}
+}
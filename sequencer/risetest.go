@@ -0,0 +1,170 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package sequencer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/analysis"
+)
+
+/*
+Measurement phase of a pressure-rise (leak rate) test: the
+base pressure is sampled while pumped, the volume is isolated,
+the rise is sampled for the acquisition period and dP/dt is
+fitted. Valves are driven by the Isolate and Restore actions
+of the application, Restore running whether the test succeeds,
+fails or is aborted
+*/
+type RiseTest struct {
+	Channel     int
+	Volume      float64       // Isolated volume in liters
+	Unit        string        // Pressure unit of the readings, for the result
+	Baseline    time.Duration // Sampling of the base pressure before isolation
+	Acquisition time.Duration // Sampling of the rise
+	Interval    time.Duration // Time between samples, one second by default
+	Isolate     func(run *Run) error
+	Restore     func(run *Run) error
+
+	Result RiseTestResult // Filled as the steps run
+}
+
+type RiseTestResult struct {
+	Channel       int               `json:"channel"`
+	Volume        float64           `json:"volume"`
+	Unit          string            `json:"unit"`
+	Start         time.Time         `json:"start"`
+	End           time.Time         `json:"end"`
+	BasePressure  float64           `json:"base_pressure"`  // Mean of the baseline samples
+	BaseDeviation float64           `json:"base_deviation"` // Standard deviation of the baseline samples
+	BaselineCount int               `json:"baseline_count"` // Number of baseline samples
+	RiseSamples   []analysis.Sample `json:"rise_samples"`   // Samples of the acquisition
+	Fit           analysis.LeakRate `json:"fit"`            // Linear fit of the rise
+}
+
+/*
+Returns the steps of the test: baseline, isolation, acquisition
+and fit, with the restore step run after them or when one of
+them fails or is aborted
+*/
+func (t *RiseTest) Steps() []Step {
+	restore := Action("Restore pumping", func(run *Run) error {
+		if t.Restore == nil {
+			return nil
+		}
+		return t.Restore(run)
+	})
+	steps := []Step{
+		Action(fmt.Sprintf("Sample channel %d base pressure for %s", t.Channel, t.Baseline), t.baseline),
+		Action("Isolate volume", func(run *Run) error {
+			if t.Isolate == nil {
+				return nil
+			}
+			return t.Isolate(run)
+		}),
+		Action(fmt.Sprintf("Sample channel %d pressure rise for %s", t.Channel, t.Acquisition), t.acquire),
+		Action("Fit dP/dt", t.fit),
+	}
+	for idx := range steps {
+		steps[idx].OnFailure = []Step{restore}
+	}
+	return append(steps, restore)
+}
+
+/*
+Samples the pressure of the channel at the start and at every
+interval of a period, honoring pause and abort. The interval
+is one second by default
+*/
+func (t *RiseTest) sample(run *Run, period time.Duration) ([]analysis.Sample, error) {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	var samples []analysis.Sample
+	for idx := range int(period/interval) + 1 {
+		if idx > 0 {
+			if err := run.Wait(interval); err != nil {
+				return samples, err
+			}
+		}
+		reading, err := run.Device.GetPressure(t.Channel)
+		if err != nil {
+			return samples, err
+		}
+		if reading.Status != "OK" {
+			return samples, fmt.Errorf("channel %d reading is unavailable: %s", t.Channel, reading.Status)
+		}
+		samples = append(samples, analysis.Sample{Time: run.Now(), Pressure: reading.Value})
+	}
+	return samples, nil
+}
+
+func (t *RiseTest) baseline(run *Run) error {
+	t.Result = RiseTestResult{Channel: t.Channel, Volume: t.Volume, Unit: t.Unit, Start: run.Now()}
+	samples, err := t.sample(run, t.Baseline)
+	if err != nil {
+		return err
+	}
+	var sum, squares float64
+	for _, sample := range samples {
+		sum += sample.Pressure
+	}
+	mean := sum / float64(len(samples))
+	for _, sample := range samples {
+		squares += (sample.Pressure - mean) * (sample.Pressure - mean)
+	}
+	t.Result.BasePressure = mean
+	t.Result.BaselineCount = len(samples)
+	if len(samples) > 1 {
+		t.Result.BaseDeviation = math.Sqrt(squares / float64(len(samples)-1))
+	}
+	return nil
+}
+
+func (t *RiseTest) acquire(run *Run) error {
+	samples, err := t.sample(run, t.Acquisition)
+	t.Result.RiseSamples = samples
+	t.Result.End = run.Now()
+	return err
+}
+
+func (t *RiseTest) fit(run *Run) error {
+	fit, err := analysis.RateOfRise(t.Result.RiseSamples, t.Volume, t.Unit)
+	t.Result.Fit = fit
+	return err
+}
+
+/*
+Returns the result as indented JSON
+*/
+func (r RiseTestResult) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+/*
+Returns a Markdown report of the result, meant for test
+records
+*/
+func (r RiseTestResult) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Pressure-Rise Test, Channel %d\n\n", r.Channel)
+	fmt.Fprintf(&b, "| Field | Value |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Start | %s |\n", r.Start.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| End | %s |\n", r.End.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Volume | %g L |\n", r.Volume)
+	fmt.Fprintf(&b, "| Base pressure | %.3E ± %.1E %s (%d samples) |\n", r.BasePressure, r.BaseDeviation, r.Unit, r.BaselineCount)
+	fmt.Fprintf(&b, "| Rise duration | %s (%d samples) |\n", r.Fit.Duration, r.Fit.Samples)
+	fmt.Fprintf(&b, "| dP/dt | %.3E ± %.1E %s/s |\n", r.Fit.RiseRate, r.Fit.RiseRateError, r.Unit)
+	fmt.Fprintf(&b, "| Leak rate | %.3E ± %.1E %s·L/s |\n", r.Fit.LeakRate, r.Fit.LeakRateError, r.Unit)
+	fmt.Fprintf(&b, "| R² | %.4f |\n", r.Fit.R2)
+	return b.String()
+}
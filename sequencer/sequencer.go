@@ -0,0 +1,391 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+var (
+	ErrAborted     = errors.New("recipe aborted")
+	ErrRunning     = errors.New("recipe is already running")
+	ErrStepTimeout = errors.New("step timed out")
+)
+
+type EventKind int
+
+const (
+	StepStarted EventKind = iota
+	StepCompleted
+	StepFailed
+	Paused
+	Resumed
+	Aborted
+	Completed
+	StepSkipped // Condition of the step was false
+)
+
+type Event struct {
+	Kind  EventKind
+	Time  time.Time
+	Step  int    // Index of the step, -1 for recipe events
+	Name  string // Name of the step
+	Total int    // Number of steps of the recipe
+	Err   error
+}
+
+/*
+Source of time of a runner, replaced e.g. in tests to run the
+steps without waiting
+*/
+type Clock interface {
+	Now() time.Time
+	// Returns a channel receiving the time once the duration has
+	// elapsed, and a function releasing the timer
+	Timer(duration time.Duration) (<-chan time.Time, func())
+}
+
+/*
+Clock of the system
+*/
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Timer(duration time.Duration) (<-chan time.Time, func()) {
+	timer := time.NewTimer(duration)
+	return timer.C, func() { timer.Stop() }
+}
+
+/*
+Step of a procedure. Only Run is required: a step without a
+Condition always runs, and a step without Timeout runs until it
+returns. When a step fails, its OnFailure steps run in order,
+e.g. to power the gauges off, and the procedure stops with the
+error of the step unless ContinueOnFailure is set and the
+OnFailure steps succeeded. They also run when the recipe is
+aborted during the step or before it starts, with a context
+that is not cancelled and regardless of pause, and the
+procedure stops with ErrAborted
+*/
+type Step struct {
+	Name              string
+	Run               func(run *Run) error
+	Condition         func(run *Run) (bool, error) // Runs the step only when true
+	Timeout           time.Duration                // Fails the step with ErrStepTimeout, 0 for none
+	OnFailure         []Step                       // Steps run when the step fails
+	ContinueOnFailure bool                         // Continues with the next step once OnFailure succeeded
+}
+
+/*
+Execution state handed to the steps. Steps must use Wait
+for any delay so pause and abort are honored
+*/
+type Run struct {
+	Device  *protocol.MKS937B
+	runner  *Runner
+	ctx     context.Context
+	cleanup bool // Failure branch of an aborted recipe, not paused
+}
+
+/*
+Executes a list of steps against a device, such as a chamber
+bake-out or a pump-down procedure, with pause, resume and abort
+support. A Runner literal is ready to use, as one created by New
+*/
+type Runner struct {
+	Device  *protocol.MKS937B
+	Steps   []Step
+	OnEvent func(event Event)
+	Clock   Clock // System clock when nil
+
+	running bool
+	paused  bool
+	changed chan struct{} // Closed whenever paused changes, created on first use
+	cancel  context.CancelFunc
+	mutex   sync.Mutex
+}
+
+/*
+Creates a new runner for a recipe
+*/
+func New(device *protocol.MKS937B, steps ...Step) *Runner {
+	return &Runner{Device: device, Steps: steps}
+}
+
+/*
+Runs all steps in order, blocking until the recipe completes,
+a step fails, or it is aborted
+*/
+func (r *Runner) Run(ctx context.Context) error {
+	r.mutex.Lock()
+	if r.running {
+		r.mutex.Unlock()
+		return ErrRunning
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r.running, r.paused, r.cancel = true, false, cancel
+	r.changedChannel()
+	r.mutex.Unlock()
+
+	defer func() {
+		cancel()
+		r.mutex.Lock()
+		r.running, r.cancel = false, nil
+		r.mutex.Unlock()
+	}()
+
+	run := &Run{Device: r.Device, runner: r, ctx: ctx}
+	for idx, step := range r.Steps {
+		if err := r.execute(run, idx, step); err != nil {
+			return err
+		}
+	}
+	r.emit(Event{Kind: Completed, Step: -1})
+	return nil
+}
+
+/*
+Runs a step once resumed, its condition and timeout honored,
+and its failure branch when it fails. Returns the error that
+stops the recipe
+*/
+func (r *Runner) execute(run *Run, idx int, step Step) error {
+	if err := run.waitResumed(); err != nil {
+		return r.fail(run, idx, step, err)
+	}
+	if step.Condition != nil {
+		ok, err := step.Condition(run)
+		if err != nil {
+			return r.fail(run, idx, step, err)
+		}
+		if !ok {
+			r.emit(Event{Kind: StepSkipped, Step: idx, Name: step.Name})
+			return nil
+		}
+	}
+	r.emit(Event{Kind: StepStarted, Step: idx, Name: step.Name})
+
+	stepRun := run
+	if step.Timeout > 0 {
+		ctx, cancel := context.WithCancelCause(run.ctx)
+		defer cancel(nil)
+		expired, stop := r.clock().Timer(step.Timeout)
+		defer stop()
+		go func() {
+			select {
+			case <-expired:
+				cancel(ErrStepTimeout)
+			case <-ctx.Done():
+			}
+		}()
+		stepRun = &Run{Device: run.Device, runner: r, ctx: ctx, cleanup: run.cleanup}
+	}
+	if err := step.Run(stepRun); err != nil {
+		return r.fail(run, idx, step, err)
+	}
+	r.emit(Event{Kind: StepCompleted, Step: idx, Name: step.Name})
+	return nil
+}
+
+/*
+Reports a failed or aborted step and runs its failure branch.
+The branch of an aborted step runs with a context that is not
+cancelled, so cleanup such as powering the gauges off is done
+*/
+func (r *Runner) fail(run *Run, idx int, step Step, err error) error {
+	if run.ctx.Err() != nil {
+		r.emit(Event{Kind: Aborted, Step: idx, Name: step.Name})
+		cleanup := &Run{Device: run.Device, runner: r, ctx: context.WithoutCancel(run.ctx), cleanup: true}
+		for _, branch := range step.OnFailure {
+			if branchErr := r.execute(cleanup, idx, branch); branchErr != nil {
+				return errors.Join(ErrAborted, branchErr)
+			}
+		}
+		return ErrAborted
+	}
+	r.emit(Event{Kind: StepFailed, Step: idx, Name: step.Name, Err: err})
+	for _, branch := range step.OnFailure {
+		if branchErr := r.execute(run, idx, branch); branchErr != nil {
+			return errors.Join(err, branchErr)
+		}
+	}
+	if step.ContinueOnFailure {
+		return nil
+	}
+	return err
+}
+
+/*
+Pauses the recipe. The current wait is suspended and no new
+step is started until Resume is called
+*/
+func (r *Runner) Pause() {
+	if r.setPaused(true) {
+		r.emit(Event{Kind: Paused, Step: -1})
+	}
+}
+
+/*
+Resumes a paused recipe
+*/
+func (r *Runner) Resume() {
+	if r.setPaused(false) {
+		r.emit(Event{Kind: Resumed, Step: -1})
+	}
+}
+
+/*
+Aborts the running recipe
+*/
+func (r *Runner) Abort() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+/*
+Changes the paused state and returns true if it changed
+*/
+func (r *Runner) setPaused(paused bool) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.running || r.paused == paused {
+		return false
+	}
+	r.paused = paused
+	close(r.changedChannel())
+	r.changed = make(chan struct{})
+	return true
+}
+
+/*
+Returns the paused state and a channel closed when it changes
+*/
+func (r *Runner) state() (bool, chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.paused, r.changedChannel()
+}
+
+/*
+Returns the channel closed when the paused state changes,
+creating it if needed. The caller must hold the mutex
+*/
+func (r *Runner) changedChannel() chan struct{} {
+	if r.changed == nil {
+		r.changed = make(chan struct{})
+	}
+	return r.changed
+}
+
+/*
+Returns the clock of the runner
+*/
+func (r *Runner) clock() Clock {
+	if r.Clock == nil {
+		return systemClock{}
+	}
+	return r.Clock
+}
+
+/*
+Sends an event to the listener, if any
+*/
+func (r *Runner) emit(event Event) {
+	if r.OnEvent == nil {
+		return
+	}
+	event.Time = r.clock().Now()
+	event.Total = len(r.Steps)
+	r.OnEvent(event)
+}
+
+/*
+Returns the context of the run, cancelled on abort
+*/
+func (run *Run) Context() context.Context {
+	return run.ctx
+}
+
+/*
+Returns the current time of the runner clock
+*/
+func (run *Run) Now() time.Time {
+	return run.runner.clock().Now()
+}
+
+/*
+Waits for a duration of unpaused time. Returns ErrAborted if
+the recipe is aborted meanwhile, or ErrStepTimeout if the step
+times out
+*/
+func (run *Run) Wait(duration time.Duration) error {
+	for duration > 0 {
+		if err := run.waitResumed(); err != nil {
+			return err
+		}
+		_, changed := run.runner.state()
+		clock := run.runner.clock()
+		start := clock.Now()
+		expired, stop := clock.Timer(duration)
+		select {
+		case <-run.ctx.Done():
+			stop()
+			return run.interrupted()
+		case <-expired:
+			return nil
+		case <-changed:
+			stop()
+			duration -= clock.Now().Sub(start)
+		}
+	}
+	return nil
+}
+
+/*
+Blocks while the recipe is paused, unless the run is the
+failure branch of an aborted recipe
+*/
+func (run *Run) waitResumed() error {
+	for {
+		paused, changed := run.runner.state()
+		if !paused || run.cleanup {
+			if run.ctx.Err() != nil {
+				return run.interrupted()
+			}
+			return nil
+		}
+		select {
+		case <-run.ctx.Done():
+			return run.interrupted()
+		case <-changed:
+		}
+	}
+}
+
+/*
+Returns ErrStepTimeout if the step timed out, or ErrAborted
+*/
+func (run *Run) interrupted() error {
+	if errors.Is(context.Cause(run.ctx), ErrStepTimeout) {
+		return ErrStepTimeout
+	}
+	return ErrAborted
+}
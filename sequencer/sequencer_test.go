@@ -0,0 +1,379 @@
+package sequencer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/framelog"
+	"github.com/devicehub-go/mks-937b/protocol"
+	"github.com/devicehub-go/mks-937b/sequencer"
+)
+
+func TestPauseResume(t *testing.T) {
+	var executed []string
+	record := func(name string) sequencer.Step {
+		return sequencer.Action(name, func(run *sequencer.Run) error {
+			executed = append(executed, name)
+			return nil
+		})
+	}
+
+	clock := newFakeClock(false)
+	runner := sequencer.New(nil, record("first"), sequencer.Wait(100*time.Millisecond), record("second"))
+	runner.Clock = clock
+	done := make(chan error, 1)
+	go func() { done <- runner.Run(context.Background()) }()
+
+	clock.blockUntil(1)
+	clock.advance(20 * time.Millisecond)
+	runner.Pause()
+	clock.blockUntil(0)
+	clock.advance(time.Hour)
+	runner.Resume()
+
+	// The paused hour is not counted in the wait
+	clock.blockUntil(1)
+	clock.advance(79 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("expected the wait to last 80ms more after resume")
+	default:
+	}
+	clock.advance(time.Millisecond)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(executed) != 2 {
+		t.Errorf("expected 2 executed steps, got %v", executed)
+	}
+}
+
+func TestRunnerLiteral(t *testing.T) {
+	clock := newFakeClock(false)
+	runner := &sequencer.Runner{Steps: []sequencer.Step{sequencer.Wait(time.Hour)}, Clock: clock}
+	runner.Pause()
+	runner.Resume()
+	go func() {
+		clock.blockUntil(1)
+		runner.Pause()
+		clock.blockUntil(0)
+		runner.Resume()
+		clock.blockUntil(1)
+		runner.Abort()
+	}()
+
+	if err := runner.Run(context.Background()); !errors.Is(err, sequencer.ErrAborted) {
+		t.Errorf("expected ErrAborted, got %v", err)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	clock := newFakeClock(false)
+	runner := sequencer.New(nil, sequencer.Wait(time.Hour))
+	runner.Clock = clock
+	go func() {
+		clock.blockUntil(1)
+		runner.Abort()
+	}()
+
+	if err := runner.Run(context.Background()); !errors.Is(err, sequencer.ErrAborted) {
+		t.Errorf("expected ErrAborted, got %v", err)
+	}
+}
+
+func TestConditionTimeoutAndFailureBranch(t *testing.T) {
+	var executed []string
+	record := func(name string) sequencer.Step {
+		return sequencer.Action(name, func(run *sequencer.Run) error {
+			executed = append(executed, name)
+			return nil
+		})
+	}
+	skipped := record("skipped")
+	skipped.Condition = func(run *sequencer.Run) (bool, error) { return false, nil }
+	crossover := sequencer.Wait(time.Hour)
+	crossover.Timeout = 20 * time.Millisecond
+	crossover.OnFailure = []sequencer.Step{record("recover")}
+	crossover.ContinueOnFailure = true
+	critical := sequencer.Wait(time.Hour)
+	critical.Timeout = 20 * time.Millisecond
+
+	var kinds []sequencer.EventKind
+	clock := newFakeClock(false)
+	runner := sequencer.New(nil, skipped, crossover, record("after"), critical, record("never"))
+	runner.Clock = clock
+	runner.OnEvent = func(event sequencer.Event) { kinds = append(kinds, event.Kind) }
+	go func() {
+		// The timeout and the wait of each step
+		for range 2 {
+			clock.blockUntil(2)
+			clock.advance(20 * time.Millisecond)
+		}
+	}()
+
+	if err := runner.Run(context.Background()); !errors.Is(err, sequencer.ErrStepTimeout) {
+		t.Fatalf("expected ErrStepTimeout, got %v", err)
+	}
+	if len(executed) != 2 || executed[0] != "recover" || executed[1] != "after" {
+		t.Errorf("unexpected executed steps %v", executed)
+	}
+	if kinds[0] != sequencer.StepSkipped || kinds[len(kinds)-1] != sequencer.StepFailed {
+		t.Errorf("unexpected events %v", kinds)
+	}
+}
+
+func TestConditionColdCathode(t *testing.T) {
+	device := replayDevice(t,
+		"@001CP1!ON;FF", "@001ACKON;FF",
+		"@001PR1?;FF", "@001ACK5.00E-07;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+		"@001CP1!ON;FF", "@001ACKON;FF",
+		"@001PR1?;FF", "@001ACK2.00E-03;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+	)
+	conditioning := sequencer.ColdCathodeConditioning{
+		Cycles: 3,
+		Dwells: []sequencer.ConditioningDwell{
+			{Below: 1e-6, On: time.Millisecond, Off: time.Millisecond},
+			{Below: 1e-3, On: 2 * time.Millisecond, Off: 2 * time.Millisecond},
+		},
+		MaxPressure: 1e-3,
+	}
+
+	runner := sequencer.New(device, sequencer.ConditionColdCathode(1, conditioning))
+	runner.Clock = newFakeClock(true)
+	if err := runner.Run(context.Background()); err == nil {
+		t.Fatal("expected the conditioning to stop above the pressure limit")
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("HV was not turned OFF, %d frames left", remaining)
+	}
+}
+
+func TestConditioningPressureWhileOn(t *testing.T) {
+	device := replayDevice(t,
+		"@001CP1!ON;FF", "@001ACKON;FF",
+		"@001PR1?;FF", "@001ACK5.00E-07;FF",
+		"@001PR1?;FF", "@001ACK2.00E-03;FF",
+		"@001CP1!OFF;FF", "@001ACKOFF;FF",
+	)
+	conditioning := sequencer.ColdCathodeConditioning{
+		Cycles:      1,
+		Dwells:      []sequencer.ConditioningDwell{{Below: 1e-3, On: time.Hour, Off: time.Minute}},
+		MaxPressure: 1e-3,
+		Interval:    time.Millisecond,
+	}
+
+	// The burst is caught during the hour ON, not at its end
+	runner := sequencer.New(device, sequencer.ConditionColdCathode(1, conditioning))
+	runner.Clock = newFakeClock(true)
+	if err := runner.Run(context.Background()); err == nil {
+		t.Fatal("expected the conditioning to stop above the pressure limit")
+	}
+	if remaining := device.Communication.(*framelog.Replay).Remaining(); remaining != 0 {
+		t.Errorf("HV was not turned OFF, %d frames left", remaining)
+	}
+
+	// There is no default schedule
+	runner = sequencer.New(device, sequencer.ConditionColdCathode(1, sequencer.ColdCathodeConditioning{}))
+	if err := runner.Run(context.Background()); err == nil {
+		t.Error("expected an error for a conditioning without schedule")
+	}
+}
+
+func TestRiseTest(t *testing.T) {
+	device := replayDevice(t,
+		"@001PR1?;FF", "@001ACK1.00E-06;FF",
+		"@001PR1?;FF", "@001ACK1.00E-06;FF",
+		"@001PR1?;FF", "@001ACK2.00E-06;FF",
+		"@001PR1?;FF", "@001ACK3.00E-06;FF",
+		"@001PR1?;FF", "@001ACK4.00E-06;FF",
+	)
+	var isolated, restored bool
+	test := &sequencer.RiseTest{
+		Channel:     1,
+		Volume:      10,
+		Unit:        "Torr",
+		Baseline:    time.Millisecond,
+		Acquisition: 2 * time.Millisecond,
+		Interval:    time.Millisecond,
+		Isolate:     func(run *sequencer.Run) error { isolated = true; return nil },
+		Restore:     func(run *sequencer.Run) error { restored = true; return nil },
+	}
+
+	runner := sequencer.New(device, test.Steps()...)
+	runner.Clock = newFakeClock(true)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !isolated || !restored {
+		t.Error("expected the volume to be isolated and restored")
+	}
+	if test.Result.BaselineCount != 2 || test.Result.BasePressure != 1e-6 {
+		t.Errorf("unexpected baseline %d samples at %E", test.Result.BaselineCount, test.Result.BasePressure)
+	}
+	if len(test.Result.RiseSamples) != 3 || math.Abs(test.Result.Fit.RiseRate-1e-3)/1e-3 > 1e-9 {
+		t.Errorf("unexpected rise of %d samples at %E", len(test.Result.RiseSamples), test.Result.Fit.RiseRate)
+	}
+	if _, err := test.Result.JSON(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRiseTestCancelled(t *testing.T) {
+	device := replayDevice(t,
+		"@001PR1?;FF", "@001ACK1.00E-06;FF",
+		"@001PR1?;FF", "@001ACK1.00E-06;FF",
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	restored := make(chan error, 1)
+	test := &sequencer.RiseTest{
+		Channel:     1,
+		Acquisition: time.Hour,
+		Interval:    time.Hour,
+		Restore: func(run *sequencer.Run) error {
+			restored <- run.Context().Err()
+			return nil
+		},
+	}
+
+	clock := newFakeClock(false)
+	runner := sequencer.New(device, test.Steps()...)
+	runner.Clock = clock
+	go func() {
+		// Paused and then cancelled while the rise is sampled
+		clock.blockUntil(1)
+		runner.Pause()
+		cancel()
+	}()
+	if err := runner.Run(ctx); !errors.Is(err, sequencer.ErrAborted) {
+		t.Fatalf("expected ErrAborted, got %v", err)
+	}
+	select {
+	case err := <-restored:
+		if err != nil {
+			t.Errorf("expected the restore to run with a live context, got %v", err)
+		}
+	default:
+		t.Error("expected the volume to be restored on abort")
+	}
+}
+
+/*
+Clock whose time only moves when advanced, or, when automatic,
+by the duration of every timer, which then expires at once
+*/
+type fakeClock struct {
+	automatic bool
+	now       time.Time
+	timers    map[*fakeTimer]struct{}
+	mutex     sync.Mutex
+}
+
+type fakeTimer struct {
+	deadline time.Time
+	channel  chan time.Time
+}
+
+func newFakeClock(automatic bool) *fakeClock {
+	return &fakeClock{
+		automatic: automatic,
+		now:       time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+		timers:    make(map[*fakeTimer]struct{}),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Timer(duration time.Duration) (<-chan time.Time, func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	timer := &fakeTimer{deadline: c.now.Add(duration), channel: make(chan time.Time, 1)}
+	if c.automatic {
+		c.now = timer.deadline
+		timer.channel <- c.now
+		return timer.channel, func() {}
+	}
+	c.timers[timer] = struct{}{}
+	return timer.channel, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		delete(c.timers, timer)
+	}
+}
+
+/*
+Moves the time forward, expiring the timers due
+*/
+func (c *fakeClock) advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+	for timer := range c.timers {
+		if !timer.deadline.After(c.now) {
+			timer.channel <- c.now
+			delete(c.timers, timer)
+		}
+	}
+}
+
+/*
+Waits until a number of timers are pending
+*/
+func (c *fakeClock) blockUntil(count int) {
+	for {
+		c.mutex.Lock()
+		pending := len(c.timers)
+		c.mutex.Unlock()
+		if pending == count {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+/*
+Creates a connected device replaying request and reply pairs
+*/
+func replayDevice(t *testing.T, pairs ...string) *protocol.MKS937B {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer, err := framelog.NewWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, data := range pairs {
+		direction := framelog.Sent
+		if idx%2 == 1 {
+			direction = framelog.Received
+		}
+		writer.Write(framelog.Frame{Time: time.Now(), Direction: direction, Data: []byte(data)})
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := framelog.NewReplay(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := &protocol.MKS937B{Communication: replay, Address: 1}
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return device
+}
@@ -0,0 +1,185 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 17th, 2026
+Last update: October 17th, 2026
+*/
+
+package sequencer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devicehub-go/mks-937b/protocol"
+)
+
+/*
+Step that waits for a fixed period
+*/
+func Wait(duration time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("Wait %s", duration),
+		Run: func(run *Run) error {
+			return run.Wait(duration)
+		},
+	}
+}
+
+/*
+Step that turns the power of a gauge ON or OFF
+*/
+func Power(channel int, status bool) Step {
+	return Step{
+		Name: fmt.Sprintf("Power channel %d %s", channel, onOff(status)),
+		Run: func(run *Run) error {
+			return run.Device.SetPowerStatus(channel, status)
+		},
+	}
+}
+
+/*
+Step that changes the control mode (AUTO, SAFE or OFF) of a
+gauge on channel 1, 3 or 5
+*/
+func ControlMode(channel int, mode string) Step {
+	return Step{
+		Name: fmt.Sprintf("Set channel %d control mode to %s", channel, mode),
+		Run: func(run *Run) error {
+			return run.Device.SetControlMode(channel, mode)
+		},
+	}
+}
+
+/*
+Step that degasses a Hot Cathode for a period. Since a degas
+cycle lasts the degas time of the channel (5 to 240 minutes,
+see SetDegasTime), degas is started again whenever the
+controller ends a cycle before the period is over.
+
+Pausing the recipe does not stop a degas cycle in progress,
+but the paused time is not counted in the period
+*/
+func Degas(channel int, duration time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("Degas channel %d for %s", channel, duration),
+		Run: func(run *Run) error {
+			for remaining := duration; remaining > 0; {
+				status, err := run.Device.GetDegasStatus(channel)
+				if err != nil {
+					return err
+				}
+				if !status {
+					if err := run.Device.SetDegasStatus(channel, true); err != nil {
+						return err
+					}
+				}
+				wait := min(remaining, 5*time.Second)
+				if err := run.Wait(wait); err != nil {
+					run.Device.SetDegasStatus(channel, false)
+					return err
+				}
+				remaining -= wait
+			}
+			return run.Device.SetDegasStatus(channel, false)
+		},
+	}
+}
+
+/*
+Step that waits until the pressure of a channel falls below a
+target, in the device unit. A zero timeout waits forever
+*/
+func WaitPressureBelow(channel int, target float64, timeout time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("Wait channel %d pressure below %.2E", channel, target),
+		Run: func(run *Run) error {
+			start := run.Now()
+			for {
+				reading, err := run.Device.GetPressure(channel)
+				if err != nil {
+					return err
+				}
+				if reading.Status == "OK" && reading.Value < target {
+					return nil
+				}
+				if timeout > 0 && run.Now().Sub(start) > timeout {
+					return fmt.Errorf("channel %d pressure did not fall below %.2E within %s", channel, target, timeout)
+				}
+				if err := run.Wait(time.Second); err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
+
+/*
+Step that polls a condition every interval until it is true.
+The step Timeout bounds the wait
+*/
+func WaitUntil(name string, condition func(run *Run) (bool, error), interval time.Duration) Step {
+	return Step{
+		Name: name,
+		Run: func(run *Run) error {
+			for {
+				ok, err := condition(run)
+				if err != nil || ok {
+					return err
+				}
+				if err := run.Wait(interval); err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
+
+/*
+Condition true when the pressure of a channel is below a
+target, in the device unit
+*/
+func PressureBelow(channel int, target float64) func(run *Run) (bool, error) {
+	return func(run *Run) (bool, error) {
+		reading, err := run.Device.GetPressure(channel)
+		return err == nil && reading.Status == "OK" && reading.Value < target, err
+	}
+}
+
+/*
+Condition true when the pressure of a channel is above a
+target, in the device unit
+*/
+func PressureAbove(channel int, target float64) func(run *Run) (bool, error) {
+	return func(run *Run) (bool, error) {
+		reading, err := run.Device.GetPressure(channel)
+		return err == nil && reading.Status == "OK" && reading.Value > target, err
+	}
+}
+
+/*
+Step that starts a software interlock, e.g. once the gauge it
+protects is running
+*/
+func StartInterlock(interlock *protocol.Interlock) Step {
+	return Step{
+		Name: fmt.Sprintf("Start channel %d interlock", interlock.Channel),
+		Run: func(run *Run) error {
+			interlock.Start()
+			return nil
+		},
+	}
+}
+
+/*
+Step running a custom action
+*/
+func Action(name string, action func(run *Run) error) Step {
+	return Step{Name: name, Run: action}
+}
+
+func onOff(status bool) string {
+	if status {
+		return "ON"
+	}
+	return "OFF"
+}